@@ -3,50 +3,142 @@ package main
 import (
 	"context"
 	"database/sql"
+	"flag"
+	"fmt"
 	"net/http"
+	"os"
 	"strings"
 
+	_ "github.com/lib/pq" // PostgreSQL driver, since upper/db uses pgx by default
 	"github.com/maitesin/sketch/config"
+	"github.com/maitesin/sketch/internal/app"
+	"github.com/maitesin/sketch/internal/infra/ansi"
 	"github.com/maitesin/sketch/internal/infra/ascii"
+	"github.com/maitesin/sketch/internal/infra/cache"
+	"github.com/maitesin/sketch/internal/infra/html"
 	httpx "github.com/maitesin/sketch/internal/infra/http"
+	jsonx "github.com/maitesin/sketch/internal/infra/json"
+	"github.com/maitesin/sketch/internal/infra/memory"
+	"github.com/maitesin/sketch/internal/infra/migration"
+	"github.com/maitesin/sketch/internal/infra/png"
 	sqlx "github.com/maitesin/sketch/internal/infra/sql"
+	"github.com/maitesin/sketch/internal/infra/sqlite"
+	"github.com/maitesin/sketch/internal/infra/svg"
 	log "github.com/sirupsen/logrus" //nolint: depguard
+	"github.com/upper/db/v4"
 	"github.com/upper/db/v4/adapter/postgresql"
 )
 
 func main() {
 	logger := log.New()
 	logger.SetFormatter(&log.JSONFormatter{})
+
+	// run returns instead of exiting, so the connections to the DB are closed before the service exits
+	if err := run(logger); err != nil {
+		logger.Fatal(err)
+	}
+}
+
+// run starts the service, or migrates the DB when the migrate command is given. The connections opened are closed when
+// it returns
+func run(logger *log.Logger) error {
+	migrateOnStart := flag.Bool("migrate", false, "apply the pending migrations of the DB before starting the service. They are always applied to SQLite DBs")
+	flag.Parse()
+
 	ctx := httpx.ContextWithLogger(context.Background(), logger)
 
 	cfg, err := config.New()
 	if err != nil {
-		logger.Infof("Failed to generate configuration %s", err)
-		return
+		return fmt.Errorf("failed to generate configuration: %w", err)
 	}
 
-	dbConn, err := sql.Open("postgres", cfg.SQL.DatabaseURL())
-	if err != nil {
-		logger.Infof("Failed to open connection to the DB: %s\n", err)
-		return
+	var canvasRepository app.CanvasRepository
+	var snapshotRepository app.SnapshotRepository
+	var migrator *migration.Runner
+	switch cfg.Storage {
+	case config.StorageMemory:
+		canvasRepository = memory.NewCanvasRepository()
+		snapshotRepository = memory.NewSnapshotRepository()
+	case config.StorageSQLite:
+		sqliteConn, err := sqlite.Open(cfg.SQLite)
+		if err != nil {
+			return fmt.Errorf("failed to open the SQLite DB: %w", err)
+		}
+		defer sqliteConn.Close()
+
+		migrator, err = sqlite.NewMigrator(ctx, sqliteConn)
+		if err != nil {
+			return fmt.Errorf("failed to read the migrations of the SQLite DB: %w", err)
+		}
+
+		canvasRepository = newSQLCanvasRepository(cfg.EventStore, sqliteConn)
+		snapshotRepository = sqlx.NewSnapshotRepository(sqliteConn)
+	default:
+		dbConn, err := sql.Open("postgres", cfg.SQL.DatabaseURL())
+		if err != nil {
+			return fmt.Errorf("failed to open connection to the DB: %w", err)
+		}
+		defer dbConn.Close()
+
+		pgConn, err := postgresql.New(dbConn)
+		if err != nil {
+			return fmt.Errorf("failed to initialize connection with the DB: %w", err)
+		}
+		defer pgConn.Close()
+
+		migrator, err = sqlx.NewMigrator(pgConn)
+		if err != nil {
+			return fmt.Errorf("failed to read the migrations of the DB: %w", err)
+		}
+
+		canvasRepository = newSQLCanvasRepository(cfg.EventStore, pgConn)
+		snapshotRepository = sqlx.NewSnapshotRepository(pgConn)
 	}
-	defer dbConn.Close()
 
-	pgConn, err := postgresql.New(dbConn)
-	if err != nil {
-		logger.Infof("Failed to initialize connection with the DB: %s\n", err)
-		return
+	if flag.Arg(0) == "migrate" {
+		err = migrate(ctx, migrator, flag.Args()[1:], os.Stdout)
+		if err != nil {
+			return fmt.Errorf("failed to migrate the DB: %w", err)
+		}
+		return nil
 	}
-	defer pgConn.Close()
 
-	canvasRepository := sqlx.NewCanvasRepository(pgConn)
-	renderer := ascii.Renderer{}
+	// The embedded SQLite DB is created when the project starts, so its schema is created along with it
+	if (*migrateOnStart || cfg.Storage == config.StorageSQLite) && migrator != nil {
+		applied, err := migrator.Up(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to migrate the DB: %w", err)
+		}
+		for _, m := range applied {
+			logger.Infof("Applied migration %04d_%s\n", m.Version, m.Name)
+		}
+	}
+
+	snapshotter := app.NewSnapshotter(snapshotRepository, cfg.HTTP.SnapshotInterval)
+	renderers := httpx.Renderers{
+		httpx.TextMediaType: ascii.Renderer{},
+		httpx.SVGMediaType:  svg.Renderer{},
+		httpx.PNGMediaType:  png.NewRenderer(cfg.PNG),
+		httpx.ANSIMediaType: ansi.Renderer{},
+		httpx.HTMLMediaType: html.Renderer{},
+		httpx.JSONMediaType: jsonx.Renderer{},
+	}
 
 	err = http.ListenAndServe(
 		strings.Join([]string{cfg.HTTP.Host, cfg.HTTP.Port}, ":"),
-		httpx.DefaultRouter(ctx, cfg.Canvas, canvasRepository, renderer),
+		httpx.DefaultRouter(ctx, cfg.Canvas, canvasRepository, renderers, cache.NewLRU(cfg.Cache.Capacity), snapshotter),
 	)
 	if err != nil {
-		logger.Infof("Failed to start service: %s\n", err)
+		return fmt.Errorf("failed to start service: %w", err)
+	}
+	return nil
+}
+
+// newSQLCanvasRepository returns the repository that stores the canvases as events when the event store is enabled, or
+// in the canvases and tasks tables otherwise
+func newSQLCanvasRepository(cfg sqlx.EventStoreConfig, sess db.Session) app.CanvasRepository {
+	if cfg.Enabled {
+		return sqlx.NewEventCanvasRepository(sess, cfg.SnapshotInterval)
 	}
+	return sqlx.NewCanvasRepository(sess)
 }
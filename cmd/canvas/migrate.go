@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/maitesin/sketch/internal/infra/migration"
+)
+
+const migrateUsage = "usage: canvas migrate up|down|status"
+
+// migrate runs the migrate subcommand, which applies all the pending migrations of the DB, reverts the latest one
+// applied, or prints whether each one is applied
+func migrate(ctx context.Context, runner *migration.Runner, args []string, w io.Writer) error {
+	if runner == nil {
+		return errors.New("the storage has no migrations")
+	}
+	if len(args) != 1 {
+		return errors.New(migrateUsage)
+	}
+
+	switch args[0] {
+	case "up":
+		applied, err := runner.Up(ctx)
+		for _, m := range applied {
+			fmt.Fprintf(w, "Applied %04d_%s\n", m.Version, m.Name)
+		}
+		if err == nil && len(applied) == 0 {
+			fmt.Fprintln(w, "No pending migrations")
+		}
+		return err
+	case "down":
+		reverted, err := runner.Down(ctx)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "Reverted %04d_%s\n", reverted.Version, reverted.Name)
+		return nil
+	case "status":
+		statuses, err := runner.Status(ctx)
+		if err != nil {
+			return err
+		}
+
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "VERSION\tNAME\tSTATUS\tAPPLIED AT")
+		for _, status := range statuses {
+			state, appliedAt := "pending", ""
+			if status.Applied {
+				state, appliedAt = "applied", status.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Fprintf(tw, "%04d\t%s\t%s\t%s\n", status.Version, status.Name, state, appliedAt)
+		}
+		return tw.Flush()
+	default:
+		return errors.New(migrateUsage)
+	}
+}
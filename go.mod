@@ -6,7 +6,9 @@ require (
 	github.com/chi-middleware/logrus-logger v0.2.0
 	github.com/go-chi/chi v1.5.4
 	github.com/google/uuid v1.2.0
+	github.com/lib/pq v1.10.4
 	github.com/sirupsen/logrus v1.8.1
 	github.com/stretchr/testify v1.7.0
-	github.com/upper/db/v4 v4.1.0
+	github.com/upper/db/v4 v4.5.0
+	modernc.org/sqlite v1.11.2
 )
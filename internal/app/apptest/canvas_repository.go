@@ -0,0 +1,575 @@
+// Package apptest contains the tests that every implementation of the interfaces of the application layer must pass
+package apptest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/maitesin/sketch/internal/app"
+	"github.com/maitesin/sketch/internal/domain"
+	"github.com/stretchr/testify/require"
+)
+
+// NewCanvasRepository returns an empty canvas repository for a test
+type NewCanvasRepository func(t *testing.T) app.CanvasRepository
+
+// validCanvas returns a canvas with all the kinds of tasks
+func validCanvas() domain.Canvas {
+	return domain.NewCanvas(
+		uuid.New(),
+		30,
+		30,
+		[]domain.Task{
+			domain.NewDrawRectangle(
+				uuid.New(),
+				domain.NewPoint(0, 0),
+				10,
+				10,
+				'0',
+				'X',
+				time.Now().UTC(),
+			),
+			domain.NewDrawRectangle(
+				uuid.New(),
+				domain.NewPoint(10, 10),
+				4,
+				4,
+				' ',
+				'#',
+				time.Now().UTC(),
+			).WithPattern(domain.NewPattern([]string{"/\\", "\\/"}, domain.PatternAnchorShape)).WithStyles(
+				domain.NewStyle(domain.ColourYellow, domain.DefaultColour),
+				domain.NewStyle(domain.ColourWhite, domain.ColourBlue),
+			),
+			domain.NewFill(
+				uuid.New(),
+				domain.NewPoint(12, 12),
+				'-',
+				time.Now().UTC(),
+			).WithStyle(domain.NewStyle(domain.ColourRed, domain.ColourBlack)),
+			domain.NewFillWithMode(
+				uuid.New(),
+				domain.NewPoint(3, 3),
+				'~',
+				domain.EightConnected,
+				domain.BoundaryFillMode,
+				'0',
+				time.Now().UTC(),
+			).WithPattern(domain.NewPattern([]string{"xo", "ox"}, domain.PatternAnchorCanvas)),
+			domain.NewDrawLine(
+				uuid.New(),
+				domain.NewPoint(0, 29),
+				domain.NewPoint(29, 0),
+				'*',
+				time.Now().UTC(),
+			),
+			domain.NewDrawEllipse(
+				uuid.New(),
+				domain.NewPoint(15, 15),
+				5,
+				7,
+				'.',
+				'O',
+				time.Now().UTC(),
+			),
+			domain.NewDrawText(
+				uuid.New(),
+				domain.NewPoint(1, 1),
+				"Hello world",
+				5,
+				domain.AlignCenter,
+				time.Now().UTC(),
+			),
+			domain.NewDrawPolyline(
+				uuid.New(),
+				[]domain.Point{domain.NewPoint(0, 0), domain.NewPoint(5, 5), domain.NewPoint(10, 0)},
+				'*',
+				time.Now().UTC(),
+			),
+			domain.NewDrawPolygon(
+				uuid.New(),
+				[]domain.Point{domain.NewPoint(20, 20), domain.NewPoint(28, 20), domain.NewPoint(24, 24)},
+				'.',
+				'#',
+				time.Now().UTC(),
+			),
+		},
+		time.Now().UTC(),
+	)
+}
+
+func invalidCanvas() domain.Canvas {
+	return domain.NewCanvas(uuid.New(), 10, 10, []domain.Task{"I am invalid"}, time.Now().UTC())
+}
+
+// store inserts the canvas and its tasks in the repository
+func store(t *testing.T, repository app.CanvasRepository, canvas domain.Canvas) {
+	t.Helper()
+
+	err := repository.Insert(context.Background(), canvas) // To insert the canvas
+	require.NoError(t, err)
+	err = repository.Update(context.Background(), canvas) // To insert the tasks
+	require.NoError(t, err)
+}
+
+func taskIDs(t *testing.T, tasks []domain.Task) []uuid.UUID {
+	t.Helper()
+
+	ids := make([]uuid.UUID, len(tasks))
+	for i, task := range tasks {
+		id, ok := domain.TaskID(task)
+		require.True(t, ok)
+		ids[i] = id
+	}
+	return ids
+}
+
+// TestCanvasRepository runs the tests that every canvas repository must pass. Each test gets a new repository
+func TestCanvasRepository(t *testing.T, newRepository NewCanvasRepository) {
+	t.Run("Insert", func(t *testing.T) { testInsert(t, newRepository) })
+	t.Run("Update", func(t *testing.T) { testUpdate(t, newRepository) })
+	t.Run("UpdateResizedCanvas", func(t *testing.T) { testUpdateResizedCanvas(t, newRepository) })
+	t.Run("UpdateRemovedTask", func(t *testing.T) { testUpdateRemovedTask(t, newRepository) })
+	t.Run("UpdateUndoneTasks", func(t *testing.T) { testUpdateUndoneTasks(t, newRepository) })
+	t.Run("UpdateEditedTask", func(t *testing.T) { testUpdateEditedTask(t, newRepository) })
+	t.Run("UpdateTasksAddedConcurrently", func(t *testing.T) { testUpdateTasksAddedConcurrently(t, newRepository) })
+	t.Run("UpdateStaleCanvas", func(t *testing.T) { testUpdateStaleCanvas(t, newRepository) })
+	t.Run("FindByID", func(t *testing.T) { testFindByID(t, newRepository) })
+	t.Run("FindByIDTasksOrder", func(t *testing.T) { testFindByIDTasksOrder(t, newRepository) })
+	t.Run("FindByIDTasksCreatedAtTheSameTime", func(t *testing.T) { testFindByIDTasksCreatedAtTheSameTime(t, newRepository) })
+	t.Run("FindByIDChangedCanvas", func(t *testing.T) { testFindByIDChangedCanvas(t, newRepository) })
+	t.Run("FindByIDSequenceOfRemovedTasks", func(t *testing.T) { testFindByIDSequenceOfRemovedTasks(t, newRepository) })
+}
+
+func testInsert(t *testing.T, newRepository NewCanvasRepository) {
+	canvas := validCanvas()
+
+	tests := []struct {
+		name        string
+		canvas      domain.Canvas
+		fixture     domain.Canvas
+		expectedErr error
+	}{
+		{
+			name: `Given a working canvas repository and a valid canvas,
+                   when the insert method is called,
+                   then no error is returned.`,
+			canvas:  validCanvas(),
+			fixture: validCanvas(),
+		},
+		{
+			name: `Given a working canvas repository and an invalid canvas,
+                   when the insert method is called,
+                   then an error is returned.`,
+			canvas:      invalidCanvas(),
+			fixture:     validCanvas(),
+			expectedErr: errors.New(""),
+		},
+		{
+			name: `Given a working canvas repository and a valid canvas that is already present,
+                   when the insert method is called,
+                   then no error is returned.`,
+			canvas:  canvas,
+			fixture: canvas,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repository := newRepository(t)
+			store(t, repository, tt.fixture)
+
+			err := repository.Insert(context.Background(), tt.canvas)
+			if tt.expectedErr != nil {
+				require.ErrorAs(t, err, &tt.expectedErr)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+
+	t.Run(`Given a working canvas repository and a canvas that is already present,
+                   when the insert method is called with a different size,
+                   then the stored canvas does not change.`, func(t *testing.T) {
+		repository := newRepository(t)
+		canvas := validCanvas()
+		store(t, repository, canvas)
+
+		resized := canvas
+		err := resized.Resize(40, 50, domain.AnchorTopLeft, false)
+		require.NoError(t, err)
+
+		err = repository.Insert(context.Background(), resized)
+		require.NoError(t, err)
+
+		stored, err := repository.FindByID(context.Background(), canvas.ID())
+		require.NoError(t, err)
+		require.Equal(t, canvas.Height(), stored.Height())
+		require.Equal(t, canvas.Width(), stored.Width())
+		require.Equal(t, taskIDs(t, canvas.Tasks()), taskIDs(t, stored.Tasks()))
+	})
+}
+
+func testUpdate(t *testing.T, newRepository NewCanvasRepository) {
+	tests := []struct {
+		name        string
+		canvas      domain.Canvas
+		expectedErr error
+	}{
+		{
+			name: `Given a working canvas repository and a valid canvas,
+                   when the update method is called,
+                   then no error is returned.`,
+			canvas: validCanvas(),
+		},
+		{
+			name: `Given a working canvas repository and an invalid canvas,
+                   when the update method is called,
+                   then an error is returned.`,
+			canvas:      invalidCanvas(),
+			expectedErr: errors.New(""),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repository := newRepository(t)
+			fixture := domain.NewCanvas(tt.canvas.ID(), tt.canvas.Height(), tt.canvas.Width(), nil, tt.canvas.CreatedAt())
+			store(t, repository, fixture)
+
+			err := repository.Update(context.Background(), tt.canvas)
+			if tt.expectedErr != nil {
+				require.ErrorAs(t, err, &tt.expectedErr)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func testUpdateResizedCanvas(t *testing.T, newRepository NewCanvasRepository) {
+	repository := newRepository(t)
+	canvas := validCanvas()
+	store(t, repository, canvas)
+
+	err := canvas.Resize(40, 50, domain.AnchorBottomRight, false)
+	require.NoError(t, err)
+
+	err = repository.Update(context.Background(), canvas)
+	require.NoError(t, err)
+
+	stored, err := repository.FindByID(context.Background(), canvas.ID())
+	require.NoError(t, err)
+	require.Equal(t, 40, stored.Height())
+	require.Equal(t, 50, stored.Width())
+	require.Len(t, stored.Tasks(), len(canvas.Tasks()))
+
+	rectangle, ok := stored.Tasks()[0].(domain.DrawRectangle)
+	require.True(t, ok)
+	require.Equal(t, domain.NewPoint(20, 10), rectangle.Point())
+}
+
+func testUpdateRemovedTask(t *testing.T, newRepository NewCanvasRepository) {
+	repository := newRepository(t)
+	canvas := validCanvas()
+	store(t, repository, canvas)
+
+	removedID, ok := domain.TaskID(canvas.Tasks()[1])
+	require.True(t, ok)
+
+	err := canvas.RemoveTask(removedID)
+	require.NoError(t, err)
+
+	err = repository.Update(context.Background(), canvas)
+	require.NoError(t, err)
+
+	stored, err := repository.FindByID(context.Background(), canvas.ID())
+	require.NoError(t, err)
+	require.Len(t, stored.Tasks(), len(canvas.Tasks()))
+	require.NotContains(t, taskIDs(t, stored.Tasks()), removedID)
+}
+
+func testUpdateTasksAddedConcurrently(t *testing.T, newRepository NewCanvasRepository) {
+	repository := newRepository(t)
+	canvas := validCanvas()
+	store(t, repository, canvas)
+
+	// every request retrieves the canvas before any of them stores the task it added
+	first, err := repository.FindByID(context.Background(), canvas.ID())
+	require.NoError(t, err)
+	second, err := repository.FindByID(context.Background(), canvas.ID())
+	require.NoError(t, err)
+	third, err := repository.FindByID(context.Background(), canvas.ID())
+	require.NoError(t, err)
+
+	rectangle := domain.NewDrawRectangle(uuid.New(), domain.NewPoint(1, 1), 3, 3, '.', '#', time.Now().UTC())
+	err = first.AddDrawRectangle(rectangle)
+	require.NoError(t, err)
+	err = repository.Update(context.Background(), first)
+	require.NoError(t, err)
+
+	// the tasks added by the other requests got the same sequence, in the same table and in another one
+	err = second.AddDrawRectangle(domain.NewDrawRectangle(uuid.New(), domain.NewPoint(2, 2), 3, 3, '.', '#', time.Now().UTC()))
+	require.NoError(t, err)
+	err = repository.Update(context.Background(), second)
+	require.ErrorAs(t, err, &app.CanvasConflict{})
+
+	err = third.AddFill(domain.NewFill(uuid.New(), domain.NewPoint(5, 5), '-', time.Now().UTC()))
+	require.NoError(t, err)
+	err = repository.Update(context.Background(), third)
+	require.ErrorAs(t, err, &app.CanvasConflict{})
+
+	stored, err := repository.FindByID(context.Background(), canvas.ID())
+	require.NoError(t, err)
+	require.Equal(t, taskIDs(t, first.Tasks()), taskIDs(t, stored.Tasks()))
+}
+
+func testUpdateStaleCanvas(t *testing.T, newRepository NewCanvasRepository) {
+	repository := newRepository(t)
+	canvas := validCanvas()
+	store(t, repository, canvas)
+
+	stale, err := repository.FindByID(context.Background(), canvas.ID())
+	require.NoError(t, err)
+
+	// the first task is edited and the last one removed after the stale canvas was retrieved
+	found, err := repository.FindByID(context.Background(), canvas.ID())
+	require.NoError(t, err)
+	rectangle, ok := found.Tasks()[0].(domain.DrawRectangle)
+	require.True(t, ok)
+	edited := domain.NewDrawRectangle(rectangle.ID(), domain.NewPoint(3, 4), 5, 6, '.', '#', time.Now().UTC())
+	err = found.UpdateTask(edited)
+	require.NoError(t, err)
+	removedID, ok := domain.TaskID(found.Tasks()[len(found.Tasks())-1])
+	require.True(t, ok)
+	err = found.RemoveTask(removedID)
+	require.NoError(t, err)
+	err = repository.Update(context.Background(), found)
+	require.NoError(t, err)
+
+	// the stale canvas does not undo the edit, nor brings back the task removed
+	line := domain.NewDrawLine(uuid.New(), domain.NewPoint(0, 0), domain.NewPoint(29, 29), '\\', time.Now().UTC())
+	err = stale.AddDrawLine(line)
+	require.NoError(t, err)
+	err = repository.Update(context.Background(), stale)
+	require.NoError(t, err)
+
+	stored, err := repository.FindByID(context.Background(), canvas.ID())
+	require.NoError(t, err)
+	require.Equal(t, append(taskIDs(t, found.Tasks()), line.ID()), taskIDs(t, stored.Tasks()))
+	storedRectangle, ok := stored.Tasks()[0].(domain.DrawRectangle)
+	require.True(t, ok)
+	require.Equal(t, edited.Point(), storedRectangle.Point())
+
+	// and it cannot edit the task removed
+	err = stale.UpdateTask(domain.NewDrawPolygon(removedID, []domain.Point{domain.NewPoint(0, 0), domain.NewPoint(5, 0), domain.NewPoint(0, 5)}, '.', '#', time.Now().UTC()))
+	require.NoError(t, err)
+	err = repository.Update(context.Background(), stale)
+	require.ErrorAs(t, err, &app.CanvasConflict{})
+}
+
+func testUpdateUndoneTasks(t *testing.T, newRepository NewCanvasRepository) {
+	repository := newRepository(t)
+	canvas := validCanvas()
+
+	err := repository.Insert(context.Background(), canvas)
+	require.NoError(t, err)
+
+	err = canvas.Undo()
+	require.NoError(t, err)
+	err = canvas.Undo()
+	require.NoError(t, err)
+
+	err = repository.Update(context.Background(), canvas)
+	require.NoError(t, err)
+
+	stored, err := repository.FindByID(context.Background(), canvas.ID())
+	require.NoError(t, err)
+	require.Len(t, stored.History(), len(canvas.History()))
+	require.Equal(t, canvas.Cursor(), stored.Cursor())
+	require.Len(t, stored.Tasks(), len(canvas.History())-2)
+}
+
+func testUpdateEditedTask(t *testing.T, newRepository NewCanvasRepository) {
+	repository := newRepository(t)
+	canvas := validCanvas()
+	store(t, repository, canvas)
+
+	rectangle, ok := canvas.Tasks()[0].(domain.DrawRectangle)
+	require.True(t, ok)
+
+	edited := domain.NewDrawRectangle(rectangle.ID(), domain.NewPoint(3, 4), 5, 6, '.', '#', time.Now().UTC())
+	err := canvas.UpdateTask(edited)
+	require.NoError(t, err)
+
+	err = repository.Update(context.Background(), canvas)
+	require.NoError(t, err)
+
+	stored, err := repository.FindByID(context.Background(), canvas.ID())
+	require.NoError(t, err)
+	require.Len(t, stored.Tasks(), len(canvas.Tasks()))
+
+	storedRectangle, ok := stored.Tasks()[0].(domain.DrawRectangle)
+	require.True(t, ok)
+	require.Equal(t, rectangle.ID(), storedRectangle.ID())
+	require.Equal(t, edited.Point(), storedRectangle.Point())
+	require.Equal(t, edited.Height(), storedRectangle.Height())
+	require.Equal(t, edited.Width(), storedRectangle.Width())
+	require.Equal(t, edited.Filler(), storedRectangle.Filler())
+	require.Equal(t, edited.Outline(), storedRectangle.Outline())
+}
+
+func testFindByID(t *testing.T, newRepository NewCanvasRepository) {
+	canvas := validCanvas()
+
+	tests := []struct {
+		name        string
+		canvas      domain.Canvas
+		fixture     domain.Canvas
+		expectedErr error
+	}{
+		{
+			name: `Given a working canvas repository and a valid canvas that already exists,
+                   when the find by id method is called,
+                   then no error is returned.`,
+			canvas:  canvas,
+			fixture: canvas,
+		},
+		{
+			name: `Given a working canvas repository and a valid canvas that does not exist,
+                   when the find by id method is called,
+                   then a canvas not found error is returned.`,
+			canvas:      validCanvas(),
+			fixture:     validCanvas(),
+			expectedErr: app.CanvasNotFound{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repository := newRepository(t)
+			store(t, repository, tt.fixture)
+
+			_, err := repository.FindByID(context.Background(), tt.canvas.ID())
+			if tt.expectedErr != nil {
+				require.ErrorAs(t, err, &tt.expectedErr)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func testFindByIDTasksOrder(t *testing.T, newRepository NewCanvasRepository) {
+	repository := newRepository(t)
+	canvas := validCanvas()
+	store(t, repository, canvas)
+
+	err := canvas.AddDrawLine(domain.NewDrawLine(uuid.New(), domain.NewPoint(0, 0), domain.NewPoint(29, 29), '\\', time.Now().UTC()))
+	require.NoError(t, err)
+
+	err = repository.Update(context.Background(), canvas)
+	require.NoError(t, err)
+
+	stored, err := repository.FindByID(context.Background(), canvas.ID())
+	require.NoError(t, err)
+	require.Equal(t, taskIDs(t, canvas.Tasks()), taskIDs(t, stored.Tasks()))
+}
+
+func testFindByIDTasksCreatedAtTheSameTime(t *testing.T, newRepository NewCanvasRepository) {
+	repository := newRepository(t)
+	canvas := domain.NewCanvas(uuid.New(), 30, 30, nil, time.Now().UTC())
+	err := repository.Insert(context.Background(), canvas)
+	require.NoError(t, err)
+
+	// the tasks are added in a different order than the one of their types
+	createdAt := time.Now().UTC()
+	err = canvas.AddDrawLine(domain.NewDrawLine(uuid.New(), domain.NewPoint(0, 0), domain.NewPoint(29, 29), '\\', createdAt))
+	require.NoError(t, err)
+	err = canvas.AddFill(domain.NewFill(uuid.New(), domain.NewPoint(12, 12), '-', createdAt))
+	require.NoError(t, err)
+	err = canvas.AddDrawRectangle(domain.NewDrawRectangle(uuid.New(), domain.NewPoint(0, 0), 10, 10, '0', 'X', createdAt))
+	require.NoError(t, err)
+
+	err = repository.Update(context.Background(), canvas)
+	require.NoError(t, err)
+
+	stored, err := repository.FindByID(context.Background(), canvas.ID())
+	require.NoError(t, err)
+	require.Equal(t, taskIDs(t, canvas.Tasks()), taskIDs(t, stored.Tasks()))
+
+	// the tasks added to the stored canvas follow the stored ones
+	err = stored.AddDrawEllipse(domain.NewDrawEllipse(uuid.New(), domain.NewPoint(15, 15), 5, 7, '.', 'O', createdAt))
+	require.NoError(t, err)
+	sequence, ok := domain.TaskSequence(stored.Tasks()[3])
+	require.True(t, ok)
+	require.Equal(t, 4, sequence)
+}
+
+func testFindByIDChangedCanvas(t *testing.T, newRepository NewCanvasRepository) {
+	repository := newRepository(t)
+	canvas := validCanvas()
+	store(t, repository, canvas)
+
+	found, err := repository.FindByID(context.Background(), canvas.ID())
+	require.NoError(t, err)
+
+	err = found.Undo()
+	require.NoError(t, err)
+	err = found.AddDrawLine(domain.NewDrawLine(uuid.New(), domain.NewPoint(0, 0), domain.NewPoint(29, 29), '\\', time.Now().UTC()))
+	require.NoError(t, err)
+
+	stored, err := repository.FindByID(context.Background(), canvas.ID())
+	require.NoError(t, err)
+	require.Equal(t, taskIDs(t, canvas.History()), taskIDs(t, stored.History()))
+	require.Equal(t, canvas.Cursor(), stored.Cursor())
+}
+
+func testFindByIDSequenceOfRemovedTasks(t *testing.T, newRepository NewCanvasRepository) {
+	repository := newRepository(t)
+	canvas := validCanvas()
+	store(t, repository, canvas)
+
+	stale, err := repository.FindByID(context.Background(), canvas.ID())
+	require.NoError(t, err)
+
+	// a task is added and then removed, so it is the last task when it is removed
+	found, err := repository.FindByID(context.Background(), canvas.ID())
+	require.NoError(t, err)
+	line := domain.NewDrawLine(uuid.New(), domain.NewPoint(0, 0), domain.NewPoint(29, 29), '\\', time.Now().UTC())
+	err = found.AddDrawLine(line)
+	require.NoError(t, err)
+	removedSequence := found.Sequence()
+	err = repository.Update(context.Background(), found)
+	require.NoError(t, err)
+
+	found, err = repository.FindByID(context.Background(), canvas.ID())
+	require.NoError(t, err)
+	err = found.RemoveTask(line.ID())
+	require.NoError(t, err)
+	err = repository.Update(context.Background(), found)
+	require.NoError(t, err)
+
+	// the task added after removing the last one gets a higher sequence than it
+	found, err = repository.FindByID(context.Background(), canvas.ID())
+	require.NoError(t, err)
+	require.Equal(t, removedSequence, found.Sequence())
+	err = found.AddDrawLine(domain.NewDrawLine(uuid.New(), domain.NewPoint(0, 29), domain.NewPoint(29, 0), '/', time.Now().UTC()))
+	require.NoError(t, err)
+	sequence, ok := domain.TaskSequence(found.Tasks()[len(found.Tasks())-1])
+	require.True(t, ok)
+	require.Greater(t, sequence, removedSequence)
+	err = repository.Update(context.Background(), found)
+	require.NoError(t, err)
+
+	stored, err := repository.FindByID(context.Background(), canvas.ID())
+	require.NoError(t, err)
+	require.Equal(t, sequence, stored.Sequence())
+
+	// and the task added to a canvas retrieved before, which got the sequence of the task removed, conflicts
+	err = stale.AddDrawLine(domain.NewDrawLine(uuid.New(), domain.NewPoint(0, 15), domain.NewPoint(29, 15), '-', time.Now().UTC()))
+	require.NoError(t, err)
+	require.Equal(t, removedSequence, stale.Sequence())
+	err = repository.Update(context.Background(), stale)
+	require.ErrorAs(t, err, &app.CanvasConflict{})
+}
@@ -15,6 +15,7 @@ type Command interface {
 	Name() string
 }
 
+//go:generate moq -out zmock_command_handler_test.go -pkg app_test . CommandHandler
 //go:generate moq -out ../infra/http/zmock_command_test.go -pkg http_test . CommandHandler
 
 // CommandHandler defines the interface of the handler to run commands
@@ -22,9 +23,11 @@ type CommandHandler interface {
 	Handle(ctx context.Context, cmd Command) error
 }
 
-// CreateCanvasCmd is a VTO
+// CreateCanvasCmd is a VTO. Height and width are optional, when they are zero the default ones are used
 type CreateCanvasCmd struct {
-	ID uuid.UUID
+	ID     uuid.UUID
+	Height int
+	Width  int
 }
 
 // Name returns the name of the command to create a canvas
@@ -34,17 +37,15 @@ func (c CreateCanvasCmd) Name() string {
 
 // CreateCanvasHandler is the handler to create a canvas
 type CreateCanvasHandler struct {
-	repository   CanvasRepository
-	canvasHeight int
-	canvasWidth  int
+	repository CanvasRepository
+	cfg        Config
 }
 
 // NewCreateCanvasHandler is a constructor
-func NewCreateCanvasHandler(repository CanvasRepository, height, width int) CreateCanvasHandler {
+func NewCreateCanvasHandler(repository CanvasRepository, cfg Config) CreateCanvasHandler {
 	return CreateCanvasHandler{
-		repository:   repository,
-		canvasHeight: height,
-		canvasWidth:  width,
+		repository: repository,
+		cfg:        cfg,
 	}
 }
 
@@ -55,10 +56,24 @@ func (c CreateCanvasHandler) Handle(ctx context.Context, cmd Command) error {
 		return InvalidCommandError{Expected: CreateCanvasCmd{}, Received: cmd}
 	}
 
+	height := c.cfg.Height
+	if createCmd.Height != 0 {
+		height = createCmd.Height
+	}
+
+	width := c.cfg.Width
+	if createCmd.Width != 0 {
+		width = createCmd.Width
+	}
+
+	if !c.cfg.ValidSize(height, width) {
+		return InvalidCanvasSizeError{Height: height, Width: width, Config: c.cfg}
+	}
+
 	canvas := domain.NewCanvas(
 		createCmd.ID,
-		c.canvasHeight,
-		c.canvasWidth,
+		height,
+		width,
 		nil,
 		time.Now().UTC(),
 	)
@@ -68,13 +83,16 @@ func (c CreateCanvasHandler) Handle(ctx context.Context, cmd Command) error {
 
 // DrawRectangleCmd is a VTO
 type DrawRectangleCmd struct {
-	CanvasID    uuid.UUID
-	RectangleID uuid.UUID
-	Point       domain.Point
-	Height      int
-	Width       int
-	Filler      rune
-	Outline     rune
+	CanvasID     uuid.UUID
+	RectangleID  uuid.UUID
+	Point        domain.Point
+	Height       int
+	Width        int
+	Filler       rune
+	Outline      rune
+	Pattern      domain.Pattern
+	FillerStyle  domain.Style
+	OutlineStyle domain.Style
 }
 
 // Name returns the name of the command to draw a rectangle in a canvas
@@ -112,7 +130,7 @@ func (d DrawRectangleHandler) Handle(ctx context.Context, cmd Command) error {
 		drawRectangleCmd.Filler,
 		drawRectangleCmd.Outline,
 		time.Now().UTC(),
-	)
+	).WithPattern(drawRectangleCmd.Pattern).WithStyles(drawRectangleCmd.FillerStyle, drawRectangleCmd.OutlineStyle)
 
 	err = canvas.AddDrawRectangle(rectangle)
 	if err != nil {
@@ -124,10 +142,15 @@ func (d DrawRectangleHandler) Handle(ctx context.Context, cmd Command) error {
 
 // AddFillCmd is a VTO
 type AddFillCmd struct {
-	CanvasID uuid.UUID
-	FillID   uuid.UUID
-	Point    domain.Point
-	Filler   rune
+	CanvasID     uuid.UUID
+	FillID       uuid.UUID
+	Point        domain.Point
+	Filler       rune
+	Connectivity domain.Connectivity
+	Mode         domain.FillMode
+	Boundary     rune
+	Pattern      domain.Pattern
+	Style        domain.Style
 }
 
 // Name returns the name of the command to add a fill task in a canvas
@@ -157,12 +180,15 @@ func (f AddFillHandler) Handle(ctx context.Context, cmd Command) error {
 		return err
 	}
 
-	fill := domain.NewFill(
+	fill := domain.NewFillWithMode(
 		addFillCmd.FillID,
 		addFillCmd.Point,
 		addFillCmd.Filler,
+		addFillCmd.Connectivity,
+		addFillCmd.Mode,
+		addFillCmd.Boundary,
 		time.Now().UTC(),
-	)
+	).WithPattern(addFillCmd.Pattern).WithStyle(addFillCmd.Style)
 
 	err = canvas.AddFill(fill)
 	if err != nil {
@@ -171,3 +197,525 @@ func (f AddFillHandler) Handle(ctx context.Context, cmd Command) error {
 
 	return f.repository.Update(ctx, canvas)
 }
+
+// DrawLineCmd is a VTO
+type DrawLineCmd struct {
+	CanvasID uuid.UUID
+	LineID   uuid.UUID
+	From     domain.Point
+	To       domain.Point
+	Outline  rune
+}
+
+// Name returns the name of the command to draw a line in a canvas
+func (c DrawLineCmd) Name() string {
+	return "drawLine"
+}
+
+// DrawLineHandler is the handler to draw a line in a canvas
+type DrawLineHandler struct {
+	repository CanvasRepository
+}
+
+// NewDrawLineHandler is a constructor
+func NewDrawLineHandler(repository CanvasRepository) DrawLineHandler {
+	return DrawLineHandler{repository: repository}
+}
+
+// Handle adds a draw line task to a canvas
+func (d DrawLineHandler) Handle(ctx context.Context, cmd Command) error {
+	drawLineCmd, ok := cmd.(DrawLineCmd)
+	if !ok {
+		return InvalidCommandError{Expected: DrawLineCmd{}, Received: cmd}
+	}
+
+	canvas, err := d.repository.FindByID(ctx, drawLineCmd.CanvasID)
+	if err != nil {
+		return err
+	}
+
+	line := domain.NewDrawLine(
+		drawLineCmd.LineID,
+		drawLineCmd.From,
+		drawLineCmd.To,
+		drawLineCmd.Outline,
+		time.Now().UTC(),
+	)
+
+	err = canvas.AddDrawLine(line)
+	if err != nil {
+		return err
+	}
+
+	return d.repository.Update(ctx, canvas)
+}
+
+// DrawEllipseCmd is a VTO
+type DrawEllipseCmd struct {
+	CanvasID  uuid.UUID
+	EllipseID uuid.UUID
+	Point     domain.Point
+	Height    int
+	Width     int
+	Filler    rune
+	Outline   rune
+}
+
+// Name returns the name of the command to draw an ellipse in a canvas
+func (c DrawEllipseCmd) Name() string {
+	return "drawEllipse"
+}
+
+// DrawEllipseHandler is the handler to draw an ellipse in a canvas
+type DrawEllipseHandler struct {
+	repository CanvasRepository
+}
+
+// NewDrawEllipseHandler is a constructor
+func NewDrawEllipseHandler(repository CanvasRepository) DrawEllipseHandler {
+	return DrawEllipseHandler{repository: repository}
+}
+
+// Handle adds a draw ellipse task to a canvas
+func (d DrawEllipseHandler) Handle(ctx context.Context, cmd Command) error {
+	drawEllipseCmd, ok := cmd.(DrawEllipseCmd)
+	if !ok {
+		return InvalidCommandError{Expected: DrawEllipseCmd{}, Received: cmd}
+	}
+
+	canvas, err := d.repository.FindByID(ctx, drawEllipseCmd.CanvasID)
+	if err != nil {
+		return err
+	}
+
+	ellipse := domain.NewDrawEllipse(
+		drawEllipseCmd.EllipseID,
+		drawEllipseCmd.Point,
+		drawEllipseCmd.Height,
+		drawEllipseCmd.Width,
+		drawEllipseCmd.Filler,
+		drawEllipseCmd.Outline,
+		time.Now().UTC(),
+	)
+
+	err = canvas.AddDrawEllipse(ellipse)
+	if err != nil {
+		return err
+	}
+
+	return d.repository.Update(ctx, canvas)
+}
+
+// DrawTextCmd is a VTO
+type DrawTextCmd struct {
+	CanvasID  uuid.UUID
+	TextID    uuid.UUID
+	Point     domain.Point
+	Text      string
+	WrapWidth int
+	Alignment domain.Alignment
+}
+
+// Name returns the name of the command to place a text in a canvas
+func (c DrawTextCmd) Name() string {
+	return "drawText"
+}
+
+// DrawTextHandler is the handler to place a text in a canvas
+type DrawTextHandler struct {
+	repository CanvasRepository
+}
+
+// NewDrawTextHandler is a constructor
+func NewDrawTextHandler(repository CanvasRepository) DrawTextHandler {
+	return DrawTextHandler{repository: repository}
+}
+
+// Handle adds a draw text task to a canvas
+func (d DrawTextHandler) Handle(ctx context.Context, cmd Command) error {
+	drawTextCmd, ok := cmd.(DrawTextCmd)
+	if !ok {
+		return InvalidCommandError{Expected: DrawTextCmd{}, Received: cmd}
+	}
+
+	canvas, err := d.repository.FindByID(ctx, drawTextCmd.CanvasID)
+	if err != nil {
+		return err
+	}
+
+	text := domain.NewDrawText(
+		drawTextCmd.TextID,
+		drawTextCmd.Point,
+		drawTextCmd.Text,
+		drawTextCmd.WrapWidth,
+		drawTextCmd.Alignment,
+		time.Now().UTC(),
+	)
+
+	err = canvas.AddDrawText(text)
+	if err != nil {
+		return err
+	}
+
+	return d.repository.Update(ctx, canvas)
+}
+
+// DrawPolylineCmd is a VTO
+type DrawPolylineCmd struct {
+	CanvasID   uuid.UUID
+	PolylineID uuid.UUID
+	Points     []domain.Point
+	Outline    rune
+}
+
+// Name returns the name of the command to draw a polyline in a canvas
+func (c DrawPolylineCmd) Name() string {
+	return "drawPolyline"
+}
+
+// DrawPolylineHandler is the handler to draw a polyline in a canvas
+type DrawPolylineHandler struct {
+	repository CanvasRepository
+}
+
+// NewDrawPolylineHandler is a constructor
+func NewDrawPolylineHandler(repository CanvasRepository) DrawPolylineHandler {
+	return DrawPolylineHandler{repository: repository}
+}
+
+// Handle adds a draw polyline task to a canvas
+func (d DrawPolylineHandler) Handle(ctx context.Context, cmd Command) error {
+	drawPolylineCmd, ok := cmd.(DrawPolylineCmd)
+	if !ok {
+		return InvalidCommandError{Expected: DrawPolylineCmd{}, Received: cmd}
+	}
+
+	canvas, err := d.repository.FindByID(ctx, drawPolylineCmd.CanvasID)
+	if err != nil {
+		return err
+	}
+
+	polyline := domain.NewDrawPolyline(
+		drawPolylineCmd.PolylineID,
+		drawPolylineCmd.Points,
+		drawPolylineCmd.Outline,
+		time.Now().UTC(),
+	)
+
+	err = canvas.AddDrawPolyline(polyline)
+	if err != nil {
+		return err
+	}
+
+	return d.repository.Update(ctx, canvas)
+}
+
+// DrawPolygonCmd is a VTO
+type DrawPolygonCmd struct {
+	CanvasID  uuid.UUID
+	PolygonID uuid.UUID
+	Points    []domain.Point
+	Filler    rune
+	Outline   rune
+}
+
+// Name returns the name of the command to draw a polygon in a canvas
+func (c DrawPolygonCmd) Name() string {
+	return "drawPolygon"
+}
+
+// DrawPolygonHandler is the handler to draw a polygon in a canvas
+type DrawPolygonHandler struct {
+	repository CanvasRepository
+}
+
+// NewDrawPolygonHandler is a constructor
+func NewDrawPolygonHandler(repository CanvasRepository) DrawPolygonHandler {
+	return DrawPolygonHandler{repository: repository}
+}
+
+// Handle adds a draw polygon task to a canvas
+func (d DrawPolygonHandler) Handle(ctx context.Context, cmd Command) error {
+	drawPolygonCmd, ok := cmd.(DrawPolygonCmd)
+	if !ok {
+		return InvalidCommandError{Expected: DrawPolygonCmd{}, Received: cmd}
+	}
+
+	canvas, err := d.repository.FindByID(ctx, drawPolygonCmd.CanvasID)
+	if err != nil {
+		return err
+	}
+
+	polygon := domain.NewDrawPolygon(
+		drawPolygonCmd.PolygonID,
+		drawPolygonCmd.Points,
+		drawPolygonCmd.Filler,
+		drawPolygonCmd.Outline,
+		time.Now().UTC(),
+	)
+
+	err = canvas.AddDrawPolygon(polygon)
+	if err != nil {
+		return err
+	}
+
+	return d.repository.Update(ctx, canvas)
+}
+
+// ResizeCanvasCmd is a VTO. Height and width are optional, when they are zero the current ones are kept
+type ResizeCanvasCmd struct {
+	CanvasID uuid.UUID
+	Height   int
+	Width    int
+	Anchor   domain.Anchor
+	Clip     bool
+}
+
+// Name returns the name of the command to resize a canvas
+func (c ResizeCanvasCmd) Name() string {
+	return "resizeCanvas"
+}
+
+// ResizeCanvasHandler is the handler to resize a canvas
+type ResizeCanvasHandler struct {
+	repository CanvasRepository
+	cfg        Config
+}
+
+// NewResizeCanvasHandler is a constructor
+func NewResizeCanvasHandler(repository CanvasRepository, cfg Config) ResizeCanvasHandler {
+	return ResizeCanvasHandler{
+		repository: repository,
+		cfg:        cfg,
+	}
+}
+
+// Handle changes the size of a canvas
+func (r ResizeCanvasHandler) Handle(ctx context.Context, cmd Command) error {
+	resizeCanvasCmd, ok := cmd.(ResizeCanvasCmd)
+	if !ok {
+		return InvalidCommandError{Expected: ResizeCanvasCmd{}, Received: cmd}
+	}
+
+	canvas, err := r.repository.FindByID(ctx, resizeCanvasCmd.CanvasID)
+	if err != nil {
+		return err
+	}
+
+	height := canvas.Height()
+	if resizeCanvasCmd.Height != 0 {
+		height = resizeCanvasCmd.Height
+	}
+
+	width := canvas.Width()
+	if resizeCanvasCmd.Width != 0 {
+		width = resizeCanvasCmd.Width
+	}
+
+	if !r.cfg.ValidSize(height, width) {
+		return InvalidCanvasSizeError{Height: height, Width: width, Config: r.cfg}
+	}
+
+	err = canvas.Resize(height, width, resizeCanvasCmd.Anchor, resizeCanvasCmd.Clip)
+	if err != nil {
+		return err
+	}
+
+	return r.repository.Update(ctx, canvas)
+}
+
+// RemoveTaskCmd is a VTO
+type RemoveTaskCmd struct {
+	CanvasID uuid.UUID
+	TaskID   uuid.UUID
+}
+
+// Name returns the name of the command to remove a task from a canvas
+func (c RemoveTaskCmd) Name() string {
+	return "removeTask"
+}
+
+// RemoveTaskHandler is the handler to remove a task from a canvas
+type RemoveTaskHandler struct {
+	repository CanvasRepository
+}
+
+// NewRemoveTaskHandler is a constructor
+func NewRemoveTaskHandler(repository CanvasRepository) RemoveTaskHandler {
+	return RemoveTaskHandler{repository: repository}
+}
+
+// Handle removes a task from a canvas
+func (r RemoveTaskHandler) Handle(ctx context.Context, cmd Command) error {
+	removeTaskCmd, ok := cmd.(RemoveTaskCmd)
+	if !ok {
+		return InvalidCommandError{Expected: RemoveTaskCmd{}, Received: cmd}
+	}
+
+	canvas, err := r.repository.FindByID(ctx, removeTaskCmd.CanvasID)
+	if err != nil {
+		return err
+	}
+
+	err = canvas.RemoveTask(removeTaskCmd.TaskID)
+	if err != nil {
+		return err
+	}
+
+	return r.repository.Update(ctx, canvas)
+}
+
+// UndoCmd is a VTO
+type UndoCmd struct {
+	CanvasID uuid.UUID
+}
+
+// Name returns the name of the command to undo the last task of a canvas
+func (c UndoCmd) Name() string {
+	return "undo"
+}
+
+// UndoHandler is the handler to undo the last task of a canvas
+type UndoHandler struct {
+	repository CanvasRepository
+}
+
+// NewUndoHandler is a constructor
+func NewUndoHandler(repository CanvasRepository) UndoHandler {
+	return UndoHandler{repository: repository}
+}
+
+// Handle undoes the last task performed in a canvas
+func (u UndoHandler) Handle(ctx context.Context, cmd Command) error {
+	undoCmd, ok := cmd.(UndoCmd)
+	if !ok {
+		return InvalidCommandError{Expected: UndoCmd{}, Received: cmd}
+	}
+
+	canvas, err := u.repository.FindByID(ctx, undoCmd.CanvasID)
+	if err != nil {
+		return err
+	}
+
+	err = canvas.Undo()
+	if err != nil {
+		return err
+	}
+
+	return u.repository.Update(ctx, canvas)
+}
+
+// RedoCmd is a VTO
+type RedoCmd struct {
+	CanvasID uuid.UUID
+}
+
+// Name returns the name of the command to redo the last undone task of a canvas
+func (c RedoCmd) Name() string {
+	return "redo"
+}
+
+// RedoHandler is the handler to redo the last undone task of a canvas
+type RedoHandler struct {
+	repository CanvasRepository
+}
+
+// NewRedoHandler is a constructor
+func NewRedoHandler(repository CanvasRepository) RedoHandler {
+	return RedoHandler{repository: repository}
+}
+
+// Handle performs again the last task undone in a canvas
+func (r RedoHandler) Handle(ctx context.Context, cmd Command) error {
+	redoCmd, ok := cmd.(RedoCmd)
+	if !ok {
+		return InvalidCommandError{Expected: RedoCmd{}, Received: cmd}
+	}
+
+	canvas, err := r.repository.FindByID(ctx, redoCmd.CanvasID)
+	if err != nil {
+		return err
+	}
+
+	err = canvas.Redo()
+	if err != nil {
+		return err
+	}
+
+	return r.repository.Update(ctx, canvas)
+}
+
+// UpdateTaskCmd is a VTO. Task contains the command with the new values of the task, and it must be one of the
+// commands to draw or fill in a canvas
+type UpdateTaskCmd struct {
+	CanvasID uuid.UUID
+	TaskID   uuid.UUID
+	Task     Command
+}
+
+// Name returns the name of the command to update a task in a canvas
+func (c UpdateTaskCmd) Name() string {
+	return "updateTask"
+}
+
+// UpdateTaskHandler is the handler to update a task in a canvas
+type UpdateTaskHandler struct {
+	repository CanvasRepository
+}
+
+// NewUpdateTaskHandler is a constructor
+func NewUpdateTaskHandler(repository CanvasRepository) UpdateTaskHandler {
+	return UpdateTaskHandler{repository: repository}
+}
+
+// Handle replaces a task in a canvas with the new values
+func (u UpdateTaskHandler) Handle(ctx context.Context, cmd Command) error {
+	updateTaskCmd, ok := cmd.(UpdateTaskCmd)
+	if !ok {
+		return InvalidCommandError{Expected: UpdateTaskCmd{}, Received: cmd}
+	}
+
+	task, err := newTask(updateTaskCmd.Task, updateTaskCmd.TaskID, time.Now().UTC())
+	if err != nil {
+		return err
+	}
+
+	canvas, err := u.repository.FindByID(ctx, updateTaskCmd.CanvasID)
+	if err != nil {
+		return err
+	}
+
+	err = canvas.UpdateTask(task)
+	if err != nil {
+		return err
+	}
+
+	return u.repository.Update(ctx, canvas)
+}
+
+func newTask(cmd Command, id uuid.UUID, createdAt time.Time) (domain.Task, error) {
+	if cmd == nil {
+		return nil, ErrTaskRequired
+	}
+
+	switch cmd := cmd.(type) {
+	case DrawRectangleCmd:
+		return domain.NewDrawRectangle(id, cmd.Point, cmd.Height, cmd.Width, cmd.Filler, cmd.Outline, createdAt).
+			WithPattern(cmd.Pattern).WithStyles(cmd.FillerStyle, cmd.OutlineStyle), nil
+	case AddFillCmd:
+		return domain.NewFillWithMode(id, cmd.Point, cmd.Filler, cmd.Connectivity, cmd.Mode, cmd.Boundary, createdAt).
+			WithPattern(cmd.Pattern).WithStyle(cmd.Style), nil
+	case DrawLineCmd:
+		return domain.NewDrawLine(id, cmd.From, cmd.To, cmd.Outline, createdAt), nil
+	case DrawEllipseCmd:
+		return domain.NewDrawEllipse(id, cmd.Point, cmd.Height, cmd.Width, cmd.Filler, cmd.Outline, createdAt), nil
+	case DrawTextCmd:
+		return domain.NewDrawText(id, cmd.Point, cmd.Text, cmd.WrapWidth, cmd.Alignment, createdAt), nil
+	case DrawPolylineCmd:
+		return domain.NewDrawPolyline(id, cmd.Points, cmd.Outline, createdAt), nil
+	case DrawPolygonCmd:
+		return domain.NewDrawPolygon(id, cmd.Points, cmd.Filler, cmd.Outline, createdAt), nil
+	}
+
+	return nil, InvalidCommandError{Expected: DrawRectangleCmd{}, Received: cmd}
+}
@@ -2,7 +2,9 @@ package app_test
 
 import (
 	"context"
+	"errors"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/maitesin/sketch/internal/app"
@@ -16,6 +18,17 @@ func validCreateCanvasCmd() app.Command {
 	}
 }
 
+func validConfig() app.Config {
+	return app.Config{
+		Height:    30,
+		Width:     30,
+		MinHeight: 1,
+		MaxHeight: 100,
+		MinWidth:  1,
+		MaxWidth:  100,
+	}
+}
+
 type invalidCmd struct{}
 
 func (i invalidCmd) Name() string { return "invalidCmd" }
@@ -34,6 +47,29 @@ func TestCreateCanvasHandler(t *testing.T) {
 			command:           validCreateCanvasCmd(),
 			repositoryMutator: noopRepositoryMutator,
 		},
+		{
+			name: `Given a valid command with a custom size inside the allowed range and a working canvas repository
+                   when the create canvas handler is executed
+                   then no error is returned`,
+			command:           app.CreateCanvasCmd{ID: uuid.New(), Height: 100, Width: 1},
+			repositoryMutator: noopRepositoryMutator,
+		},
+		{
+			name: `Given a valid command with a custom height bigger than the allowed one and a working canvas repository
+                   when the create canvas handler is executed
+                   then an invalid canvas size error is returned`,
+			command:           app.CreateCanvasCmd{ID: uuid.New(), Height: 101},
+			repositoryMutator: noopRepositoryMutator,
+			expectedErr:       app.InvalidCanvasSizeError{},
+		},
+		{
+			name: `Given a valid command with a custom width smaller than the allowed one and a working canvas repository
+                   when the create canvas handler is executed
+                   then an invalid canvas size error is returned`,
+			command:           app.CreateCanvasCmd{ID: uuid.New(), Width: -1},
+			repositoryMutator: noopRepositoryMutator,
+			expectedErr:       app.InvalidCanvasSizeError{},
+		},
 		{
 			name: `Given an invalid command and a working canvas repository
                    when the create canvas handler is executed
@@ -64,11 +100,12 @@ func TestCreateCanvasHandler(t *testing.T) {
 			t.Parallel()
 
 			repository := tt.repositoryMutator(validCanvasRepository())
-			handler := app.NewCreateCanvasHandler(repository, 30, 30)
+			handler := app.NewCreateCanvasHandler(repository, validConfig())
 
 			err := handler.Handle(context.Background(), tt.command)
 			if tt.expectedErr != nil {
 				require.ErrorAs(t, err, &tt.expectedErr)
+				require.IsType(t, tt.expectedErr, err)
 			} else {
 				require.NoError(t, err)
 			}
@@ -179,10 +216,12 @@ func TestDrawRectangleHandler(t *testing.T) {
 
 func validAddFillCmd() app.Command {
 	return app.AddFillCmd{
-		CanvasID: uuid.New(),
-		FillID:   uuid.New(),
-		Point:    domain.NewPoint(12, 12),
-		Filler:   '-',
+		CanvasID:     uuid.New(),
+		FillID:       uuid.New(),
+		Point:        domain.NewPoint(12, 12),
+		Filler:       '-',
+		Connectivity: domain.FourConnected,
+		Mode:         domain.FloodFillMode,
 	}
 }
 
@@ -254,6 +293,30 @@ func TestAddFillHandler(t *testing.T) {
 			repositoryMutator: noopRepositoryMutator,
 			expectedErr:       domain.ErrOutOfBounds,
 		},
+		{
+			name: `Given a valid command with a pattern and a working canvas repository
+                   when the add fill handler is executed
+                   then the fill is stored with the pattern and no error is returned`,
+			command: app.AddFillCmd{
+				CanvasID: uuid.New(),
+				FillID:   uuid.New(),
+				Point:    domain.NewPoint(12, 12),
+				Filler:   '-',
+				Pattern:  domain.NewPattern([]string{"xo", "ox"}, domain.PatternAnchorCanvas),
+			},
+			repositoryMutator: func(repository app.CanvasRepository) app.CanvasRepository {
+				return &CanvasRepositoryMock{
+					FindByIDFunc: repository.FindByID,
+					UpdateFunc: func(_ context.Context, canvas domain.Canvas) error {
+						fill, ok := canvas.Tasks()[len(canvas.Tasks())-1].(domain.Fill)
+						if !ok || fill.Pattern().IsEmpty() {
+							return errors.New("the fill was stored without the pattern")
+						}
+						return nil
+					},
+				}
+			},
+		},
 	}
 	for _, tt := range tests {
 		tt := tt
@@ -272,3 +335,1018 @@ func TestAddFillHandler(t *testing.T) {
 		})
 	}
 }
+
+func validDrawLineCmd() app.Command {
+	return app.DrawLineCmd{
+		CanvasID: uuid.New(),
+		LineID:   uuid.New(),
+		From:     domain.NewPoint(0, 0),
+		To:       domain.NewPoint(10, 20),
+		Outline:  '*',
+	}
+}
+
+func TestDrawLineHandler(t *testing.T) {
+	tests := []struct {
+		name              string
+		command           app.Command
+		repositoryMutator repositoryMutator
+		expectedErr       error
+	}{
+		{
+			name: `Given a valid command and a working canvas repository
+                   when the draw line handler is executed
+                   then no error is returned`,
+			command:           validDrawLineCmd(),
+			repositoryMutator: noopRepositoryMutator,
+		},
+		{
+			name: `Given an invalid command and a working canvas repository
+                   when the draw line handler is executed
+                   then an invalid command error returned`,
+			command:           invalidCmd{},
+			repositoryMutator: noopRepositoryMutator,
+			expectedErr:       app.InvalidCommandError{},
+		},
+		{
+			name: `Given a valid command and a canvas repository that fails the find by ID operation
+                   when the draw line handler is executed
+                   then an error is returned`,
+			command: validDrawLineCmd(),
+			repositoryMutator: func(app.CanvasRepository) app.CanvasRepository {
+				repository := &CanvasRepositoryMock{
+					FindByIDFunc: func(context.Context, uuid.UUID) (domain.Canvas, error) {
+						return domain.Canvas{}, app.CanvasNotFound{}
+					},
+				}
+				return repository
+			},
+			expectedErr: app.CanvasNotFound{},
+		},
+		{
+			name: `Given a valid command and a canvas repository that fails the update operation
+                   when the draw line handler is executed
+                   then an error is returned`,
+			command: validDrawLineCmd(),
+			repositoryMutator: func(app.CanvasRepository) app.CanvasRepository {
+				repository := &CanvasRepositoryMock{
+					FindByIDFunc: func(context.Context, uuid.UUID) (domain.Canvas, error) {
+						return domain.NewCanvas(uuid.New(), 30, 30, nil, time.Now().UTC()), nil
+					},
+					UpdateFunc: func(context.Context, domain.Canvas) error {
+						return app.CanvasNotFound{}
+					},
+				}
+				return repository
+			},
+			expectedErr: app.CanvasNotFound{},
+		},
+		{
+			name: `Given a valid command, but with a line ending outside of the canvas and a working canvas repository
+                   when the draw line handler is executed
+                   then an error is returned`,
+			command: app.DrawLineCmd{
+				CanvasID: uuid.New(),
+				LineID:   uuid.New(),
+				From:     domain.NewPoint(0, 0),
+				To:       domain.NewPoint(100, 100),
+				Outline:  '*',
+			},
+			repositoryMutator: noopRepositoryMutator,
+			expectedErr:       domain.ErrOutOfBounds,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repository := tt.repositoryMutator(validCanvasRepository())
+			handler := app.NewDrawLineHandler(repository)
+
+			err := handler.Handle(context.Background(), tt.command)
+			if tt.expectedErr != nil {
+				require.ErrorAs(t, err, &tt.expectedErr)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func validDrawEllipseCmd() app.Command {
+	return app.DrawEllipseCmd{
+		CanvasID:  uuid.New(),
+		EllipseID: uuid.New(),
+		Point:     domain.NewPoint(0, 0),
+		Height:    10,
+		Width:     10,
+		Filler:    '0',
+		Outline:   'X',
+	}
+}
+
+func TestDrawEllipseHandler(t *testing.T) {
+	tests := []struct {
+		name              string
+		command           app.Command
+		repositoryMutator repositoryMutator
+		expectedErr       error
+	}{
+		{
+			name: `Given a valid command and a working canvas repository
+                   when the draw ellipse handler is executed
+                   then no error is returned`,
+			command:           validDrawEllipseCmd(),
+			repositoryMutator: noopRepositoryMutator,
+		},
+		{
+			name: `Given an invalid command and a working canvas repository
+                   when the draw ellipse handler is executed
+                   then an invalid command error returned`,
+			command:           invalidCmd{},
+			repositoryMutator: noopRepositoryMutator,
+			expectedErr:       app.InvalidCommandError{},
+		},
+		{
+			name: `Given a valid command and a canvas repository that fails the find by ID operation
+                   when the draw ellipse handler is executed
+                   then an error is returned`,
+			command: validDrawEllipseCmd(),
+			repositoryMutator: func(app.CanvasRepository) app.CanvasRepository {
+				repository := &CanvasRepositoryMock{
+					FindByIDFunc: func(context.Context, uuid.UUID) (domain.Canvas, error) {
+						return domain.Canvas{}, app.CanvasNotFound{}
+					},
+				}
+				return repository
+			},
+			expectedErr: app.CanvasNotFound{},
+		},
+		{
+			name: `Given a valid command and a canvas repository that fails the update operation
+                   when the draw ellipse handler is executed
+                   then an error is returned`,
+			command: validDrawEllipseCmd(),
+			repositoryMutator: func(app.CanvasRepository) app.CanvasRepository {
+				repository := &CanvasRepositoryMock{
+					FindByIDFunc: func(context.Context, uuid.UUID) (domain.Canvas, error) {
+						return domain.NewCanvas(uuid.New(), 30, 30, nil, time.Now().UTC()), nil
+					},
+					UpdateFunc: func(context.Context, domain.Canvas) error {
+						return app.CanvasNotFound{}
+					},
+				}
+				return repository
+			},
+			expectedErr: app.CanvasNotFound{},
+		},
+		{
+			name: `Given a valid command, but with an ellipse too big for the canvas and a working canvas repository
+                   when the draw ellipse handler is executed
+                   then an error is returned`,
+			command: app.DrawEllipseCmd{
+				CanvasID:  uuid.New(),
+				EllipseID: uuid.New(),
+				Point:     domain.NewPoint(0, 0),
+				Height:    100,
+				Width:     100,
+				Filler:    '0',
+			},
+			repositoryMutator: noopRepositoryMutator,
+			expectedErr:       domain.ErrOutOfBounds,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repository := tt.repositoryMutator(validCanvasRepository())
+			handler := app.NewDrawEllipseHandler(repository)
+
+			err := handler.Handle(context.Background(), tt.command)
+			if tt.expectedErr != nil {
+				require.ErrorAs(t, err, &tt.expectedErr)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func validDrawTextCmd() app.Command {
+	return app.DrawTextCmd{
+		CanvasID:  uuid.New(),
+		TextID:    uuid.New(),
+		Point:     domain.NewPoint(0, 0),
+		Text:      "Hello world",
+		WrapWidth: 5,
+		Alignment: domain.AlignCenter,
+	}
+}
+
+func TestDrawTextHandler(t *testing.T) {
+	tests := []struct {
+		name              string
+		command           app.Command
+		repositoryMutator repositoryMutator
+		expectedErr       error
+	}{
+		{
+			name: `Given a valid command and a working canvas repository
+                   when the draw text handler is executed
+                   then no error is returned`,
+			command:           validDrawTextCmd(),
+			repositoryMutator: noopRepositoryMutator,
+		},
+		{
+			name: `Given an invalid command and a working canvas repository
+                   when the draw text handler is executed
+                   then an invalid command error returned`,
+			command:           invalidCmd{},
+			repositoryMutator: noopRepositoryMutator,
+			expectedErr:       app.InvalidCommandError{},
+		},
+		{
+			name: `Given a valid command and a canvas repository that fails the find by ID operation
+                   when the draw text handler is executed
+                   then an error is returned`,
+			command: validDrawTextCmd(),
+			repositoryMutator: func(app.CanvasRepository) app.CanvasRepository {
+				repository := &CanvasRepositoryMock{
+					FindByIDFunc: func(context.Context, uuid.UUID) (domain.Canvas, error) {
+						return domain.Canvas{}, app.CanvasNotFound{}
+					},
+				}
+				return repository
+			},
+			expectedErr: app.CanvasNotFound{},
+		},
+		{
+			name: `Given a valid command and a canvas repository that fails the update operation
+                   when the draw text handler is executed
+                   then an error is returned`,
+			command: validDrawTextCmd(),
+			repositoryMutator: func(app.CanvasRepository) app.CanvasRepository {
+				repository := &CanvasRepositoryMock{
+					FindByIDFunc: func(context.Context, uuid.UUID) (domain.Canvas, error) {
+						return domain.NewCanvas(uuid.New(), 30, 30, nil, time.Now().UTC()), nil
+					},
+					UpdateFunc: func(context.Context, domain.Canvas) error {
+						return app.CanvasNotFound{}
+					},
+				}
+				return repository
+			},
+			expectedErr: app.CanvasNotFound{},
+		},
+		{
+			name: `Given a valid command, but with a text too long for the canvas and a working canvas repository
+                   when the draw text handler is executed
+                   then an error is returned`,
+			command: app.DrawTextCmd{
+				CanvasID:  uuid.New(),
+				TextID:    uuid.New(),
+				Point:     domain.NewPoint(0, 0),
+				Text:      "This text is way too long to fit in the canvas",
+				Alignment: domain.AlignLeft,
+			},
+			repositoryMutator: noopRepositoryMutator,
+			expectedErr:       domain.ErrOutOfBounds,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repository := tt.repositoryMutator(validCanvasRepository())
+			handler := app.NewDrawTextHandler(repository)
+
+			err := handler.Handle(context.Background(), tt.command)
+			if tt.expectedErr != nil {
+				require.ErrorAs(t, err, &tt.expectedErr)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func validDrawPolylineCmd() app.Command {
+	return app.DrawPolylineCmd{
+		CanvasID:   uuid.New(),
+		PolylineID: uuid.New(),
+		Points:     []domain.Point{domain.NewPoint(0, 0), domain.NewPoint(10, 10), domain.NewPoint(20, 0)},
+		Outline:    '*',
+	}
+}
+
+func TestDrawPolylineHandler(t *testing.T) {
+	tests := []struct {
+		name              string
+		command           app.Command
+		repositoryMutator repositoryMutator
+		expectedErr       error
+	}{
+		{
+			name: `Given a valid command and a working canvas repository
+                   when the draw polyline handler is executed
+                   then no error is returned`,
+			command:           validDrawPolylineCmd(),
+			repositoryMutator: noopRepositoryMutator,
+		},
+		{
+			name: `Given an invalid command and a working canvas repository
+                   when the draw polyline handler is executed
+                   then an invalid command error returned`,
+			command:           invalidCmd{},
+			repositoryMutator: noopRepositoryMutator,
+			expectedErr:       app.InvalidCommandError{},
+		},
+		{
+			name: `Given a valid command and a canvas repository that fails the find by ID operation
+                   when the draw polyline handler is executed
+                   then an error is returned`,
+			command: validDrawPolylineCmd(),
+			repositoryMutator: func(app.CanvasRepository) app.CanvasRepository {
+				repository := &CanvasRepositoryMock{
+					FindByIDFunc: func(context.Context, uuid.UUID) (domain.Canvas, error) {
+						return domain.Canvas{}, app.CanvasNotFound{}
+					},
+				}
+				return repository
+			},
+			expectedErr: app.CanvasNotFound{},
+		},
+		{
+			name: `Given a valid command and a canvas repository that fails the update operation
+                   when the draw polyline handler is executed
+                   then an error is returned`,
+			command: validDrawPolylineCmd(),
+			repositoryMutator: func(app.CanvasRepository) app.CanvasRepository {
+				repository := &CanvasRepositoryMock{
+					FindByIDFunc: func(context.Context, uuid.UUID) (domain.Canvas, error) {
+						return domain.NewCanvas(uuid.New(), 30, 30, nil, time.Now().UTC()), nil
+					},
+					UpdateFunc: func(context.Context, domain.Canvas) error {
+						return app.CanvasNotFound{}
+					},
+				}
+				return repository
+			},
+			expectedErr: app.CanvasNotFound{},
+		},
+		{
+			name: `Given a valid command, but with a polyline point outside of the canvas and a working canvas repository
+                   when the draw polyline handler is executed
+                   then an error is returned`,
+			command: app.DrawPolylineCmd{
+				CanvasID:   uuid.New(),
+				PolylineID: uuid.New(),
+				Points:     []domain.Point{domain.NewPoint(0, 0), domain.NewPoint(100, 100)},
+				Outline:    '*',
+			},
+
+			repositoryMutator: noopRepositoryMutator,
+			expectedErr:       domain.ErrOutOfBounds,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repository := tt.repositoryMutator(validCanvasRepository())
+			handler := app.NewDrawPolylineHandler(repository)
+
+			err := handler.Handle(context.Background(), tt.command)
+			if tt.expectedErr != nil {
+				require.ErrorAs(t, err, &tt.expectedErr)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func validDrawPolygonCmd() app.Command {
+	return app.DrawPolygonCmd{
+		CanvasID:  uuid.New(),
+		PolygonID: uuid.New(),
+		Points:    []domain.Point{domain.NewPoint(0, 0), domain.NewPoint(10, 10), domain.NewPoint(20, 0)},
+		Filler:    '.',
+		Outline:   '#',
+	}
+}
+
+func TestDrawPolygonHandler(t *testing.T) {
+	tests := []struct {
+		name              string
+		command           app.Command
+		repositoryMutator repositoryMutator
+		expectedErr       error
+	}{
+		{
+			name: `Given a valid command and a working canvas repository
+                   when the draw polygon handler is executed
+                   then no error is returned`,
+			command:           validDrawPolygonCmd(),
+			repositoryMutator: noopRepositoryMutator,
+		},
+		{
+			name: `Given an invalid command and a working canvas repository
+                   when the draw polygon handler is executed
+                   then an invalid command error returned`,
+			command:           invalidCmd{},
+			repositoryMutator: noopRepositoryMutator,
+			expectedErr:       app.InvalidCommandError{},
+		},
+		{
+			name: `Given a valid command and a canvas repository that fails the find by ID operation
+                   when the draw polygon handler is executed
+                   then an error is returned`,
+			command: validDrawPolygonCmd(),
+			repositoryMutator: func(app.CanvasRepository) app.CanvasRepository {
+				repository := &CanvasRepositoryMock{
+					FindByIDFunc: func(context.Context, uuid.UUID) (domain.Canvas, error) {
+						return domain.Canvas{}, app.CanvasNotFound{}
+					},
+				}
+				return repository
+			},
+			expectedErr: app.CanvasNotFound{},
+		},
+		{
+			name: `Given a valid command and a canvas repository that fails the update operation
+                   when the draw polygon handler is executed
+                   then an error is returned`,
+			command: validDrawPolygonCmd(),
+			repositoryMutator: func(app.CanvasRepository) app.CanvasRepository {
+				repository := &CanvasRepositoryMock{
+					FindByIDFunc: func(context.Context, uuid.UUID) (domain.Canvas, error) {
+						return domain.NewCanvas(uuid.New(), 30, 30, nil, time.Now().UTC()), nil
+					},
+					UpdateFunc: func(context.Context, domain.Canvas) error {
+						return app.CanvasNotFound{}
+					},
+				}
+				return repository
+			},
+			expectedErr: app.CanvasNotFound{},
+		},
+		{
+			name: `Given a valid command, but with a polygon vertex outside of the canvas and a working canvas repository
+                   when the draw polygon handler is executed
+                   then an error is returned`,
+			command: app.DrawPolygonCmd{
+				CanvasID:  uuid.New(),
+				PolygonID: uuid.New(),
+				Points:    []domain.Point{domain.NewPoint(0, 0), domain.NewPoint(100, 100), domain.NewPoint(20, 0)},
+				Filler:    '.',
+				Outline:   '#',
+			},
+
+			repositoryMutator: noopRepositoryMutator,
+			expectedErr:       domain.ErrOutOfBounds,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repository := tt.repositoryMutator(validCanvasRepository())
+			handler := app.NewDrawPolygonHandler(repository)
+
+			err := handler.Handle(context.Background(), tt.command)
+			if tt.expectedErr != nil {
+				require.ErrorAs(t, err, &tt.expectedErr)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func validResizeCanvasCmd() app.Command {
+	return app.ResizeCanvasCmd{
+		CanvasID: uuid.New(),
+		Height:   40,
+		Width:    50,
+		Anchor:   domain.AnchorBottomRight,
+	}
+}
+
+func TestResizeCanvasHandler(t *testing.T) {
+	canvasWithRectangle := func(app.CanvasRepository) app.CanvasRepository {
+		repository := &CanvasRepositoryMock{
+			FindByIDFunc: func(context.Context, uuid.UUID) (domain.Canvas, error) {
+				rectangle := domain.NewDrawRectangle(uuid.New(), domain.NewPoint(20, 20), 5, 5, 'X', 'O', time.Now().UTC())
+				return domain.NewCanvas(uuid.New(), 30, 30, []domain.Task{rectangle}, time.Now().UTC()), nil
+			},
+			UpdateFunc: func(context.Context, domain.Canvas) error {
+				return nil
+			},
+		}
+		return repository
+	}
+
+	tests := []struct {
+		name              string
+		command           app.Command
+		repositoryMutator repositoryMutator
+		expectedErr       error
+	}{
+		{
+			name: `Given a valid command and a working canvas repository
+                   when the resize canvas handler is executed
+                   then no error is returned`,
+			command:           validResizeCanvasCmd(),
+			repositoryMutator: noopRepositoryMutator,
+		},
+		{
+			name: `Given a valid command that only changes the height and a working canvas repository
+                   when the resize canvas handler is executed
+                   then no error is returned`,
+			command:           app.ResizeCanvasCmd{CanvasID: uuid.New(), Height: 10, Anchor: domain.AnchorTopLeft},
+			repositoryMutator: noopRepositoryMutator,
+		},
+		{
+			name: `Given an invalid command and a working canvas repository
+                   when the resize canvas handler is executed
+                   then an invalid command error returned`,
+			command:           invalidCmd{},
+			repositoryMutator: noopRepositoryMutator,
+			expectedErr:       app.InvalidCommandError{},
+		},
+		{
+			name: `Given a valid command with a size out of the allowed range and a working canvas repository
+                   when the resize canvas handler is executed
+                   then an invalid canvas size error is returned`,
+			command:           app.ResizeCanvasCmd{CanvasID: uuid.New(), Width: 101, Anchor: domain.AnchorTopLeft},
+			repositoryMutator: noopRepositoryMutator,
+			expectedErr:       app.InvalidCanvasSizeError{},
+		},
+		{
+			name: `Given a valid command that crops a task and a canvas repository with a canvas containing that task
+                   when the resize canvas handler is executed
+                   then an out of bounds error is returned`,
+			command:           app.ResizeCanvasCmd{CanvasID: uuid.New(), Height: 10, Width: 10, Anchor: domain.AnchorTopLeft},
+			repositoryMutator: canvasWithRectangle,
+			expectedErr:       domain.ErrOutOfBounds,
+		},
+		{
+			name: `Given a valid command that crops a task with clip and a canvas repository with a canvas containing that task
+                   when the resize canvas handler is executed
+                   then no error is returned`,
+			command:           app.ResizeCanvasCmd{CanvasID: uuid.New(), Height: 10, Width: 10, Anchor: domain.AnchorTopLeft, Clip: true},
+			repositoryMutator: canvasWithRectangle,
+		},
+		{
+			name: `Given a valid command and a canvas repository that fails the find by ID operation
+                   when the resize canvas handler is executed
+                   then an error is returned`,
+			command: validResizeCanvasCmd(),
+			repositoryMutator: func(app.CanvasRepository) app.CanvasRepository {
+				repository := &CanvasRepositoryMock{
+					FindByIDFunc: func(context.Context, uuid.UUID) (domain.Canvas, error) {
+						return domain.Canvas{}, app.CanvasNotFound{}
+					},
+				}
+				return repository
+			},
+			expectedErr: app.CanvasNotFound{},
+		},
+		{
+			name: `Given a valid command and a canvas repository that fails the update operation
+                   when the resize canvas handler is executed
+                   then an error is returned`,
+			command: validResizeCanvasCmd(),
+			repositoryMutator: func(app.CanvasRepository) app.CanvasRepository {
+				repository := &CanvasRepositoryMock{
+					FindByIDFunc: func(context.Context, uuid.UUID) (domain.Canvas, error) {
+						return domain.NewCanvas(uuid.New(), 30, 30, nil, time.Now().UTC()), nil
+					},
+					UpdateFunc: func(context.Context, domain.Canvas) error {
+						return app.CanvasNotFound{}
+					},
+				}
+				return repository
+			},
+			expectedErr: app.CanvasNotFound{},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repository := tt.repositoryMutator(validCanvasRepository())
+			handler := app.NewResizeCanvasHandler(repository, validConfig())
+
+			err := handler.Handle(context.Background(), tt.command)
+			if tt.expectedErr != nil {
+				require.ErrorAs(t, err, &tt.expectedErr)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestRemoveTaskHandler(t *testing.T) {
+	taskID := uuid.New()
+	canvasWithTask := func(app.CanvasRepository) app.CanvasRepository {
+		repository := &CanvasRepositoryMock{
+			FindByIDFunc: func(context.Context, uuid.UUID) (domain.Canvas, error) {
+				fill := domain.NewFill(taskID, domain.NewPoint(1, 1), '-', time.Now().UTC())
+				return domain.NewCanvas(uuid.New(), 30, 30, []domain.Task{fill}, time.Now().UTC()), nil
+			},
+			UpdateFunc: func(_ context.Context, canvas domain.Canvas) error {
+				if len(canvas.Tasks()) != 0 || len(canvas.RemovedTasks()) != 1 {
+					return errors.New("task not removed")
+				}
+				return nil
+			},
+		}
+		return repository
+	}
+
+	tests := []struct {
+		name              string
+		command           app.Command
+		repositoryMutator repositoryMutator
+		expectedErr       error
+	}{
+		{
+			name: `Given a valid command and a working canvas repository with a canvas containing the task
+                   when the remove task handler is executed
+                   then no error is returned`,
+			command:           app.RemoveTaskCmd{CanvasID: uuid.New(), TaskID: taskID},
+			repositoryMutator: canvasWithTask,
+		},
+		{
+			name: `Given a valid command and a working canvas repository with a canvas that does not contain the task
+                   when the remove task handler is executed
+                   then a task not found error is returned`,
+			command:           app.RemoveTaskCmd{CanvasID: uuid.New(), TaskID: uuid.New()},
+			repositoryMutator: canvasWithTask,
+			expectedErr:       domain.ErrTaskNotFound,
+		},
+		{
+			name: `Given an invalid command and a working canvas repository
+                   when the remove task handler is executed
+                   then an invalid command error returned`,
+			command:           invalidCmd{},
+			repositoryMutator: noopRepositoryMutator,
+			expectedErr:       app.InvalidCommandError{},
+		},
+		{
+			name: `Given a valid command and a canvas repository that fails the find by ID operation
+                   when the remove task handler is executed
+                   then an error is returned`,
+			command: app.RemoveTaskCmd{CanvasID: uuid.New(), TaskID: taskID},
+			repositoryMutator: func(app.CanvasRepository) app.CanvasRepository {
+				repository := &CanvasRepositoryMock{
+					FindByIDFunc: func(context.Context, uuid.UUID) (domain.Canvas, error) {
+						return domain.Canvas{}, app.CanvasNotFound{}
+					},
+				}
+				return repository
+			},
+			expectedErr: app.CanvasNotFound{},
+		},
+		{
+			name: `Given a valid command and a canvas repository that fails the update operation
+                   when the remove task handler is executed
+                   then an error is returned`,
+			command: app.RemoveTaskCmd{CanvasID: uuid.New(), TaskID: taskID},
+			repositoryMutator: func(app.CanvasRepository) app.CanvasRepository {
+				repository := &CanvasRepositoryMock{
+					FindByIDFunc: func(context.Context, uuid.UUID) (domain.Canvas, error) {
+						fill := domain.NewFill(taskID, domain.NewPoint(1, 1), '-', time.Now().UTC())
+						return domain.NewCanvas(uuid.New(), 30, 30, []domain.Task{fill}, time.Now().UTC()), nil
+					},
+					UpdateFunc: func(context.Context, domain.Canvas) error {
+						return app.CanvasNotFound{}
+					},
+				}
+				return repository
+			},
+			expectedErr: app.CanvasNotFound{},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repository := tt.repositoryMutator(validCanvasRepository())
+			handler := app.NewRemoveTaskHandler(repository)
+
+			err := handler.Handle(context.Background(), tt.command)
+			if tt.expectedErr != nil {
+				require.ErrorAs(t, err, &tt.expectedErr)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func canvasWithFillRepository(cursor int, updateErr error) repositoryMutator {
+	return func(app.CanvasRepository) app.CanvasRepository {
+		return &CanvasRepositoryMock{
+			FindByIDFunc: func(context.Context, uuid.UUID) (domain.Canvas, error) {
+				fill := domain.NewFill(uuid.New(), domain.NewPoint(1, 1), '-', time.Now().UTC())
+				return domain.NewCanvasWithHistory(uuid.New(), 30, 30, []domain.Task{fill}, cursor, time.Now().UTC()), nil
+			},
+			UpdateFunc: func(context.Context, domain.Canvas) error {
+				return updateErr
+			},
+		}
+	}
+}
+
+func TestUndoHandler(t *testing.T) {
+	tests := []struct {
+		name              string
+		command           app.Command
+		repositoryMutator repositoryMutator
+		expectedErr       error
+	}{
+		{
+			name: `Given a valid command and a working canvas repository with a canvas containing a task
+                   when the undo handler is executed
+                   then no error is returned`,
+			command:           app.UndoCmd{CanvasID: uuid.New()},
+			repositoryMutator: canvasWithFillRepository(1, nil),
+		},
+		{
+			name: `Given a valid command and a working canvas repository with an empty canvas
+                   when the undo handler is executed
+                   then a nothing to undo error is returned`,
+			command:           app.UndoCmd{CanvasID: uuid.New()},
+			repositoryMutator: noopRepositoryMutator,
+			expectedErr:       domain.ErrNothingToUndo,
+		},
+		{
+			name: `Given an invalid command and a working canvas repository
+                   when the undo handler is executed
+                   then an invalid command error returned`,
+			command:           invalidCmd{},
+			repositoryMutator: noopRepositoryMutator,
+			expectedErr:       app.InvalidCommandError{},
+		},
+		{
+			name: `Given a valid command and a canvas repository that fails the find by ID operation
+                   when the undo handler is executed
+                   then an error is returned`,
+			command: app.UndoCmd{CanvasID: uuid.New()},
+			repositoryMutator: func(app.CanvasRepository) app.CanvasRepository {
+				repository := &CanvasRepositoryMock{
+					FindByIDFunc: func(context.Context, uuid.UUID) (domain.Canvas, error) {
+						return domain.Canvas{}, app.CanvasNotFound{}
+					},
+				}
+				return repository
+			},
+			expectedErr: app.CanvasNotFound{},
+		},
+		{
+			name: `Given a valid command and a canvas repository that fails the update operation
+                   when the undo handler is executed
+                   then an error is returned`,
+			command:           app.UndoCmd{CanvasID: uuid.New()},
+			repositoryMutator: canvasWithFillRepository(1, errors.New("something went wrong")),
+			expectedErr:       errors.New(""),
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repository := tt.repositoryMutator(validCanvasRepository())
+			handler := app.NewUndoHandler(repository)
+
+			err := handler.Handle(context.Background(), tt.command)
+			if tt.expectedErr != nil {
+				require.ErrorAs(t, err, &tt.expectedErr)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestRedoHandler(t *testing.T) {
+	tests := []struct {
+		name              string
+		command           app.Command
+		repositoryMutator repositoryMutator
+		expectedErr       error
+	}{
+		{
+			name: `Given a valid command and a working canvas repository with a canvas containing an undone task
+                   when the redo handler is executed
+                   then no error is returned`,
+			command:           app.RedoCmd{CanvasID: uuid.New()},
+			repositoryMutator: canvasWithFillRepository(0, nil),
+		},
+		{
+			name: `Given a valid command and a working canvas repository with a canvas without undone tasks
+                   when the redo handler is executed
+                   then a nothing to redo error is returned`,
+			command:           app.RedoCmd{CanvasID: uuid.New()},
+			repositoryMutator: noopRepositoryMutator,
+			expectedErr:       domain.ErrNothingToRedo,
+		},
+		{
+			name: `Given an invalid command and a working canvas repository
+                   when the redo handler is executed
+                   then an invalid command error returned`,
+			command:           invalidCmd{},
+			repositoryMutator: noopRepositoryMutator,
+			expectedErr:       app.InvalidCommandError{},
+		},
+		{
+			name: `Given a valid command and a canvas repository that fails the find by ID operation
+                   when the redo handler is executed
+                   then an error is returned`,
+			command: app.RedoCmd{CanvasID: uuid.New()},
+			repositoryMutator: func(app.CanvasRepository) app.CanvasRepository {
+				repository := &CanvasRepositoryMock{
+					FindByIDFunc: func(context.Context, uuid.UUID) (domain.Canvas, error) {
+						return domain.Canvas{}, app.CanvasNotFound{}
+					},
+				}
+				return repository
+			},
+			expectedErr: app.CanvasNotFound{},
+		},
+		{
+			name: `Given a valid command and a canvas repository that fails the update operation
+                   when the redo handler is executed
+                   then an error is returned`,
+			command:           app.RedoCmd{CanvasID: uuid.New()},
+			repositoryMutator: canvasWithFillRepository(0, errors.New("something went wrong")),
+			expectedErr:       errors.New(""),
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repository := tt.repositoryMutator(validCanvasRepository())
+			handler := app.NewRedoHandler(repository)
+
+			err := handler.Handle(context.Background(), tt.command)
+			if tt.expectedErr != nil {
+				require.ErrorAs(t, err, &tt.expectedErr)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestUpdateTaskHandler(t *testing.T) {
+	fillID := uuid.New()
+	canvasWithFill := func(app.CanvasRepository) app.CanvasRepository {
+		repository := &CanvasRepositoryMock{
+			FindByIDFunc: func(context.Context, uuid.UUID) (domain.Canvas, error) {
+				fill := domain.NewFill(fillID, domain.NewPoint(1, 1), '-', time.Now().UTC())
+				return domain.NewCanvas(uuid.New(), 30, 30, []domain.Task{fill}, time.Now().UTC()), nil
+			},
+			UpdateFunc: func(context.Context, domain.Canvas) error {
+				return nil
+			},
+		}
+		return repository
+	}
+
+	tests := []struct {
+		name              string
+		command           app.Command
+		repositoryMutator repositoryMutator
+		expectedErr       error
+	}{
+		{
+			name: `Given a valid command and a working canvas repository with a canvas containing the task
+                   when the update task handler is executed
+                   then no error is returned`,
+			command: app.UpdateTaskCmd{
+				CanvasID: uuid.New(),
+				TaskID:   fillID,
+				Task:     app.AddFillCmd{Point: domain.NewPoint(5, 5), Filler: '*'},
+			},
+			repositoryMutator: canvasWithFill,
+		},
+		{
+			name: `Given a valid command that moves the task out of the canvas and a working canvas repository
+                   when the update task handler is executed
+                   then an out of bounds error is returned`,
+			command: app.UpdateTaskCmd{
+				CanvasID: uuid.New(),
+				TaskID:   fillID,
+				Task:     app.AddFillCmd{Point: domain.NewPoint(50, 5), Filler: '*'},
+			},
+			repositoryMutator: canvasWithFill,
+			expectedErr:       domain.ErrOutOfBounds,
+		},
+		{
+			name: `Given a valid command that changes the type of the task and a working canvas repository
+                   when the update task handler is executed
+                   then a task type mismatch error is returned`,
+			command: app.UpdateTaskCmd{
+				CanvasID: uuid.New(),
+				TaskID:   fillID,
+				Task:     validDrawLineCmd(),
+			},
+			repositoryMutator: canvasWithFill,
+			expectedErr:       domain.ErrTaskTypeMismatch,
+		},
+		{
+			name: `Given a valid command with an unknown task ID and a working canvas repository
+                   when the update task handler is executed
+                   then a task not found error is returned`,
+			command: app.UpdateTaskCmd{
+				CanvasID: uuid.New(),
+				TaskID:   uuid.New(),
+				Task:     app.AddFillCmd{Point: domain.NewPoint(5, 5), Filler: '*'},
+			},
+			repositoryMutator: canvasWithFill,
+			expectedErr:       domain.ErrTaskNotFound,
+		},
+		{
+			name: `Given a command with an invalid task and a working canvas repository
+                   when the update task handler is executed
+                   then an invalid command error returned`,
+			command: app.UpdateTaskCmd{
+				CanvasID: uuid.New(),
+				TaskID:   fillID,
+				Task:     validCreateCanvasCmd(),
+			},
+			repositoryMutator: canvasWithFill,
+			expectedErr:       app.InvalidCommandError{},
+		},
+		{
+			name: `Given a command without a task and a working canvas repository
+                   when the update task handler is executed
+                   then a task required error is returned`,
+			command: app.UpdateTaskCmd{
+				CanvasID: uuid.New(),
+				TaskID:   fillID,
+			},
+			repositoryMutator: canvasWithFill,
+			expectedErr:       app.ErrTaskRequired,
+		},
+		{
+			name: `Given an invalid command and a working canvas repository
+                   when the update task handler is executed
+                   then an invalid command error returned`,
+			command:           invalidCmd{},
+			repositoryMutator: noopRepositoryMutator,
+			expectedErr:       app.InvalidCommandError{},
+		},
+		{
+			name: `Given a valid command and a canvas repository that fails the find by ID operation
+                   when the update task handler is executed
+                   then an error is returned`,
+			command: app.UpdateTaskCmd{
+				CanvasID: uuid.New(),
+				TaskID:   fillID,
+				Task:     app.AddFillCmd{Point: domain.NewPoint(5, 5), Filler: '*'},
+			},
+			repositoryMutator: func(app.CanvasRepository) app.CanvasRepository {
+				repository := &CanvasRepositoryMock{
+					FindByIDFunc: func(context.Context, uuid.UUID) (domain.Canvas, error) {
+						return domain.Canvas{}, app.CanvasNotFound{}
+					},
+				}
+				return repository
+			},
+			expectedErr: app.CanvasNotFound{},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repository := tt.repositoryMutator(validCanvasRepository())
+			handler := app.NewUpdateTaskHandler(repository)
+
+			err := handler.Handle(context.Background(), tt.command)
+			if tt.expectedErr != nil {
+				require.ErrorAs(t, err, &tt.expectedErr)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
@@ -4,4 +4,15 @@ package app
 type Config struct {
 	Height int
 	Width  int
+
+	MinHeight int
+	MaxHeight int
+	MinWidth  int
+	MaxWidth  int
+}
+
+// ValidSize checks if the given dimensions are in the range allowed for a canvas
+func (c Config) ValidSize(height, width int) bool {
+	return c.MinHeight <= height && height <= c.MaxHeight &&
+		c.MinWidth <= width && width <= c.MaxWidth
 }
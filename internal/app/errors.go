@@ -1,6 +1,7 @@
 package app
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/google/uuid"
@@ -10,6 +11,8 @@ const (
 	errMsgInvalidCommand = "invalid command %q received. Expected %q"
 	errMsgInvalidQuery   = "invalid query %q received. Expected %q"
 	errMsgCanvasNotFound = "canvas %q not found"
+	errMsgCanvasConflict = "canvas %q was changed by another request"
+	errMsgInvalidSize    = "canvas size %dx%d (height x width) out of the allowed range. Height must be between %d and %d, and width between %d and %d"
 )
 
 type InvalidCommandError struct {
@@ -30,6 +33,9 @@ func (iqe InvalidQueryError) Error() string {
 	return fmt.Sprintf(errMsgInvalidQuery, iqe.Received.Name(), iqe.Expected.Name())
 }
 
+// ErrTaskRequired is returned when a command to update a task does not carry the task to replace it with
+var ErrTaskRequired = errors.New("task is required")
+
 type CanvasNotFound struct {
 	ID uuid.UUID
 }
@@ -37,3 +43,27 @@ type CanvasNotFound struct {
 func (cnf CanvasNotFound) Error() string {
 	return fmt.Sprintf(errMsgCanvasNotFound, cnf.ID)
 }
+
+// CanvasConflict is returned when a canvas cannot be stored because it was changed concurrently, e.g. two tasks added
+// at the same time got the same sequence
+type CanvasConflict struct {
+	ID uuid.UUID
+}
+
+func (cc CanvasConflict) Error() string {
+	return fmt.Sprintf(errMsgCanvasConflict, cc.ID)
+}
+
+type InvalidCanvasSizeError struct {
+	Height int
+	Width  int
+	Config Config
+}
+
+func (icse InvalidCanvasSizeError) Error() string {
+	return fmt.Sprintf(errMsgInvalidSize,
+		icse.Height, icse.Width,
+		icse.Config.MinHeight, icse.Config.MaxHeight,
+		icse.Config.MinWidth, icse.Config.MaxWidth,
+	)
+}
@@ -45,3 +45,17 @@ func TestInvalidQueryError_Error(t *testing.T) {
 	require.Contains(t, err.Error(), query1.Name())
 	require.Contains(t, err.Error(), query2.Name())
 }
+
+func TestInvalidCanvasSizeError_Error(t *testing.T) {
+	t.Parallel()
+
+	err := app.InvalidCanvasSizeError{
+		Height: 2000,
+		Width:  10,
+		Config: app.Config{MinHeight: 1, MaxHeight: 1000, MinWidth: 5, MaxWidth: 500},
+	}
+
+	require.Contains(t, err.Error(), "2000x10")
+	require.Contains(t, err.Error(), "between 1 and 1000")
+	require.Contains(t, err.Error(), "between 5 and 500")
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/google/uuid"
+	"github.com/maitesin/sketch/internal/domain"
 )
 
 //go:generate moq -out zmock_query_test.go -pkg app_test . Query
@@ -52,3 +53,100 @@ func (r RetrieveCanvasHandler) Handle(ctx context.Context, query Query) (QueryRe
 
 	return r.repository.FindByID(ctx, retrieveQuery.ID)
 }
+
+// TaskType defines the kind of task performed in a canvas
+type TaskType string
+
+const (
+	RectangleTaskType TaskType = "rectangle"
+	FillTaskType      TaskType = "fill"
+	LineTaskType      TaskType = "line"
+	EllipseTaskType   TaskType = "ellipse"
+	TextTaskType      TaskType = "text"
+	PolylineTaskType  TaskType = "polyline"
+	PolygonTaskType   TaskType = "polygon"
+)
+
+// TaskTypeOf returns the type of a task, or an empty type if the task is unknown
+func TaskTypeOf(task domain.Task) TaskType {
+	switch task.(type) {
+	case domain.DrawRectangle:
+		return RectangleTaskType
+	case domain.Fill:
+		return FillTaskType
+	case domain.DrawLine:
+		return LineTaskType
+	case domain.DrawEllipse:
+		return EllipseTaskType
+	case domain.DrawText:
+		return TextTaskType
+	case domain.DrawPolyline:
+		return PolylineTaskType
+	case domain.DrawPolygon:
+		return PolygonTaskType
+	}
+
+	return ""
+}
+
+// ListTasksQuery is a VTO. When Type is not empty only the tasks of that type are listed. When Limit is zero all the
+// tasks after the Offset are listed
+type ListTasksQuery struct {
+	CanvasID uuid.UUID
+	Type     TaskType
+	Offset   int
+	Limit    int
+}
+
+// Name returns the name of the query to list the tasks of a canvas
+func (l ListTasksQuery) Name() string {
+	return "listTasks"
+}
+
+// ListTasksResponse contains a page of the tasks of a canvas, and the total number of tasks that match the query
+type ListTasksResponse struct {
+	Tasks []domain.Task
+	Total int
+}
+
+// ListTasksHandler is the handler to list the tasks of a canvas
+type ListTasksHandler struct {
+	repository CanvasRepository
+}
+
+// NewListTasksHandler is a constructor
+func NewListTasksHandler(repository CanvasRepository) ListTasksHandler {
+	return ListTasksHandler{repository: repository}
+}
+
+// Handle lists the tasks performed in a canvas in the order they are rendered
+func (l ListTasksHandler) Handle(ctx context.Context, query Query) (QueryResponse, error) {
+	listQuery, ok := query.(ListTasksQuery)
+	if !ok {
+		return nil, InvalidQueryError{Expected: ListTasksQuery{}, Received: query}
+	}
+
+	canvas, err := l.repository.FindByID(ctx, listQuery.CanvasID)
+	if err != nil {
+		return nil, err
+	}
+
+	tasks := make([]domain.Task, 0, len(canvas.Tasks()))
+	for _, task := range canvas.Tasks() {
+		if listQuery.Type == "" || TaskTypeOf(task) == listQuery.Type {
+			tasks = append(tasks, task)
+		}
+	}
+
+	total := len(tasks)
+	start := listQuery.Offset
+	if start > total {
+		start = total
+	}
+	end := total
+	if listQuery.Limit > 0 && start+listQuery.Limit < total {
+		end = start + listQuery.Limit
+	}
+
+	return ListTasksResponse{Tasks: tasks[start:end], Total: total}, nil
+}
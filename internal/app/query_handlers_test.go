@@ -97,3 +97,128 @@ func TestRetrieveCanvasHandler(t *testing.T) {
 		})
 	}
 }
+
+func TestListTasksHandler(t *testing.T) {
+	rectangle := domain.NewDrawRectangle(uuid.New(), domain.NewPoint(0, 0), 3, 3, 'X', 'O', time.Now().UTC()).WithSequence(1)
+	fill1 := domain.NewFill(uuid.New(), domain.NewPoint(5, 5), '-', time.Now().UTC()).WithSequence(2)
+	line := domain.NewDrawLine(uuid.New(), domain.NewPoint(0, 0), domain.NewPoint(5, 5), '*', time.Now().UTC()).WithSequence(3)
+	fill2 := domain.NewFill(uuid.New(), domain.NewPoint(6, 6), '+', time.Now().UTC()).WithSequence(4)
+	undone := domain.NewFill(uuid.New(), domain.NewPoint(7, 7), '.', time.Now().UTC()).WithSequence(5)
+
+	canvasWithTasks := func(app.CanvasRepository) app.CanvasRepository {
+		repository := &CanvasRepositoryMock{
+			FindByIDFunc: func(context.Context, uuid.UUID) (domain.Canvas, error) {
+				history := []domain.Task{rectangle, fill1, line, fill2, undone}
+				return domain.NewCanvasWithHistory(uuid.New(), 30, 30, history, 4, time.Now().UTC()), nil
+			},
+		}
+		return repository
+	}
+
+	tests := []struct {
+		name              string
+		query             app.Query
+		repositoryMutator repositoryMutator
+		expectedResponse  app.ListTasksResponse
+		expectedErr       error
+	}{
+		{
+			name: `Given a query without filters and a working canvas repository
+                   when the list tasks query is handled
+                   then all the tasks that are not undone are returned in order`,
+			query:             app.ListTasksQuery{CanvasID: uuid.New()},
+			repositoryMutator: canvasWithTasks,
+			expectedResponse: app.ListTasksResponse{
+				Tasks: []domain.Task{rectangle, fill1, line, fill2},
+				Total: 4,
+			},
+		},
+		{
+			name: `Given a query filtering by type and a working canvas repository
+                   when the list tasks query is handled
+                   then only the tasks of that type are returned`,
+			query:             app.ListTasksQuery{CanvasID: uuid.New(), Type: app.FillTaskType},
+			repositoryMutator: canvasWithTasks,
+			expectedResponse: app.ListTasksResponse{
+				Tasks: []domain.Task{fill1, fill2},
+				Total: 2,
+			},
+		},
+		{
+			name: `Given a query with offset and limit and a working canvas repository
+                   when the list tasks query is handled
+                   then only the tasks of that page are returned`,
+			query:             app.ListTasksQuery{CanvasID: uuid.New(), Offset: 1, Limit: 2},
+			repositoryMutator: canvasWithTasks,
+			expectedResponse: app.ListTasksResponse{
+				Tasks: []domain.Task{fill1, line},
+				Total: 4,
+			},
+		},
+		{
+			name: `Given a query with an offset bigger than the number of tasks and a working canvas repository
+                   when the list tasks query is handled
+                   then no tasks are returned`,
+			query:             app.ListTasksQuery{CanvasID: uuid.New(), Offset: 10, Limit: 2},
+			repositoryMutator: canvasWithTasks,
+			expectedResponse: app.ListTasksResponse{
+				Tasks: []domain.Task{},
+				Total: 4,
+			},
+		},
+		{
+			name: `Given an invalid query and a working canvas repository
+                   when the list tasks query is handled
+                   then an invalid query error is returned`,
+			query:             invalidQuery{},
+			repositoryMutator: noopRepositoryMutator,
+			expectedErr:       app.InvalidQueryError{},
+		},
+		{
+			name: `Given a valid query and a non-working canvas repository
+                   when the list tasks query is handled
+                   then an error is returned`,
+			query: app.ListTasksQuery{CanvasID: uuid.New()},
+			repositoryMutator: func(app.CanvasRepository) app.CanvasRepository {
+				repository := &CanvasRepositoryMock{
+					FindByIDFunc: func(_ context.Context, _ uuid.UUID) (domain.Canvas, error) {
+						return domain.Canvas{}, app.CanvasNotFound{}
+					},
+				}
+
+				return repository
+			},
+			expectedErr: app.CanvasNotFound{},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repository := tt.repositoryMutator(validCanvasRepository())
+			handler := app.NewListTasksHandler(repository)
+
+			response, err := handler.Handle(context.Background(), tt.query)
+			if tt.expectedErr != nil {
+				require.ErrorAs(t, err, &tt.expectedErr)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tt.expectedResponse, response)
+			}
+		})
+	}
+}
+
+func TestTaskTypeOf(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, app.RectangleTaskType, app.TaskTypeOf(domain.DrawRectangle{}))
+	require.Equal(t, app.FillTaskType, app.TaskTypeOf(domain.Fill{}))
+	require.Equal(t, app.LineTaskType, app.TaskTypeOf(domain.DrawLine{}))
+	require.Equal(t, app.EllipseTaskType, app.TaskTypeOf(domain.DrawEllipse{}))
+	require.Equal(t, app.TextTaskType, app.TaskTypeOf(domain.DrawText{}))
+	require.Equal(t, app.PolylineTaskType, app.TaskTypeOf(domain.DrawPolyline{}))
+	require.Equal(t, app.PolygonTaskType, app.TaskTypeOf(domain.DrawPolygon{}))
+	require.Equal(t, app.TaskType(""), app.TaskTypeOf("unknown"))
+}
@@ -0,0 +1,95 @@
+package app
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+//go:generate moq -out zmock_render_cache_test.go -pkg app_test . RenderCache
+//go:generate moq -out ../infra/http/zmock_render_cache_test.go -pkg http_test . RenderCache
+
+// RenderCache defines the interface of the store for the renderings of the canvases. Every canvas has a version that
+// changes each time the canvas is invalidated, so renderings produced from an outdated canvas are never served
+type RenderCache interface {
+	Version(canvasID uuid.UUID) uint64
+	Get(key RenderKey) ([]byte, bool)
+	Set(key RenderKey, rendering []byte)
+	Invalidate(canvasID uuid.UUID)
+	Stats() RenderCacheStats
+}
+
+// RenderKey identifies a rendering of a version of a canvas. The variant distinguishes the renderings of the same
+// version, e.g. different formats or viewports
+type RenderKey struct {
+	CanvasID uuid.UUID
+	Version  uint64
+	Variant  string
+}
+
+// RenderCacheStats is a VTO with the counters of a render cache
+type RenderCacheStats struct {
+	Hits    uint64
+	Misses  uint64
+	Entries int
+	Size    int
+}
+
+// InvalidateRenderCacheHandler is a decorator for a command handler that invalidates the renderings of the canvas
+// targeted by the command once it has been handled
+type InvalidateRenderCacheHandler struct {
+	handler CommandHandler
+	cache   RenderCache
+}
+
+// NewInvalidateRenderCacheHandler is a constructor
+func NewInvalidateRenderCacheHandler(handler CommandHandler, cache RenderCache) InvalidateRenderCacheHandler {
+	return InvalidateRenderCacheHandler{
+		handler: handler,
+		cache:   cache,
+	}
+}
+
+// Handle runs the command and invalidates the renderings of its canvas. The canvas is invalidated even when the command
+// fails, because the repository might have changed it before failing
+func (i InvalidateRenderCacheHandler) Handle(ctx context.Context, cmd Command) error {
+	err := i.handler.Handle(ctx, cmd)
+	if canvasID, ok := canvasIDOf(cmd); ok {
+		i.cache.Invalidate(canvasID)
+	}
+	return err
+}
+
+// canvasIDOf returns the ID of the canvas targeted by the command
+func canvasIDOf(cmd Command) (uuid.UUID, bool) {
+	switch cmd := cmd.(type) {
+	case CreateCanvasCmd:
+		return cmd.ID, true
+	case DrawRectangleCmd:
+		return cmd.CanvasID, true
+	case AddFillCmd:
+		return cmd.CanvasID, true
+	case DrawLineCmd:
+		return cmd.CanvasID, true
+	case DrawEllipseCmd:
+		return cmd.CanvasID, true
+	case DrawTextCmd:
+		return cmd.CanvasID, true
+	case DrawPolylineCmd:
+		return cmd.CanvasID, true
+	case DrawPolygonCmd:
+		return cmd.CanvasID, true
+	case ResizeCanvasCmd:
+		return cmd.CanvasID, true
+	case RemoveTaskCmd:
+		return cmd.CanvasID, true
+	case UndoCmd:
+		return cmd.CanvasID, true
+	case RedoCmd:
+		return cmd.CanvasID, true
+	case UpdateTaskCmd:
+		return cmd.CanvasID, true
+	default:
+		return uuid.UUID{}, false
+	}
+}
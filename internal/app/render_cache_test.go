@@ -0,0 +1,153 @@
+package app_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/maitesin/sketch/internal/app"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInvalidateRenderCacheHandler(t *testing.T) {
+	canvasID := uuid.New()
+
+	tests := []struct {
+		name                string
+		command             app.Command
+		handlerErr          error
+		expectedInvalidated []uuid.UUID
+	}{
+		{
+			name: `Given a command to create a canvas and a working command handler
+                   when the invalidate render cache handler is executed
+                   then the canvas created is invalidated`,
+			command:             app.CreateCanvasCmd{ID: canvasID},
+			expectedInvalidated: []uuid.UUID{canvasID},
+		},
+		{
+			name: `Given a command to draw a rectangle and a working command handler
+                   when the invalidate render cache handler is executed
+                   then the canvas of the rectangle is invalidated`,
+			command:             app.DrawRectangleCmd{CanvasID: canvasID},
+			expectedInvalidated: []uuid.UUID{canvasID},
+		},
+		{
+			name: `Given a command to add a fill and a working command handler
+                   when the invalidate render cache handler is executed
+                   then the canvas of the fill is invalidated`,
+			command:             app.AddFillCmd{CanvasID: canvasID},
+			expectedInvalidated: []uuid.UUID{canvasID},
+		},
+		{
+			name: `Given a command to draw a line and a working command handler
+                   when the invalidate render cache handler is executed
+                   then the canvas of the line is invalidated`,
+			command:             app.DrawLineCmd{CanvasID: canvasID},
+			expectedInvalidated: []uuid.UUID{canvasID},
+		},
+		{
+			name: `Given a command to draw an ellipse and a working command handler
+                   when the invalidate render cache handler is executed
+                   then the canvas of the ellipse is invalidated`,
+			command:             app.DrawEllipseCmd{CanvasID: canvasID},
+			expectedInvalidated: []uuid.UUID{canvasID},
+		},
+		{
+			name: `Given a command to draw a text and a working command handler
+                   when the invalidate render cache handler is executed
+                   then the canvas of the text is invalidated`,
+			command:             app.DrawTextCmd{CanvasID: canvasID},
+			expectedInvalidated: []uuid.UUID{canvasID},
+		},
+		{
+			name: `Given a command to draw a polyline and a working command handler
+                   when the invalidate render cache handler is executed
+                   then the canvas of the polyline is invalidated`,
+			command:             app.DrawPolylineCmd{CanvasID: canvasID},
+			expectedInvalidated: []uuid.UUID{canvasID},
+		},
+		{
+			name: `Given a command to draw a polygon and a working command handler
+                   when the invalidate render cache handler is executed
+                   then the canvas of the polygon is invalidated`,
+			command:             app.DrawPolygonCmd{CanvasID: canvasID},
+			expectedInvalidated: []uuid.UUID{canvasID},
+		},
+		{
+			name: `Given a command to resize a canvas and a working command handler
+                   when the invalidate render cache handler is executed
+                   then the canvas resized is invalidated`,
+			command:             app.ResizeCanvasCmd{CanvasID: canvasID},
+			expectedInvalidated: []uuid.UUID{canvasID},
+		},
+		{
+			name: `Given a command to remove a task and a working command handler
+                   when the invalidate render cache handler is executed
+                   then the canvas of the task is invalidated`,
+			command:             app.RemoveTaskCmd{CanvasID: canvasID},
+			expectedInvalidated: []uuid.UUID{canvasID},
+		},
+		{
+			name: `Given a command to undo a task and a working command handler
+                   when the invalidate render cache handler is executed
+                   then the canvas of the task is invalidated`,
+			command:             app.UndoCmd{CanvasID: canvasID},
+			expectedInvalidated: []uuid.UUID{canvasID},
+		},
+		{
+			name: `Given a command to redo a task and a working command handler
+                   when the invalidate render cache handler is executed
+                   then the canvas of the task is invalidated`,
+			command:             app.RedoCmd{CanvasID: canvasID},
+			expectedInvalidated: []uuid.UUID{canvasID},
+		},
+		{
+			name: `Given a command to update a task and a working command handler
+                   when the invalidate render cache handler is executed
+                   then the canvas of the task is invalidated`,
+			command:             app.UpdateTaskCmd{CanvasID: canvasID},
+			expectedInvalidated: []uuid.UUID{canvasID},
+		},
+		{
+			name: `Given a command to draw a rectangle and a non-working command handler
+                   when the invalidate render cache handler is executed
+                   then the error is returned and the canvas of the rectangle is invalidated`,
+			command:             app.DrawRectangleCmd{CanvasID: canvasID},
+			handlerErr:          app.CanvasNotFound{},
+			expectedInvalidated: []uuid.UUID{canvasID},
+		},
+		{
+			name: `Given an unknown command and a working command handler
+                   when the invalidate render cache handler is executed
+                   then no canvas is invalidated`,
+			command: invalidCmd{},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			handler := &CommandHandlerMock{
+				HandleFunc: func(context.Context, app.Command) error {
+					return tt.handlerErr
+				},
+			}
+			cache := &RenderCacheMock{
+				InvalidateFunc: func(uuid.UUID) {},
+			}
+
+			err := app.NewInvalidateRenderCacheHandler(handler, cache).Handle(context.Background(), tt.command)
+			require.True(t, errors.Is(err, tt.handlerErr))
+			require.Len(t, handler.HandleCalls(), 1)
+
+			var invalidated []uuid.UUID
+			for _, call := range cache.InvalidateCalls() {
+				invalidated = append(invalidated, call.CanvasID)
+			}
+			require.Equal(t, tt.expectedInvalidated, invalidated)
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package app
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/maitesin/sketch/internal/domain"
+	"github.com/maitesin/sketch/internal/infra/raster"
+)
+
+//go:generate moq -out zmock_snapshot_repository_test.go -pkg app_test . SnapshotRepository
+//go:generate moq -out ../infra/http/zmock_snapshot_repository_test.go -pkg http_test . SnapshotRepository
+
+// SnapshotRepository defines the interface of the store for the latest snapshot of each canvas
+type SnapshotRepository interface {
+	Save(ctx context.Context, canvasID uuid.UUID, snapshot raster.Snapshot) error
+	FindByCanvasID(ctx context.Context, canvasID uuid.UUID) (raster.Snapshot, error)
+	Delete(ctx context.Context, canvasID uuid.UUID) error
+}
+
+// Snapshotter replaces the tasks of a canvas drawn in its latest snapshot by the snapshot, so rendering the canvas only
+// draws the tasks added after it. A new snapshot is taken every interval tasks. When the interval is zero, snapshots
+// are disabled and the canvases are rendered from all their tasks. The canvas still has to be retrieved with all its
+// tasks, since they are needed to check that the snapshot matches them
+type Snapshotter struct {
+	repository SnapshotRepository
+	interval   int
+}
+
+// NewSnapshotter is a constructor
+func NewSnapshotter(repository SnapshotRepository, interval int) Snapshotter {
+	return Snapshotter{
+		repository: repository,
+		interval:   interval,
+	}
+}
+
+// Enabled returns whether the snapshots are taken
+func (s Snapshotter) Enabled() bool {
+	return s.interval > 0
+}
+
+// Resume returns the canvas to be rendered, starting with the latest snapshot of the canvas when it still matches its
+// tasks. It never stores nor deletes snapshots, that is left to Refresh. The canvas returned can always be rendered,
+// so when the snapshot cannot be found it is returned with all its tasks along with the error
+func (s Snapshotter) Resume(ctx context.Context, canvas domain.Canvas) (domain.Canvas, error) {
+	resumed, _, err := s.resume(ctx, canvas)
+	if errors.Is(err, errOutdatedSnapshot) {
+		return resumed, nil
+	}
+	return resumed, err
+}
+
+// Refresh deletes the snapshot of the canvas when it does not match its tasks anymore, and stores a new one when there
+// are at least interval tasks performed after the latest one
+func (s Snapshotter) Refresh(ctx context.Context, canvas domain.Canvas) error {
+	if !s.Enabled() {
+		return nil
+	}
+
+	resumed, drawn, err := s.resume(ctx, canvas)
+	switch {
+	case errors.Is(err, errOutdatedSnapshot):
+		if err := s.repository.Delete(ctx, canvas.ID()); err != nil {
+			return err
+		}
+	case err != nil:
+		return err
+	}
+
+	performed := len(canvas.Tasks())
+	next := performed - performed%s.interval
+	if next <= drawn {
+		return nil
+	}
+
+	// The snapshot is taken from the tasks not drawn yet, so the ones drawn in the previous snapshot are not drawn again
+	pending := performed - next
+	tasks := resumed.Tasks()
+	prefix := domain.NewCanvas(canvas.ID(), canvas.Height(), canvas.Width(), tasks[:len(tasks)-pending], canvas.CreatedAt())
+	snapshot, err := raster.NewSnapshot(prefix)
+	if err != nil {
+		return err
+	}
+
+	return s.repository.Save(ctx, canvas.ID(), snapshot)
+}
+
+var errOutdatedSnapshot = errors.New("snapshot does not match the tasks of the canvas")
+
+// resume returns the canvas resumed from its latest snapshot and the number of tasks drawn in it. When the snapshot
+// does not match the tasks of the canvas, errOutdatedSnapshot is returned along with the canvas with all its tasks
+func (s Snapshotter) resume(ctx context.Context, canvas domain.Canvas) (domain.Canvas, int, error) {
+	if !s.Enabled() {
+		return canvas, 0, nil
+	}
+
+	snapshot, err := s.repository.FindByCanvasID(ctx, canvas.ID())
+	switch {
+	case errors.Is(err, raster.ErrSnapshotNotFound):
+		return canvas, 0, nil
+	case err != nil:
+		return canvas, 0, err
+	}
+
+	resumed, ok := snapshot.Resume(canvas)
+	if !ok {
+		return canvas, 0, errOutdatedSnapshot
+	}
+	return resumed, snapshot.Tasks(), nil
+}
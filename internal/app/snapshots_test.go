@@ -0,0 +1,255 @@
+package app_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/maitesin/sketch/internal/app"
+	"github.com/maitesin/sketch/internal/domain"
+	"github.com/maitesin/sketch/internal/infra/raster"
+	"github.com/stretchr/testify/require"
+)
+
+// snapshotCanvas returns a canvas with five lines, so it has two snapshots when they are taken every two tasks
+func snapshotCanvas() domain.Canvas {
+	tasks := make([]domain.Task, 5)
+	for i := range tasks {
+		tasks[i] = domain.NewDrawLine(uuid.New(), domain.NewPoint(i, 0), domain.NewPoint(i, 3), rune('a'+i), time.Now().UTC())
+	}
+	return domain.NewCanvas(uuid.New(), 4, 6, tasks, time.Now().UTC())
+}
+
+func snapshotOf(t *testing.T, canvas domain.Canvas, tasks int) raster.Snapshot {
+	t.Helper()
+
+	snapshot, err := raster.NewSnapshot(domain.NewCanvas(canvas.ID(), canvas.Height(), canvas.Width(), canvas.Tasks()[:tasks], canvas.CreatedAt()))
+	require.NoError(t, err)
+	return snapshot
+}
+
+func TestSnapshotter_Resume(t *testing.T) {
+	canvas := snapshotCanvas()
+
+	tests := []struct {
+		name          string
+		interval      int
+		snapshot      func(*testing.T) (raster.Snapshot, error)
+		expectedTasks int
+		expectedErr   bool
+	}{
+		{
+			name: `Given a disabled snapshotter,
+                   when a canvas is resumed,
+                   then the canvas is returned with all its tasks`,
+			snapshot:      func(*testing.T) (raster.Snapshot, error) { return raster.Snapshot{}, errors.New("not used") },
+			expectedTasks: 5,
+		},
+		{
+			name: `Given a snapshotter and a canvas without snapshot,
+                   when the canvas is resumed,
+                   then the canvas is returned with all its tasks`,
+			interval:      2,
+			snapshot:      func(*testing.T) (raster.Snapshot, error) { return raster.Snapshot{}, raster.ErrSnapshotNotFound },
+			expectedTasks: 5,
+		},
+		{
+			name: `Given a snapshotter and a canvas with a snapshot of its first four tasks,
+                   when the canvas is resumed,
+                   then the snapshot is used`,
+			interval:      2,
+			snapshot:      func(t *testing.T) (raster.Snapshot, error) { return snapshotOf(t, canvas, 4), nil },
+			expectedTasks: 2,
+		},
+		{
+			name: `Given a snapshotter and a canvas with a snapshot of its first two tasks,
+                   when the canvas is resumed,
+                   then the snapshot is used without taking a new one`,
+			interval:      2,
+			snapshot:      func(t *testing.T) (raster.Snapshot, error) { return snapshotOf(t, canvas, 2), nil },
+			expectedTasks: 4,
+		},
+		{
+			name: `Given a snapshotter and a canvas with a snapshot that does not match its tasks,
+                   when the canvas is resumed,
+                   then the canvas is returned with all its tasks`,
+			interval:      2,
+			snapshot:      func(t *testing.T) (raster.Snapshot, error) { return snapshotOf(t, snapshotCanvas(), 2), nil },
+			expectedTasks: 5,
+		},
+		{
+			name: `Given a snapshotter with a non-working repository to find snapshots,
+                   when a canvas is resumed,
+                   then the canvas is returned with all its tasks along with the error`,
+			interval: 2,
+			snapshot: func(*testing.T) (raster.Snapshot, error) {
+				return raster.Snapshot{}, errors.New("something went wrong")
+			},
+			expectedTasks: 5,
+			expectedErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// the snapshots are never stored nor deleted when resuming, so those functions are left unset
+			repository := &SnapshotRepositoryMock{
+				FindByCanvasIDFunc: func(context.Context, uuid.UUID) (raster.Snapshot, error) {
+					return tt.snapshot(t)
+				},
+			}
+
+			resumed, err := app.NewSnapshotter(repository, tt.interval).Resume(context.Background(), canvas)
+			if tt.expectedErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+			require.Len(t, resumed.Tasks(), tt.expectedTasks)
+
+			expected, err := raster.Rasterize(canvas, raster.FullViewport(canvas))
+			require.NoError(t, err)
+			grid, err := raster.Rasterize(resumed, raster.FullViewport(resumed))
+			require.NoError(t, err)
+			require.Equal(t, expected, grid)
+		})
+	}
+}
+
+func TestSnapshotter_Refresh(t *testing.T) {
+	canvas := snapshotCanvas()
+
+	tests := []struct {
+		name                 string
+		interval             int
+		snapshot             func(*testing.T) (raster.Snapshot, error)
+		saveErr              error
+		deleteErr            error
+		expectedErr          bool
+		expectedSavedTasks   int
+		expectedDeleteCalled bool
+	}{
+		{
+			name: `Given a disabled snapshotter,
+                   when a canvas is refreshed,
+                   then no snapshot is stored`,
+			snapshot: func(*testing.T) (raster.Snapshot, error) { return raster.Snapshot{}, errors.New("not used") },
+		},
+		{
+			name: `Given a snapshotter and a canvas without snapshot,
+                   when the canvas is refreshed,
+                   then a snapshot of the first four tasks is stored`,
+			interval:           2,
+			snapshot:           func(*testing.T) (raster.Snapshot, error) { return raster.Snapshot{}, raster.ErrSnapshotNotFound },
+			expectedSavedTasks: 4,
+		},
+		{
+			name: `Given a snapshotter and a canvas with a snapshot of its first four tasks,
+                   when the canvas is refreshed,
+                   then the snapshot is kept`,
+			interval: 2,
+			snapshot: func(t *testing.T) (raster.Snapshot, error) { return snapshotOf(t, canvas, 4), nil },
+		},
+		{
+			name: `Given a snapshotter and a canvas with a snapshot of its first two tasks,
+                   when the canvas is refreshed,
+                   then a snapshot of the first four tasks is stored`,
+			interval:           2,
+			snapshot:           func(t *testing.T) (raster.Snapshot, error) { return snapshotOf(t, canvas, 2), nil },
+			expectedSavedTasks: 4,
+		},
+		{
+			name: `Given a snapshotter and a canvas with a snapshot that does not match its tasks,
+                   when the canvas is refreshed,
+                   then the snapshot is deleted and a new one is stored`,
+			interval:             2,
+			snapshot:             func(t *testing.T) (raster.Snapshot, error) { return snapshotOf(t, snapshotCanvas(), 2), nil },
+			expectedSavedTasks:   4,
+			expectedDeleteCalled: true,
+		},
+		{
+			name: `Given a snapshotter with an interval longer than the tasks of the canvas and a snapshot that does not match,
+                   when the canvas is refreshed,
+                   then the snapshot is deleted and no new one is stored`,
+			interval:             10,
+			snapshot:             func(t *testing.T) (raster.Snapshot, error) { return snapshotOf(t, snapshotCanvas(), 2), nil },
+			expectedDeleteCalled: true,
+		},
+		{
+			name: `Given a snapshotter with a non-working repository to find snapshots,
+                   when a canvas is refreshed,
+                   then an error is returned`,
+			interval: 2,
+			snapshot: func(*testing.T) (raster.Snapshot, error) {
+				return raster.Snapshot{}, errors.New("something went wrong")
+			},
+			expectedErr: true,
+		},
+		{
+			name: `Given a snapshotter with a non-working repository to store snapshots,
+                   when a canvas is refreshed,
+                   then an error is returned`,
+			interval:           2,
+			snapshot:           func(*testing.T) (raster.Snapshot, error) { return raster.Snapshot{}, raster.ErrSnapshotNotFound },
+			saveErr:            errors.New("something went wrong"),
+			expectedErr:        true,
+			expectedSavedTasks: 4,
+		},
+		{
+			name: `Given a snapshotter with a non-working repository to delete snapshots and a snapshot that does not match,
+                   when the canvas is refreshed,
+                   then an error is returned and no snapshot is stored`,
+			interval:             2,
+			snapshot:             func(t *testing.T) (raster.Snapshot, error) { return snapshotOf(t, snapshotCanvas(), 2), nil },
+			deleteErr:            errors.New("something went wrong"),
+			expectedErr:          true,
+			expectedDeleteCalled: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repository := &SnapshotRepositoryMock{
+				FindByCanvasIDFunc: func(context.Context, uuid.UUID) (raster.Snapshot, error) {
+					return tt.snapshot(t)
+				},
+				SaveFunc: func(context.Context, uuid.UUID, raster.Snapshot) error {
+					return tt.saveErr
+				},
+				DeleteFunc: func(context.Context, uuid.UUID) error {
+					return tt.deleteErr
+				},
+			}
+
+			err := app.NewSnapshotter(repository, tt.interval).Refresh(context.Background(), canvas)
+			if tt.expectedErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+			require.Equal(t, tt.expectedDeleteCalled, len(repository.DeleteCalls()) == 1)
+			if tt.expectedSavedTasks != 0 {
+				require.Len(t, repository.SaveCalls(), 1)
+				require.Equal(t, tt.expectedSavedTasks, repository.SaveCalls()[0].Snapshot.Tasks())
+
+				// the snapshot stored draws the same cells as the tasks it replaces
+				snapshot := repository.SaveCalls()[0].Snapshot
+				resumed, ok := snapshot.Resume(canvas)
+				require.True(t, ok)
+				expected, err := raster.Rasterize(canvas, raster.FullViewport(canvas))
+				require.NoError(t, err)
+				grid, err := raster.Rasterize(resumed, raster.FullViewport(resumed))
+				require.NoError(t, err)
+				require.Equal(t, expected, grid)
+			} else {
+				require.Empty(t, repository.SaveCalls())
+			}
+		})
+	}
+}
@@ -1,6 +1,8 @@
 package domain
 
 import (
+	"reflect"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -31,6 +33,119 @@ func (p Point) Y() int {
 
 type Task interface{}
 
+// PatternAnchor defines where the tile of a pattern starts repeating from
+type PatternAnchor string
+
+const (
+	// PatternAnchorCanvas repeats the tile from the origin of the canvas, so adjacent shapes share the same pattern
+	PatternAnchorCanvas PatternAnchor = "canvas"
+	// PatternAnchorShape repeats the tile from the point of the shape, so the pattern moves with the shape
+	PatternAnchorShape PatternAnchor = "shape"
+)
+
+// Pattern defines a tile of runes that is repeated to fill an area instead of a single filler rune
+type Pattern struct {
+	tile   [][]rune
+	anchor PatternAnchor
+}
+
+// Tile returns the rows of the tile
+func (p Pattern) Tile() []string {
+	if p.IsEmpty() {
+		return nil
+	}
+
+	rows := make([]string, len(p.tile))
+	for i := range p.tile {
+		rows[i] = string(p.tile[i])
+	}
+	return rows
+}
+
+// Anchor returns where the tile starts repeating from
+func (p Pattern) Anchor() PatternAnchor {
+	return p.anchor
+}
+
+// IsEmpty returns whether the pattern has no tile, so the filler rune has to be used instead
+func (p Pattern) IsEmpty() bool {
+	return len(p.tile) == 0
+}
+
+// At returns the rune of the pattern for the cell in x and y of a shape that starts in the origin
+func (p Pattern) At(x, y int, origin Point) rune {
+	if p.anchor == PatternAnchorShape {
+		x, y = x-origin.x, y-origin.y
+	}
+
+	row := p.tile[mod(y, len(p.tile))]
+	return row[mod(x, len(row))]
+}
+
+// NewPattern is a constructor. Rows without runes are ignored, so a tile without runes creates an empty pattern
+func NewPattern(tile []string, anchor PatternAnchor) Pattern {
+	var runes [][]rune
+	for _, row := range tile {
+		if row != "" {
+			runes = append(runes, []rune(row))
+		}
+	}
+	if len(runes) == 0 {
+		return Pattern{}
+	}
+
+	return Pattern{tile: runes, anchor: anchor}
+}
+
+// mod returns the modulo of a value that is always positive, so tiles repeat to the left of and above their origin too
+func mod(value, modulo int) int {
+	return (value%modulo + modulo) % modulo
+}
+
+// Colour is one of the standard colours supported by terminals. The default colour leaves the choice to the renderer
+type Colour string
+
+const (
+	DefaultColour Colour = ""
+	ColourBlack   Colour = "black"
+	ColourRed     Colour = "red"
+	ColourGreen   Colour = "green"
+	ColourYellow  Colour = "yellow"
+	ColourBlue    Colour = "blue"
+	ColourMagenta Colour = "magenta"
+	ColourCyan    Colour = "cyan"
+	ColourWhite   Colour = "white"
+)
+
+// Colours contains all the colours that can be used by a style, in the order of their ANSI codes
+var Colours = []Colour{ColourBlack, ColourRed, ColourGreen, ColourYellow, ColourBlue, ColourMagenta, ColourCyan, ColourWhite}
+
+// Style defines the colours used to draw the runes of a task
+type Style struct {
+	foreground Colour
+	background Colour
+}
+
+// Foreground returns the colour of the runes
+func (s Style) Foreground() Colour {
+	return s.foreground
+}
+
+// Background returns the colour behind the runes
+func (s Style) Background() Colour {
+	return s.background
+}
+
+// IsDefault returns whether the style uses the default colours of the renderer
+func (s Style) IsDefault() bool {
+	return s.foreground == DefaultColour && s.background == DefaultColour
+}
+
+// NewStyle is a constructor
+func NewStyle(foreground, background Colour) Style {
+	return Style{foreground: foreground, background: background}
+}
+
 // DrawRectangle defines the coordinates of how to draw a 2D rectangle
 type DrawRectangle struct {
 	id      uuid.UUID
@@ -39,8 +154,13 @@ type DrawRectangle struct {
 	width   int
 	filler  rune
 	outline rune
+	pattern Pattern
+
+	fillerStyle  Style
+	outlineStyle Style
 
 	createdAt time.Time
+	sequence  int
 }
 
 // ID returns the id of the rectangle
@@ -73,11 +193,51 @@ func (dr DrawRectangle) Outline() rune {
 	return dr.outline
 }
 
+// Pattern returns the pattern to fill the rectangle with. When it is not empty it is used instead of the filler
+func (dr DrawRectangle) Pattern() Pattern {
+	return dr.pattern
+}
+
+// WithPattern returns a copy of the rectangle filled with the pattern
+func (dr DrawRectangle) WithPattern(pattern Pattern) DrawRectangle {
+	dr.pattern = pattern
+	return dr
+}
+
+// FillerStyle returns the colours of the inside of the rectangle
+func (dr DrawRectangle) FillerStyle() Style {
+	return dr.fillerStyle
+}
+
+// OutlineStyle returns the colours of the outline of the rectangle
+func (dr DrawRectangle) OutlineStyle() Style {
+	return dr.outlineStyle
+}
+
+// WithStyles returns a copy of the rectangle drawn with the styles
+func (dr DrawRectangle) WithStyles(filler, outline Style) DrawRectangle {
+	dr.fillerStyle = filler
+	dr.outlineStyle = outline
+	return dr
+}
+
 // CreatedAt returns the time where the rectangle was created
 func (dr DrawRectangle) CreatedAt() time.Time {
 	return dr.createdAt
 }
 
+// Sequence returns the position of the rectangle in the order of the tasks of its canvas. It is 0 until the rectangle is
+// added to a canvas
+func (dr DrawRectangle) Sequence() int {
+	return dr.sequence
+}
+
+// WithSequence returns a copy of the rectangle with the sequence, used to restore the rectangle of a stored canvas
+func (dr DrawRectangle) WithSequence(sequence int) DrawRectangle {
+	dr.sequence = sequence
+	return dr
+}
+
 // NewDrawRectangle is a constructor for tasks that draw rectangles
 func NewDrawRectangle(id uuid.UUID, point Point, height, width int, filler, outline rune, createdAt time.Time) DrawRectangle {
 	return DrawRectangle{
@@ -91,13 +251,39 @@ func NewDrawRectangle(id uuid.UUID, point Point, height, width int, filler, outl
 	}
 }
 
+// Connectivity defines the neighbours of a cell that a fill spreads to
+type Connectivity int
+
+const (
+	// FourConnected spreads to the horizontal and vertical neighbours of a cell
+	FourConnected Connectivity = 4
+	// EightConnected spreads to the diagonal neighbours of a cell too, so it crosses diagonal lines
+	EightConnected Connectivity = 8
+)
+
+// FillMode defines which cells are filled
+type FillMode string
+
+const (
+	// FloodFillMode fills the cells that have the same rune as the cell where the fill starts
+	FloodFillMode FillMode = "flood"
+	// BoundaryFillMode fills all the cells until the boundary rune is hit
+	BoundaryFillMode FillMode = "boundary"
+)
+
 // Fill defines the coordinates where a filling operation needs to be performed
 type Fill struct {
-	id     uuid.UUID
-	point  Point
-	filler rune
+	id           uuid.UUID
+	point        Point
+	filler       rune
+	connectivity Connectivity
+	mode         FillMode
+	boundary     rune
+	pattern      Pattern
+	style        Style
 
 	createdAt time.Time
+	sequence  int
 }
 
 // ID returns the id of the fill
@@ -115,27 +301,507 @@ func (f Fill) Filler() rune {
 	return f.filler
 }
 
+// Connectivity returns the neighbours of a cell that the fill spreads to
+func (f Fill) Connectivity() Connectivity {
+	return f.connectivity
+}
+
+// Mode returns which cells are filled
+func (f Fill) Mode() FillMode {
+	return f.mode
+}
+
+// Boundary returns the rune where the fill stops when using the boundary fill mode
+func (f Fill) Boundary() rune {
+	return f.boundary
+}
+
+// Pattern returns the pattern to fill the area with. When it is not empty it is used instead of the filler
+func (f Fill) Pattern() Pattern {
+	return f.pattern
+}
+
+// WithPattern returns a copy of the fill using the pattern
+func (f Fill) WithPattern(pattern Pattern) Fill {
+	f.pattern = pattern
+	return f
+}
+
+// Style returns the colours of the filled area
+func (f Fill) Style() Style {
+	return f.style
+}
+
+// WithStyle returns a copy of the fill drawn with the style
+func (f Fill) WithStyle(style Style) Fill {
+	f.style = style
+	return f
+}
+
 // CreatedAt returns the time where the fill was created
 func (f Fill) CreatedAt() time.Time {
 	return f.createdAt
 }
 
-// NewFill is a constructor
+// Sequence returns the position of the fill in the order of the tasks of its canvas. It is 0 until the fill is
+// added to a canvas
+func (f Fill) Sequence() int {
+	return f.sequence
+}
+
+// WithSequence returns a copy of the fill with the sequence, used to restore the fill of a stored canvas
+func (f Fill) WithSequence(sequence int) Fill {
+	f.sequence = sequence
+	return f
+}
+
+// NewFill is a constructor for flood fills that spread to the horizontal and vertical neighbours
 func NewFill(id uuid.UUID, point Point, filler rune, createdAt time.Time) Fill {
+	return NewFillWithMode(id, point, filler, FourConnected, FloodFillMode, 0, createdAt)
+}
+
+// NewFillWithMode is a constructor. The boundary is only used by the boundary fill mode
+func NewFillWithMode(id uuid.UUID, point Point, filler rune, connectivity Connectivity, mode FillMode, boundary rune, createdAt time.Time) Fill {
 	return Fill{
+		id:           id,
+		point:        point,
+		filler:       filler,
+		connectivity: connectivity,
+		mode:         mode,
+		boundary:     boundary,
+		createdAt:    createdAt,
+	}
+}
+
+// DrawLine defines the coordinates of how to draw a straight line between two points
+type DrawLine struct {
+	id      uuid.UUID
+	from    Point
+	to      Point
+	outline rune
+
+	createdAt time.Time
+	sequence  int
+}
+
+// ID returns the id of the line
+func (dl DrawLine) ID() uuid.UUID {
+	return dl.id
+}
+
+// From returns the point where the line starts
+func (dl DrawLine) From() Point {
+	return dl.from
+}
+
+// To returns the point where the line ends
+func (dl DrawLine) To() Point {
+	return dl.to
+}
+
+// Outline returns the rune to draw the line with
+func (dl DrawLine) Outline() rune {
+	return dl.outline
+}
+
+// CreatedAt returns the time where the line was created
+func (dl DrawLine) CreatedAt() time.Time {
+	return dl.createdAt
+}
+
+// Sequence returns the position of the line in the order of the tasks of its canvas. It is 0 until the line is
+// added to a canvas
+func (dl DrawLine) Sequence() int {
+	return dl.sequence
+}
+
+// WithSequence returns a copy of the line with the sequence, used to restore the line of a stored canvas
+func (dl DrawLine) WithSequence(sequence int) DrawLine {
+	dl.sequence = sequence
+	return dl
+}
+
+// NewDrawLine is a constructor for tasks that draw lines
+func NewDrawLine(id uuid.UUID, from, to Point, outline rune, createdAt time.Time) DrawLine {
+	return DrawLine{
+		id:        id,
+		from:      from,
+		to:        to,
+		outline:   outline,
+		createdAt: createdAt,
+	}
+}
+
+// DrawEllipse defines the bounding box of a 2D ellipse and how to draw it
+type DrawEllipse struct {
+	id      uuid.UUID
+	point   Point
+	height  int
+	width   int
+	filler  rune
+	outline rune
+
+	createdAt time.Time
+	sequence  int
+}
+
+// ID returns the id of the ellipse
+func (de DrawEllipse) ID() uuid.UUID {
+	return de.id
+}
+
+// Point returns the top left corner of the bounding box of the ellipse
+func (de DrawEllipse) Point() Point {
+	return de.point
+}
+
+// Height returns the height of the bounding box of the ellipse
+func (de DrawEllipse) Height() int {
+	return de.height
+}
+
+// Width returns the width of the bounding box of the ellipse
+func (de DrawEllipse) Width() int {
+	return de.width
+}
+
+// Filler returns the rune to fill the ellipse with
+func (de DrawEllipse) Filler() rune {
+	return de.filler
+}
+
+// Outline returns the rune to out line the ellipse with
+func (de DrawEllipse) Outline() rune {
+	return de.outline
+}
+
+// CreatedAt returns the time where the ellipse was created
+func (de DrawEllipse) CreatedAt() time.Time {
+	return de.createdAt
+}
+
+// Sequence returns the position of the ellipse in the order of the tasks of its canvas. It is 0 until the ellipse is
+// added to a canvas
+func (de DrawEllipse) Sequence() int {
+	return de.sequence
+}
+
+// WithSequence returns a copy of the ellipse with the sequence, used to restore the ellipse of a stored canvas
+func (de DrawEllipse) WithSequence(sequence int) DrawEllipse {
+	de.sequence = sequence
+	return de
+}
+
+// NewDrawEllipse is a constructor for tasks that draw ellipses
+func NewDrawEllipse(id uuid.UUID, point Point, height, width int, filler, outline rune, createdAt time.Time) DrawEllipse {
+	return DrawEllipse{
 		id:        id,
 		point:     point,
+		height:    height,
+		width:     width,
 		filler:    filler,
+		outline:   outline,
+		createdAt: createdAt,
+	}
+}
+
+// Alignment defines how the lines of a text are aligned horizontally
+type Alignment string
+
+const (
+	AlignLeft   Alignment = "left"
+	AlignCenter Alignment = "center"
+	AlignRight  Alignment = "right"
+)
+
+// DrawText defines a text to be placed in the canvas
+type DrawText struct {
+	id        uuid.UUID
+	point     Point
+	text      string
+	wrapWidth int
+	alignment Alignment
+
+	createdAt time.Time
+	sequence  int
+}
+
+// ID returns the id of the text
+func (dt DrawText) ID() uuid.UUID {
+	return dt.id
+}
+
+// Point returns the point where the text starts
+func (dt DrawText) Point() Point {
+	return dt.point
+}
+
+// Text returns the text to be placed
+func (dt DrawText) Text() string {
+	return dt.text
+}
+
+// WrapWidth returns the maximum number of characters per line. Zero means that the text is not wrapped
+func (dt DrawText) WrapWidth() int {
+	return dt.wrapWidth
+}
+
+// Alignment returns how the lines of the text are aligned
+func (dt DrawText) Alignment() Alignment {
+	return dt.alignment
+}
+
+// CreatedAt returns the time where the text was created
+func (dt DrawText) CreatedAt() time.Time {
+	return dt.createdAt
+}
+
+// Sequence returns the position of the text in the order of the tasks of its canvas. It is 0 until the text is
+// added to a canvas
+func (dt DrawText) Sequence() int {
+	return dt.sequence
+}
+
+// WithSequence returns a copy of the text with the sequence, used to restore the text of a stored canvas
+func (dt DrawText) WithSequence(sequence int) DrawText {
+	dt.sequence = sequence
+	return dt
+}
+
+// NewDrawText is a constructor for tasks that place texts
+func NewDrawText(id uuid.UUID, point Point, text string, wrapWidth int, alignment Alignment, createdAt time.Time) DrawText {
+	return DrawText{
+		id:        id,
+		point:     point,
+		text:      text,
+		wrapWidth: wrapWidth,
+		alignment: alignment,
+		createdAt: createdAt,
+	}
+}
+
+// TextLine defines a line of a text once it has been wrapped and aligned
+type TextLine struct {
+	point Point
+	text  string
+}
+
+// Point returns the point where the line starts
+func (tl TextLine) Point() Point {
+	return tl.point
+}
+
+// Text returns the content of the line
+func (tl TextLine) Text() string {
+	return tl.text
+}
+
+// Lines returns the lines of the text after wrapping and aligning them
+func (dt DrawText) Lines() []TextLine {
+	lines := dt.wrap()
+	width := dt.Width()
+
+	textLines := make([]TextLine, len(lines))
+	for i, line := range lines {
+		offset := 0
+		switch dt.alignment {
+		case AlignCenter:
+			offset = (width - len(line)) / 2
+		case AlignRight:
+			offset = width - len(line)
+		case AlignLeft:
+		}
+
+		textLines[i] = TextLine{
+			point: NewPoint(dt.point.x+offset, dt.point.y+i),
+			text:  string(line),
+		}
+	}
+
+	return textLines
+}
+
+// Height returns the number of lines that the text takes
+func (dt DrawText) Height() int {
+	return len(dt.wrap())
+}
+
+// Width returns the number of characters of the widest line of the text, or the wrap width when present
+func (dt DrawText) Width() int {
+	if dt.wrapWidth > 0 {
+		return dt.wrapWidth
+	}
+
+	width := 0
+	for _, line := range dt.wrap() {
+		if width < len(line) {
+			width = len(line)
+		}
+	}
+	return width
+}
+
+// wrap splits the text in lines, breaking them between words when they are longer than the wrap width.
+// Words longer than the wrap width are split
+func (dt DrawText) wrap() [][]rune {
+	var lines [][]rune
+	for _, paragraph := range strings.Split(dt.text, "\n") {
+		if dt.wrapWidth <= 0 {
+			lines = append(lines, []rune(paragraph))
+			continue
+		}
+
+		var line []rune
+		for _, field := range strings.Fields(paragraph) {
+			word := []rune(field)
+			for len(word) > dt.wrapWidth {
+				if len(line) > 0 {
+					lines = append(lines, line)
+					line = nil
+				}
+				lines = append(lines, word[:dt.wrapWidth])
+				word = word[dt.wrapWidth:]
+			}
+
+			if len(line) > 0 && len(line)+1+len(word) > dt.wrapWidth {
+				lines = append(lines, line)
+				line = nil
+			}
+			if len(line) > 0 {
+				line = append(line, ' ')
+			}
+			line = append(line, word...)
+		}
+		lines = append(lines, line)
+	}
+
+	return lines
+}
+
+// DrawPolyline defines the ordered list of points of an open shape made of straight lines
+type DrawPolyline struct {
+	id      uuid.UUID
+	points  []Point
+	outline rune
+
+	createdAt time.Time
+	sequence  int
+}
+
+// ID returns the id of the polyline
+func (dp DrawPolyline) ID() uuid.UUID {
+	return dp.id
+}
+
+// Points returns the ordered list of points joined by the polyline
+func (dp DrawPolyline) Points() []Point {
+	return append([]Point(nil), dp.points...)
+}
+
+// Outline returns the rune to draw the polyline with
+func (dp DrawPolyline) Outline() rune {
+	return dp.outline
+}
+
+// CreatedAt returns the time where the polyline was created
+func (dp DrawPolyline) CreatedAt() time.Time {
+	return dp.createdAt
+}
+
+// Sequence returns the position of the polyline in the order of the tasks of its canvas. It is 0 until the polyline is
+// added to a canvas
+func (dp DrawPolyline) Sequence() int {
+	return dp.sequence
+}
+
+// WithSequence returns a copy of the polyline with the sequence, used to restore the polyline of a stored canvas
+func (dp DrawPolyline) WithSequence(sequence int) DrawPolyline {
+	dp.sequence = sequence
+	return dp
+}
+
+// NewDrawPolyline is a constructor for tasks that draw polylines
+func NewDrawPolyline(id uuid.UUID, points []Point, outline rune, createdAt time.Time) DrawPolyline {
+	return DrawPolyline{
+		id:        id,
+		points:    append([]Point(nil), points...),
+		outline:   outline,
+		createdAt: createdAt,
+	}
+}
+
+// DrawPolygon defines the ordered list of vertices of a closed shape
+type DrawPolygon struct {
+	id      uuid.UUID
+	points  []Point
+	filler  rune
+	outline rune
+
+	createdAt time.Time
+	sequence  int
+}
+
+// ID returns the id of the polygon
+func (dp DrawPolygon) ID() uuid.UUID {
+	return dp.id
+}
+
+// Points returns the ordered list of vertices of the polygon. The last one is joined with the first one
+func (dp DrawPolygon) Points() []Point {
+	return append([]Point(nil), dp.points...)
+}
+
+// Filler returns the rune to fill the polygon with
+func (dp DrawPolygon) Filler() rune {
+	return dp.filler
+}
+
+// Outline returns the rune to out line the polygon with
+func (dp DrawPolygon) Outline() rune {
+	return dp.outline
+}
+
+// CreatedAt returns the time where the polygon was created
+func (dp DrawPolygon) CreatedAt() time.Time {
+	return dp.createdAt
+}
+
+// Sequence returns the position of the polygon in the order of the tasks of its canvas. It is 0 until the polygon is
+// added to a canvas
+func (dp DrawPolygon) Sequence() int {
+	return dp.sequence
+}
+
+// WithSequence returns a copy of the polygon with the sequence, used to restore the polygon of a stored canvas
+func (dp DrawPolygon) WithSequence(sequence int) DrawPolygon {
+	dp.sequence = sequence
+	return dp
+}
+
+// NewDrawPolygon is a constructor for tasks that draw polygons
+func NewDrawPolygon(id uuid.UUID, points []Point, filler, outline rune, createdAt time.Time) DrawPolygon {
+	return DrawPolygon{
+		id:        id,
+		points:    append([]Point(nil), points...),
+		filler:    filler,
+		outline:   outline,
 		createdAt: createdAt,
 	}
 }
 
 // Canvas defines the 2D space where rectangles can be placed
 type Canvas struct {
-	id     uuid.UUID
-	height int
-	width  int
-	tasks  []Task
+	id      uuid.UUID
+	height  int
+	width   int
+	tasks   []Task
+	cursor  int
+	added   []uuid.UUID
+	removed []uuid.UUID
+	edited  []uuid.UUID
+
+	// sequence is the highest sequence given to the tasks of the canvas, so the next task added gets the following one
+	sequence int
 
 	createdAt time.Time
 }
@@ -155,44 +821,557 @@ func (c Canvas) Width() int {
 	return c.width
 }
 
-// Tasks returns the slice of tasks to be performed in the canvas
+// Tasks returns the slice of tasks to be performed in the canvas. The tasks that have been undone are not included
 func (c Canvas) Tasks() []Task {
+	return c.tasks[:c.cursor]
+}
+
+// History returns all the tasks of the canvas, including the ones that have been undone and can be redone
+func (c Canvas) History() []Task {
 	return c.tasks
 }
 
+// Cursor returns the number of tasks from the history that are performed in the canvas
+func (c Canvas) Cursor() int {
+	return c.cursor
+}
+
 // CreatedAt returns the time where the canvas was created
 func (c Canvas) CreatedAt() time.Time {
 	return c.createdAt
 }
 
-// NewCanvas is a constructor for canvas
+// Sequence returns the highest sequence given to a task of the canvas, including the tasks that have been removed. It
+// only ever increases, so every task added to the canvas gets a new sequence
+func (c Canvas) Sequence() int {
+	return c.sequence
+}
+
+// WithSequence returns a copy of the canvas with the highest sequence given to its tasks, used to restore a stored canvas
+// whose last tasks have been removed. The sequence is never lower than the one of the tasks of the canvas
+func (c Canvas) WithSequence(sequence int) Canvas {
+	if sequence > c.sequence {
+		c.sequence = sequence
+	}
+	return c
+}
+
+// AddedTasks returns the IDs of the tasks added to the canvas since it was created or retrieved, which are the ones
+// that got their sequence from it
+func (c Canvas) AddedTasks() []uuid.UUID {
+	return c.added
+}
+
+// RemovedTasks returns the IDs of the tasks removed from the canvas since it was created or retrieved
+func (c Canvas) RemovedTasks() []uuid.UUID {
+	return c.removed
+}
+
+// EditedTasks returns the IDs of the tasks edited, or moved by a resize, since the canvas was created or retrieved
+func (c Canvas) EditedTasks() []uuid.UUID {
+	return c.edited
+}
+
+// NewCanvas is a constructor for canvas. The tasks are new to the canvas, so they are the added tasks
 func NewCanvas(id uuid.UUID, height, width int, tasks []Task, createdAt time.Time) Canvas {
+	canvas := NewCanvasWithHistory(id, height, width, tasks, len(tasks), createdAt)
+	for _, task := range canvas.tasks {
+		if id, ok := TaskID(task); ok {
+			canvas.added = append(canvas.added, id)
+		}
+	}
+	return canvas
+}
+
+// NewCanvasWithHistory is a constructor for canvas where only the first cursor tasks of the history are performed, used
+// to restore a stored canvas. Tasks without sequence get the one following the highest sequence of the tasks before
+// them
+func NewCanvasWithHistory(id uuid.UUID, height, width int, history []Task, cursor int, createdAt time.Time) Canvas {
+	if cursor < 0 {
+		cursor = 0
+	}
+	if cursor > len(history) {
+		cursor = len(history)
+	}
+
+	// the history is only copied when a task gets its sequence, so the tasks of the caller are not changed
+	tasks := history
+	copied := false
+	var last int
+	for i := range history {
+		sequence, ok := TaskSequence(history[i])
+		if !ok {
+			continue
+		}
+
+		if sequence == 0 {
+			if !copied {
+				tasks = append([]Task(nil), history...)
+				copied = true
+			}
+			sequence = last + 1
+			tasks[i] = withSequence(history[i], sequence)
+		}
+		if sequence > last {
+			last = sequence
+		}
+	}
+
 	return Canvas{
 		id:        id,
 		height:    height,
 		width:     width,
 		tasks:     tasks,
+		cursor:    cursor,
+		sequence:  last,
 		createdAt: createdAt,
 	}
 }
 
 // AddDrawRectangle adds a rectangle to an existing canvas
 func (c *Canvas) AddDrawRectangle(rectangle DrawRectangle) error {
-	if c.height < rectangle.height+rectangle.point.y ||
-		c.width < rectangle.width+rectangle.point.x {
+	if !c.fitsTask(rectangle) {
 		return ErrOutOfBounds
 	}
 
-	c.tasks = append(c.tasks, rectangle)
-	return nil
+	return c.add(rectangle.id, rectangle)
 }
 
 // AddFill adds a fill operation to an existing canvas
 func (c *Canvas) AddFill(fill Fill) error {
-	if c.height < fill.point.y || c.width < fill.point.x {
+	if !c.fitsTask(fill) {
 		return ErrOutOfBounds
 	}
 
-	c.tasks = append(c.tasks, fill)
+	return c.add(fill.id, fill)
+}
+
+// AddDrawLine adds a line to an existing canvas
+func (c *Canvas) AddDrawLine(line DrawLine) error {
+	if !c.fitsTask(line) {
+		return ErrOutOfBounds
+	}
+
+	return c.add(line.id, line)
+}
+
+// AddDrawEllipse adds an ellipse to an existing canvas
+func (c *Canvas) AddDrawEllipse(ellipse DrawEllipse) error {
+	if !validSize(ellipse) {
+		return ErrInvalidSize
+	}
+	if !c.fitsTask(ellipse) {
+		return ErrOutOfBounds
+	}
+
+	return c.add(ellipse.id, ellipse)
+}
+
+// AddDrawText adds a text to an existing canvas
+func (c *Canvas) AddDrawText(text DrawText) error {
+	if !c.fitsTask(text) {
+		return ErrOutOfBounds
+	}
+
+	return c.add(text.id, text)
+}
+
+// AddDrawPolyline adds a polyline to an existing canvas
+func (c *Canvas) AddDrawPolyline(polyline DrawPolyline) error {
+	if !c.fitsTask(polyline) {
+		return ErrOutOfBounds
+	}
+
+	return c.add(polyline.id, polyline)
+}
+
+// AddDrawPolygon adds a polygon to an existing canvas
+func (c *Canvas) AddDrawPolygon(polygon DrawPolygon) error {
+	if !c.fitsTask(polygon) {
+		return ErrOutOfBounds
+	}
+
+	return c.add(polygon.id, polygon)
+}
+
+// add appends a task to the canvas discarding the tasks that have been undone, and assigns it the next sequence of the
+// canvas. It returns ErrTaskAlreadyExists when the ID is taken by a task of the canvas, including the undone ones
+func (c *Canvas) add(id uuid.UUID, task Task) error {
+	for i := range c.tasks {
+		if taskID, ok := TaskID(c.tasks[i]); ok && taskID == id {
+			return ErrTaskAlreadyExists
+		}
+	}
+
+	for _, undone := range c.tasks[c.cursor:] {
+		if taskID, ok := TaskID(undone); ok {
+			c.removed = append(c.removed, taskID)
+		}
+	}
+
+	c.sequence++
+	c.tasks = append(c.tasks[:c.cursor:c.cursor], withSequence(task, c.sequence))
+	c.cursor = len(c.tasks)
+	c.added = append(c.added, id)
+	return nil
+}
+
+// Undo hides the last task performed in the canvas
+func (c *Canvas) Undo() error {
+	if c.cursor == 0 {
+		return ErrNothingToUndo
+	}
+
+	c.cursor--
+	return nil
+}
+
+// Redo performs again the last task undone in the canvas
+func (c *Canvas) Redo() error {
+	if c.cursor == len(c.tasks) {
+		return ErrNothingToRedo
+	}
+
+	c.cursor++
+	return nil
+}
+
+func (c Canvas) fits(point Point, height, width int) bool {
+	return point.x >= 0 && point.y >= 0 &&
+		c.height >= height+point.y && c.width >= width+point.x
+}
+
+// validSize checks that the bounding box of an ellipse is not empty, since it is drawn from its center
+func validSize(task Task) bool {
+	if ellipse, ok := task.(DrawEllipse); ok {
+		return ellipse.height > 0 && ellipse.width > 0
+	}
+	return true
+}
+
+func (c Canvas) contains(point Point) bool {
+	return point.x >= 0 && point.y >= 0 && point.x < c.width && point.y < c.height
+}
+
+func (c Canvas) containsAll(points []Point) bool {
+	for _, point := range points {
+		if !c.contains(point) {
+			return false
+		}
+	}
+	return true
+}
+
+// Anchor defines the corner of the canvas that stays in place when the canvas is resized
+type Anchor string
+
+const (
+	AnchorTopLeft     Anchor = "top_left"
+	AnchorTopRight    Anchor = "top_right"
+	AnchorBottomLeft  Anchor = "bottom_left"
+	AnchorBottomRight Anchor = "bottom_right"
+)
+
+// Resize changes the size of the canvas keeping its content attached to the anchor corner. When any of the tasks,
+// including the undone ones, does not fit in the new size it returns ErrOutOfBounds, unless clip is set. In that case the
+// tasks are cropped at the edges of the canvas, and the ones left wholly outside of it are removed. Tasks that were
+// already cropped by a previous resize do not need to fit either
+func (c *Canvas) Resize(height, width int, anchor Anchor, clip bool) error {
+	var dx, dy int
+	switch anchor {
+	case AnchorTopLeft:
+	case AnchorTopRight:
+		dx = width - c.width
+	case AnchorBottomLeft:
+		dy = height - c.height
+	case AnchorBottomRight:
+		dx, dy = width-c.width, height-c.height
+	default:
+		return ErrInvalidAnchor
+	}
+
+	resized := NewCanvasWithHistory(c.id, height, width, make([]Task, 0, len(c.tasks)), 0, c.createdAt)
+	resized.added = c.added
+	resized.removed = append([]uuid.UUID(nil), c.removed...)
+	resized.edited = append([]uuid.UUID(nil), c.edited...)
+	resized.sequence = c.sequence
+	for i := range c.tasks {
+		task := translate(c.tasks[i], dx, dy)
+		if resized.fitsTask(task) || (!clip && !c.fitsTask(c.tasks[i])) {
+			resized.keep(task, i < c.cursor, dx != 0 || dy != 0)
+			continue
+		}
+		if !clip {
+			return ErrOutOfBounds
+		}
+
+		if resized.overlapsTask(task) {
+			resized.keep(task, i < c.cursor, dx != 0 || dy != 0)
+		} else if id, ok := TaskID(task); ok {
+			resized.removed = append(resized.removed, id)
+		}
+	}
+
+	*c = resized
 	return nil
 }
+
+// keep appends a task of the history to the canvas while it is being resized, and marks it as edited when it moved
+func (c *Canvas) keep(task Task, performed, moved bool) {
+	c.tasks = append(c.tasks, task)
+	if performed {
+		c.cursor++
+	}
+	if id, ok := TaskID(task); ok && moved {
+		c.markEdited(id)
+	}
+}
+
+// markEdited adds the task to the edited tasks, unless it is already there
+func (c *Canvas) markEdited(id uuid.UUID) {
+	for _, edited := range c.edited {
+		if edited == id {
+			return
+		}
+	}
+	c.edited = append(c.edited, id)
+}
+
+// RemoveTask removes a task from an existing canvas
+func (c *Canvas) RemoveTask(id uuid.UUID) error {
+	tasks := make([]Task, 0, len(c.tasks))
+	cursor := c.cursor
+	for i, task := range c.tasks {
+		if taskID, ok := TaskID(task); ok && taskID == id {
+			if i < c.cursor {
+				cursor--
+			}
+			continue
+		}
+		tasks = append(tasks, task)
+	}
+
+	if len(tasks) == len(c.tasks) {
+		return ErrTaskNotFound
+	}
+
+	c.tasks = tasks
+	c.cursor = cursor
+	c.removed = append(c.removed, id)
+	return nil
+}
+
+// UpdateTask replaces the task with the same ID in the canvas, keeping its position in the order of the tasks, its
+// sequence, and its creation time. The task cannot change its type
+func (c *Canvas) UpdateTask(task Task) error {
+	id, ok := TaskID(task)
+	if !ok {
+		return ErrTaskNotFound
+	}
+
+	for i := range c.tasks {
+		if taskID, ok := TaskID(c.tasks[i]); !ok || taskID != id {
+			continue
+		}
+
+		if reflect.TypeOf(c.tasks[i]) != reflect.TypeOf(task) {
+			return ErrTaskTypeMismatch
+		}
+		if !validSize(task) {
+			return ErrInvalidSize
+		}
+		if !c.fitsTask(task) {
+			return ErrOutOfBounds
+		}
+
+		if timed, ok := c.tasks[i].(interface{ CreatedAt() time.Time }); ok {
+			task = withCreatedAt(task, timed.CreatedAt())
+		}
+		if sequence, ok := TaskSequence(c.tasks[i]); ok {
+			task = withSequence(task, sequence)
+		}
+		c.tasks[i] = task
+		c.markEdited(id)
+		return nil
+	}
+
+	return ErrTaskNotFound
+}
+
+func withCreatedAt(task Task, createdAt time.Time) Task {
+	switch task := task.(type) {
+	case DrawRectangle:
+		task.createdAt = createdAt
+		return task
+	case Fill:
+		task.createdAt = createdAt
+		return task
+	case DrawLine:
+		task.createdAt = createdAt
+		return task
+	case DrawEllipse:
+		task.createdAt = createdAt
+		return task
+	case DrawText:
+		task.createdAt = createdAt
+		return task
+	case DrawPolyline:
+		task.createdAt = createdAt
+		return task
+	case DrawPolygon:
+		task.createdAt = createdAt
+		return task
+	}
+
+	return task
+}
+
+func withSequence(task Task, sequence int) Task {
+	switch task := task.(type) {
+	case DrawRectangle:
+		return task.WithSequence(sequence)
+	case Fill:
+		return task.WithSequence(sequence)
+	case DrawLine:
+		return task.WithSequence(sequence)
+	case DrawEllipse:
+		return task.WithSequence(sequence)
+	case DrawText:
+		return task.WithSequence(sequence)
+	case DrawPolyline:
+		return task.WithSequence(sequence)
+	case DrawPolygon:
+		return task.WithSequence(sequence)
+	}
+
+	return task
+}
+
+// TaskSequence returns the sequence of a task, if the task has one
+func TaskSequence(task Task) (int, bool) {
+	sequenced, ok := task.(interface{ Sequence() int })
+	if !ok {
+		return 0, false
+	}
+	return sequenced.Sequence(), true
+}
+
+// TaskID returns the ID of a task, if the task has one
+func TaskID(task Task) (uuid.UUID, bool) {
+	identifiable, ok := task.(interface{ ID() uuid.UUID })
+	if !ok {
+		return uuid.UUID{}, false
+	}
+	return identifiable.ID(), true
+}
+
+func translate(task Task, dx, dy int) Task {
+	switch task := task.(type) {
+	case DrawRectangle:
+		task.point = task.point.translate(dx, dy)
+		return task
+	case Fill:
+		task.point = task.point.translate(dx, dy)
+		return task
+	case DrawLine:
+		task.from = task.from.translate(dx, dy)
+		task.to = task.to.translate(dx, dy)
+		return task
+	case DrawEllipse:
+		task.point = task.point.translate(dx, dy)
+		return task
+	case DrawText:
+		task.point = task.point.translate(dx, dy)
+		return task
+	case DrawPolyline:
+		task.points = translateAll(task.points, dx, dy)
+		return task
+	case DrawPolygon:
+		task.points = translateAll(task.points, dx, dy)
+		return task
+	}
+
+	return task
+}
+
+func (p Point) translate(dx, dy int) Point {
+	return NewPoint(p.x+dx, p.y+dy)
+}
+
+func translateAll(points []Point, dx, dy int) []Point {
+	translated := make([]Point, len(points))
+	for i := range points {
+		translated[i] = points[i].translate(dx, dy)
+	}
+	return translated
+}
+
+// overlapsTask checks if any of the cells where a task is performed is inside the bounds of the canvas
+func (c Canvas) overlapsTask(task Task) bool {
+	var points []Point
+	switch task := task.(type) {
+	case DrawRectangle:
+		points = []Point{task.point, task.point.translate(task.width-1, task.height-1)}
+	case Fill:
+		return c.contains(task.point)
+	case DrawLine:
+		points = []Point{task.from, task.to}
+	case DrawEllipse:
+		points = []Point{task.point, task.point.translate(task.width-1, task.height-1)}
+	case DrawText:
+		points = []Point{task.point, task.point.translate(task.Width()-1, task.Height()-1)}
+	case DrawPolyline:
+		points = task.points
+	case DrawPolygon:
+		points = task.points
+	default:
+		return true
+	}
+
+	if len(points) == 0 {
+		return false
+	}
+	left, top, right, bottom := points[0].x, points[0].y, points[0].x, points[0].y
+	for _, point := range points[1:] {
+		left, right = min(left, point.x), max(right, point.x)
+		top, bottom = min(top, point.y), max(bottom, point.y)
+	}
+	return right >= 0 && bottom >= 0 && left < c.width && top < c.height
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// fitsTask checks if a task can be performed inside the bounds of the canvas
+func (c Canvas) fitsTask(task Task) bool {
+	switch task := task.(type) {
+	case DrawRectangle:
+		return c.fits(task.point, task.height, task.width)
+	case Fill:
+		return task.point.x >= 0 && task.point.y >= 0 &&
+			c.height >= task.point.y && c.width >= task.point.x
+	case DrawLine:
+		return c.contains(task.from) && c.contains(task.to)
+	case DrawEllipse:
+		return c.fits(task.point, task.height, task.width)
+	case DrawText:
+		return c.fits(task.point, task.Height(), task.Width())
+	case DrawPolyline:
+		return c.containsAll(task.points)
+	case DrawPolygon:
+		return c.containsAll(task.points)
+	}
+
+	return true
+}
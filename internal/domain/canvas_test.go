@@ -1,6 +1,7 @@
 package domain_test
 
 import (
+	"strings"
 	"testing"
 	"time"
 
@@ -134,7 +135,7 @@ func TestCanvasGetters(t *testing.T) {
 
 	canvasID := uuid.New()
 	canvasTime := time.Now().UTC()
-	tasks := []domain.Task{validFill()}
+	tasks := []domain.Task{validFill().WithSequence(1)}
 	canvas := domain.NewCanvas(
 		canvasID,
 		30,
@@ -191,9 +192,148 @@ func TestFillGetters(t *testing.T) {
 	require.Equal(t, 4, fill.Point().X())
 	require.Equal(t, 5, fill.Point().Y())
 	require.Equal(t, '-', fill.Filler())
+	require.Equal(t, domain.FourConnected, fill.Connectivity())
+	require.Equal(t, domain.FloodFillMode, fill.Mode())
 	require.Equal(t, fillTime, fill.CreatedAt())
 }
 
+func TestNewFillWithMode(t *testing.T) {
+	t.Parallel()
+
+	fillID := uuid.New()
+	fillTime := time.Now().UTC()
+	fill := domain.NewFillWithMode(
+		fillID,
+		domain.NewPoint(4, 5),
+		'-',
+		domain.EightConnected,
+		domain.BoundaryFillMode,
+		'#',
+		fillTime,
+	)
+
+	require.Equal(t, fillID, fill.ID())
+	require.Equal(t, 4, fill.Point().X())
+	require.Equal(t, 5, fill.Point().Y())
+	require.Equal(t, '-', fill.Filler())
+	require.Equal(t, domain.EightConnected, fill.Connectivity())
+	require.Equal(t, domain.BoundaryFillMode, fill.Mode())
+	require.Equal(t, '#', fill.Boundary())
+	require.Equal(t, fillTime, fill.CreatedAt())
+}
+
+func TestNewPattern(t *testing.T) {
+	t.Parallel()
+
+	pattern := domain.NewPattern([]string{"/\\", "\\/"}, domain.PatternAnchorShape)
+
+	require.False(t, pattern.IsEmpty())
+	require.Equal(t, []string{"/\\", "\\/"}, pattern.Tile())
+	require.Equal(t, domain.PatternAnchorShape, pattern.Anchor())
+
+	empty := domain.NewPattern([]string{""}, domain.PatternAnchorCanvas)
+
+	require.True(t, empty.IsEmpty())
+	require.Nil(t, empty.Tile())
+	require.Equal(t, domain.Pattern{}, empty)
+}
+
+func TestPattern_At(t *testing.T) {
+	tests := []struct {
+		name         string
+		anchor       domain.PatternAnchor
+		x, y         int
+		origin       domain.Point
+		expectedRune rune
+	}{
+		{
+			name: `Given a pattern anchored to the canvas,
+                   when the rune for a cell inside the first tile is requested,
+                   then the rune in that position of the tile is returned`,
+			anchor:       domain.PatternAnchorCanvas,
+			x:            1,
+			y:            0,
+			origin:       domain.NewPoint(1, 1),
+			expectedRune: 'b',
+		},
+		{
+			name: `Given a pattern anchored to the canvas,
+                   when the rune for a cell outside the first tile is requested,
+                   then the rune in that position of the repeated tile is returned`,
+			anchor:       domain.PatternAnchorCanvas,
+			x:            4,
+			y:            3,
+			origin:       domain.NewPoint(1, 1),
+			expectedRune: 'e',
+		},
+		{
+			name: `Given a pattern anchored to the shape,
+                   when the rune for the origin of the shape is requested,
+                   then the first rune of the tile is returned`,
+			anchor:       domain.PatternAnchorShape,
+			x:            1,
+			y:            1,
+			origin:       domain.NewPoint(1, 1),
+			expectedRune: 'a',
+		},
+		{
+			name: `Given a pattern anchored to the shape,
+                   when the rune for a cell above and to the left of the origin of the shape is requested,
+                   then the rune in that position of the repeated tile is returned`,
+			anchor:       domain.PatternAnchorShape,
+			x:            0,
+			y:            0,
+			origin:       domain.NewPoint(1, 1),
+			expectedRune: 'f',
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			pattern := domain.NewPattern([]string{"abc", "def"}, tt.anchor)
+
+			require.Equal(t, tt.expectedRune, pattern.At(tt.x, tt.y, tt.origin))
+		})
+	}
+}
+
+func TestWithPattern(t *testing.T) {
+	t.Parallel()
+
+	pattern := domain.NewPattern([]string{"xo", "ox"}, domain.PatternAnchorCanvas)
+
+	rectangle := domain.NewDrawRectangle(uuid.New(), domain.NewPoint(0, 0), 3, 3, ' ', '#', time.Now().UTC())
+	require.True(t, rectangle.Pattern().IsEmpty())
+	require.Equal(t, pattern, rectangle.WithPattern(pattern).Pattern())
+
+	fill := domain.NewFill(uuid.New(), domain.NewPoint(0, 0), ' ', time.Now().UTC())
+	require.True(t, fill.Pattern().IsEmpty())
+	require.Equal(t, pattern, fill.WithPattern(pattern).Pattern())
+}
+
+func TestWithStyles(t *testing.T) {
+	t.Parallel()
+
+	filler := domain.NewStyle(domain.ColourYellow, domain.ColourBlue)
+	outline := domain.NewStyle(domain.ColourRed, domain.DefaultColour)
+
+	rectangle := domain.NewDrawRectangle(uuid.New(), domain.NewPoint(0, 0), 3, 3, ' ', '#', time.Now().UTC())
+	require.True(t, rectangle.FillerStyle().IsDefault())
+	require.True(t, rectangle.OutlineStyle().IsDefault())
+	styled := rectangle.WithStyles(filler, outline)
+	require.Equal(t, filler, styled.FillerStyle())
+	require.Equal(t, outline, styled.OutlineStyle())
+	require.False(t, styled.OutlineStyle().IsDefault())
+
+	fill := domain.NewFill(uuid.New(), domain.NewPoint(0, 0), ' ', time.Now().UTC())
+	require.True(t, fill.Style().IsDefault())
+	require.Equal(t, filler, fill.WithStyle(filler).Style())
+	require.Equal(t, domain.ColourYellow, fill.WithStyle(filler).Style().Foreground())
+	require.Equal(t, domain.ColourBlue, fill.WithStyle(filler).Style().Background())
+}
+
 func TestCanvas_AddDrawRectangle(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -299,3 +439,887 @@ func TestCanvas_AddFill(t *testing.T) {
 		})
 	}
 }
+
+func TestDrawLineGetters(t *testing.T) {
+	t.Parallel()
+
+	lineID := uuid.New()
+	lineTime := time.Now().UTC()
+	line := domain.NewDrawLine(
+		lineID,
+		domain.NewPoint(4, 5),
+		domain.NewPoint(10, 20),
+		'*',
+		lineTime,
+	)
+
+	require.Equal(t, lineID, line.ID())
+	require.Equal(t, 4, line.From().X())
+	require.Equal(t, 5, line.From().Y())
+	require.Equal(t, 10, line.To().X())
+	require.Equal(t, 20, line.To().Y())
+	require.Equal(t, '*', line.Outline())
+	require.Equal(t, lineTime, line.CreatedAt())
+}
+
+func TestCanvas_AddDrawLine(t *testing.T) {
+	tests := []struct {
+		name        string
+		line        domain.DrawLine
+		expectedErr error
+	}{
+		{
+			name: `Given a canvas with dimensions 30x30 and a diagonal line from point 0x0 to point 29x29,
+                   when the AddDrawLine method is called,
+                   then no error is returned`,
+			line: domain.NewDrawLine(uuid.New(), domain.NewPoint(0, 0), domain.NewPoint(29, 29), '*', time.Now().UTC()),
+		},
+		{
+			name: `Given a canvas with dimensions 30x30 and a horizontal line from point 29x3 to point 0x3,
+                   when the AddDrawLine method is called,
+                   then no error is returned`,
+			line: domain.NewDrawLine(uuid.New(), domain.NewPoint(29, 3), domain.NewPoint(0, 3), '*', time.Now().UTC()),
+		},
+		{
+			name: `Given a canvas with dimensions 30x30 and a line from point 0x0 to point 30x0,
+                   when the AddDrawLine method is called,
+                   then an out of bounds error is returned`,
+			line:        domain.NewDrawLine(uuid.New(), domain.NewPoint(0, 0), domain.NewPoint(30, 0), '*', time.Now().UTC()),
+			expectedErr: domain.ErrOutOfBounds,
+		},
+		{
+			name: `Given a canvas with dimensions 30x30 and a line from point 0x30 to point 0x0,
+                   when the AddDrawLine method is called,
+                   then an out of bounds error is returned`,
+			line:        domain.NewDrawLine(uuid.New(), domain.NewPoint(0, 30), domain.NewPoint(0, 0), '*', time.Now().UTC()),
+			expectedErr: domain.ErrOutOfBounds,
+		},
+		{
+			name: `Given a canvas with dimensions 30x30 and a line from point -1x0 to point 5x5,
+                   when the AddDrawLine method is called,
+                   then an out of bounds error is returned`,
+			line:        domain.NewDrawLine(uuid.New(), domain.NewPoint(-1, 0), domain.NewPoint(5, 5), '*', time.Now().UTC()),
+			expectedErr: domain.ErrOutOfBounds,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			canvas := validCanvas()
+			err := canvas.AddDrawLine(tt.line)
+			if tt.expectedErr != nil {
+				require.ErrorAs(t, err, &tt.expectedErr)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestDrawEllipseGetters(t *testing.T) {
+	t.Parallel()
+
+	ellipseID := uuid.New()
+	ellipseTime := time.Now().UTC()
+	ellipse := domain.NewDrawEllipse(
+		ellipseID,
+		domain.NewPoint(4, 5),
+		30,
+		10,
+		'X',
+		'O',
+		ellipseTime,
+	)
+
+	require.Equal(t, ellipseID, ellipse.ID())
+	require.Equal(t, 4, ellipse.Point().X())
+	require.Equal(t, 5, ellipse.Point().Y())
+	require.Equal(t, 30, ellipse.Height())
+	require.Equal(t, 10, ellipse.Width())
+	require.Equal(t, 'X', ellipse.Filler())
+	require.Equal(t, 'O', ellipse.Outline())
+	require.Equal(t, ellipseTime, ellipse.CreatedAt())
+}
+
+func TestCanvas_AddDrawEllipse(t *testing.T) {
+	tests := []struct {
+		name        string
+		ellipse     domain.DrawEllipse
+		expectedErr error
+	}{
+		{
+			name: `Given a canvas with dimensions 30x30 and a draw ellipse with a bounding box 30x30 from point 0x0,
+                   when the AddDrawEllipse method is called,
+                   then no error is returned`,
+			ellipse: domain.NewDrawEllipse(uuid.New(), domain.NewPoint(0, 0), 30, 30, ' ', ' ', time.Now().UTC()),
+		},
+		{
+			name: `Given a canvas with dimensions 30x30 and a draw ellipse with a bounding box 31x30 from point 0x0,
+                   when the AddDrawEllipse method is called,
+                   then an out of bounds error is returned`,
+			ellipse:     domain.NewDrawEllipse(uuid.New(), domain.NewPoint(0, 0), 31, 30, ' ', ' ', time.Now().UTC()),
+			expectedErr: domain.ErrOutOfBounds,
+		},
+		{
+			name: `Given a canvas with dimensions 30x30 and a draw ellipse with a bounding box 30x30 from point 1x0,
+                   when the AddDrawEllipse method is called,
+                   then an out of bounds error is returned`,
+			ellipse:     domain.NewDrawEllipse(uuid.New(), domain.NewPoint(1, 0), 30, 30, ' ', ' ', time.Now().UTC()),
+			expectedErr: domain.ErrOutOfBounds,
+		},
+		{
+			name: `Given a canvas with dimensions 30x30 and a draw ellipse with a bounding box 5x5 from point 0x-1,
+                   when the AddDrawEllipse method is called,
+                   then an out of bounds error is returned`,
+			ellipse:     domain.NewDrawEllipse(uuid.New(), domain.NewPoint(0, -1), 5, 5, ' ', ' ', time.Now().UTC()),
+			expectedErr: domain.ErrOutOfBounds,
+		},
+		{
+			name: `Given a canvas with dimensions 30x30 and a draw ellipse with a bounding box -5x5 from point 0x10,
+                   when the AddDrawEllipse method is called,
+                   then an invalid size error is returned`,
+			ellipse:     domain.NewDrawEllipse(uuid.New(), domain.NewPoint(0, 10), -5, 5, ' ', ' ', time.Now().UTC()),
+			expectedErr: domain.ErrInvalidSize,
+		},
+		{
+			name: `Given a canvas with dimensions 30x30 and a draw ellipse with a bounding box 5x0 from point 0x0,
+                   when the AddDrawEllipse method is called,
+                   then an invalid size error is returned`,
+			ellipse:     domain.NewDrawEllipse(uuid.New(), domain.NewPoint(0, 0), 5, 0, ' ', ' ', time.Now().UTC()),
+			expectedErr: domain.ErrInvalidSize,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			canvas := validCanvas()
+			err := canvas.AddDrawEllipse(tt.ellipse)
+			if tt.expectedErr != nil {
+				require.ErrorIs(t, err, tt.expectedErr)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestDrawTextGetters(t *testing.T) {
+	t.Parallel()
+
+	textID := uuid.New()
+	textTime := time.Now().UTC()
+	text := domain.NewDrawText(
+		textID,
+		domain.NewPoint(4, 5),
+		"Hello world",
+		6,
+		domain.AlignCenter,
+		textTime,
+	)
+
+	require.Equal(t, textID, text.ID())
+	require.Equal(t, 4, text.Point().X())
+	require.Equal(t, 5, text.Point().Y())
+	require.Equal(t, "Hello world", text.Text())
+	require.Equal(t, 6, text.WrapWidth())
+	require.Equal(t, domain.AlignCenter, text.Alignment())
+	require.Equal(t, 2, text.Height())
+	require.Equal(t, 6, text.Width())
+	require.Equal(t, textTime, text.CreatedAt())
+}
+
+func TestDrawText_Lines(t *testing.T) {
+	tests := []struct {
+		name           string
+		text           domain.DrawText
+		expectedLines  []string
+		expectedStarts []domain.Point
+	}{
+		{
+			name: `Given a text without wrap width,
+                   when the Lines method is called,
+                   then it returns a single line starting in the point of the text`,
+			text:           domain.NewDrawText(uuid.New(), domain.NewPoint(1, 2), "Hello world", 0, domain.AlignLeft, time.Now().UTC()),
+			expectedLines:  []string{"Hello world"},
+			expectedStarts: []domain.Point{domain.NewPoint(1, 2)},
+		},
+		{
+			name: `Given a text with line breaks, without wrap width and aligned to the right,
+                   when the Lines method is called,
+                   then it returns a line per line break aligned to the longest one`,
+			text:           domain.NewDrawText(uuid.New(), domain.NewPoint(0, 0), "Hello\nbig world", 0, domain.AlignRight, time.Now().UTC()),
+			expectedLines:  []string{"Hello", "big world"},
+			expectedStarts: []domain.Point{domain.NewPoint(4, 0), domain.NewPoint(0, 1)},
+		},
+		{
+			name: `Given a text with a wrap width and centered,
+                   when the Lines method is called,
+                   then it returns the text split between words and centered in the wrap width`,
+			text:           domain.NewDrawText(uuid.New(), domain.NewPoint(0, 0), "Hello big world", 7, domain.AlignCenter, time.Now().UTC()),
+			expectedLines:  []string{"Hello", "big", "world"},
+			expectedStarts: []domain.Point{domain.NewPoint(1, 0), domain.NewPoint(2, 1), domain.NewPoint(1, 2)},
+		},
+		{
+			name: `Given a text with a word longer than the wrap width,
+                   when the Lines method is called,
+                   then it returns the word split in several lines`,
+			text:           domain.NewDrawText(uuid.New(), domain.NewPoint(0, 0), "a ñandúes", 3, domain.AlignLeft, time.Now().UTC()),
+			expectedLines:  []string{"a", "ñan", "dúe", "s"},
+			expectedStarts: []domain.Point{domain.NewPoint(0, 0), domain.NewPoint(0, 1), domain.NewPoint(0, 2), domain.NewPoint(0, 3)},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			lines := tt.text.Lines()
+			require.Len(t, lines, len(tt.expectedLines))
+			for i := range lines {
+				require.Equal(t, tt.expectedLines[i], lines[i].Text())
+				require.Equal(t, tt.expectedStarts[i], lines[i].Point())
+			}
+		})
+	}
+}
+
+func TestCanvas_AddDrawText(t *testing.T) {
+	tests := []struct {
+		name        string
+		text        domain.DrawText
+		expectedErr error
+	}{
+		{
+			name: `Given a canvas with dimensions 30x30 and a text 30 characters long from point 0x29,
+                   when the AddDrawText method is called,
+                   then no error is returned`,
+			text: domain.NewDrawText(uuid.New(), domain.NewPoint(0, 29), strings.Repeat("a", 30), 0, domain.AlignLeft, time.Now().UTC()),
+		},
+		{
+			name: `Given a canvas with dimensions 30x30 and a text 31 characters long from point 0x0,
+                   when the AddDrawText method is called,
+                   then an out of bounds error is returned`,
+			text:        domain.NewDrawText(uuid.New(), domain.NewPoint(0, 0), strings.Repeat("a", 31), 0, domain.AlignLeft, time.Now().UTC()),
+			expectedErr: domain.ErrOutOfBounds,
+		},
+		{
+			name: `Given a canvas with dimensions 30x30 and a text that wraps in two lines from point 0x29,
+                   when the AddDrawText method is called,
+                   then an out of bounds error is returned`,
+			text:        domain.NewDrawText(uuid.New(), domain.NewPoint(0, 29), "Hello world", 5, domain.AlignLeft, time.Now().UTC()),
+			expectedErr: domain.ErrOutOfBounds,
+		},
+		{
+			name: `Given a canvas with dimensions 30x30 and a text with a wrap width of 31 from point 0x0,
+                   when the AddDrawText method is called,
+                   then an out of bounds error is returned`,
+			text:        domain.NewDrawText(uuid.New(), domain.NewPoint(0, 0), "Hello", 31, domain.AlignRight, time.Now().UTC()),
+			expectedErr: domain.ErrOutOfBounds,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			canvas := validCanvas()
+			err := canvas.AddDrawText(tt.text)
+			if tt.expectedErr != nil {
+				require.ErrorAs(t, err, &tt.expectedErr)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestDrawPolylineGetters(t *testing.T) {
+	t.Parallel()
+
+	polylineID := uuid.New()
+	polylineTime := time.Now().UTC()
+	points := []domain.Point{domain.NewPoint(0, 0), domain.NewPoint(4, 5), domain.NewPoint(10, 5)}
+	polyline := domain.NewDrawPolyline(
+		polylineID,
+		points,
+		'*',
+		polylineTime,
+	)
+
+	require.Equal(t, polylineID, polyline.ID())
+	require.Equal(t, points, polyline.Points())
+	require.Equal(t, '*', polyline.Outline())
+	require.Equal(t, polylineTime, polyline.CreatedAt())
+
+	points[0] = domain.NewPoint(1, 1)
+	require.Equal(t, domain.NewPoint(0, 0), polyline.Points()[0])
+}
+
+func TestDrawPolygonGetters(t *testing.T) {
+	t.Parallel()
+
+	polygonID := uuid.New()
+	polygonTime := time.Now().UTC()
+	points := []domain.Point{domain.NewPoint(0, 0), domain.NewPoint(4, 5), domain.NewPoint(10, 5)}
+	polygon := domain.NewDrawPolygon(
+		polygonID,
+		points,
+		'X',
+		'O',
+		polygonTime,
+	)
+
+	require.Equal(t, polygonID, polygon.ID())
+	require.Equal(t, points, polygon.Points())
+	require.Equal(t, 'X', polygon.Filler())
+	require.Equal(t, 'O', polygon.Outline())
+	require.Equal(t, polygonTime, polygon.CreatedAt())
+}
+
+func TestCanvas_AddDrawPolyline(t *testing.T) {
+	tests := []struct {
+		name        string
+		polyline    domain.DrawPolyline
+		expectedErr error
+	}{
+		{
+			name: `Given a canvas with dimensions 30x30 and a polyline with all its points inside the canvas,
+                   when the AddDrawPolyline method is called,
+                   then no error is returned`,
+			polyline: domain.NewDrawPolyline(
+				uuid.New(),
+				[]domain.Point{domain.NewPoint(0, 0), domain.NewPoint(29, 0), domain.NewPoint(29, 29)},
+				'*',
+				time.Now().UTC(),
+			),
+		},
+		{
+			name: `Given a canvas with dimensions 30x30 and a polyline with its last point outside the canvas,
+                   when the AddDrawPolyline method is called,
+                   then an out of bounds error is returned`,
+			polyline: domain.NewDrawPolyline(
+				uuid.New(),
+				[]domain.Point{domain.NewPoint(0, 0), domain.NewPoint(29, 0), domain.NewPoint(29, 30)},
+				'*',
+				time.Now().UTC(),
+			),
+			expectedErr: domain.ErrOutOfBounds,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			canvas := validCanvas()
+			err := canvas.AddDrawPolyline(tt.polyline)
+			if tt.expectedErr != nil {
+				require.ErrorAs(t, err, &tt.expectedErr)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestCanvas_AddDrawPolygon(t *testing.T) {
+	tests := []struct {
+		name        string
+		polygon     domain.DrawPolygon
+		expectedErr error
+	}{
+		{
+			name: `Given a canvas with dimensions 30x30 and a polygon with all its vertices inside the canvas,
+                   when the AddDrawPolygon method is called,
+                   then no error is returned`,
+			polygon: domain.NewDrawPolygon(
+				uuid.New(),
+				[]domain.Point{domain.NewPoint(0, 0), domain.NewPoint(29, 0), domain.NewPoint(15, 29)},
+				'X',
+				'O',
+				time.Now().UTC(),
+			),
+		},
+		{
+			name: `Given a canvas with dimensions 30x30 and a polygon with a vertex outside the canvas,
+                   when the AddDrawPolygon method is called,
+                   then an out of bounds error is returned`,
+			polygon: domain.NewDrawPolygon(
+				uuid.New(),
+				[]domain.Point{domain.NewPoint(0, 0), domain.NewPoint(30, 0), domain.NewPoint(15, 29)},
+				'X',
+				'O',
+				time.Now().UTC(),
+			),
+			expectedErr: domain.ErrOutOfBounds,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			canvas := validCanvas()
+			err := canvas.AddDrawPolygon(tt.polygon)
+			if tt.expectedErr != nil {
+				require.ErrorAs(t, err, &tt.expectedErr)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestCanvas_Resize(t *testing.T) {
+	tests := []struct {
+		name                   string
+		height                 int
+		width                  int
+		anchor                 domain.Anchor
+		clip                   bool
+		expectedHeight         int
+		expectedWidth          int
+		expectedRectanglePoint domain.Point
+		expectedRemoved        int
+		expectedErr            error
+	}{
+		{
+			name: `Given a canvas with dimensions 30x30 with a rectangle in point 0x0,
+                   when the Resize method is called to grow it to 40x50 anchored to the top left corner,
+                   then no error is returned and the rectangle stays in the same point`,
+			height:                 40,
+			width:                  50,
+			anchor:                 domain.AnchorTopLeft,
+			expectedHeight:         40,
+			expectedWidth:          50,
+			expectedRectanglePoint: domain.NewPoint(0, 0),
+		},
+		{
+			name: `Given a canvas with dimensions 30x30 with a rectangle in point 0x0,
+                   when the Resize method is called to grow it to 40x50 anchored to the bottom right corner,
+                   then no error is returned and the rectangle is moved to the point 20x10`,
+			height:                 40,
+			width:                  50,
+			anchor:                 domain.AnchorBottomRight,
+			expectedHeight:         40,
+			expectedWidth:          50,
+			expectedRectanglePoint: domain.NewPoint(20, 10),
+		},
+		{
+			name: `Given a canvas with dimensions 30x30 with a rectangle in point 0x0,
+                   when the Resize method is called to grow it to 35x30 anchored to the bottom left corner,
+                   then no error is returned and the rectangle is moved to the point 0x5`,
+			height:                 35,
+			width:                  30,
+			anchor:                 domain.AnchorBottomLeft,
+			expectedHeight:         35,
+			expectedWidth:          30,
+			expectedRectanglePoint: domain.NewPoint(0, 5),
+		},
+		{
+			name: `Given a canvas with dimensions 30x30 with a rectangle in point 0x0 and a fill in point 12x12,
+                   when the Resize method is called to shrink it to 20x20 anchored to the top left corner,
+                   then no error is returned`,
+			height:                 20,
+			width:                  20,
+			anchor:                 domain.AnchorTopLeft,
+			expectedHeight:         20,
+			expectedWidth:          20,
+			expectedRectanglePoint: domain.NewPoint(0, 0),
+		},
+		{
+			name: `Given a canvas with dimensions 30x30 with a rectangle in point 0x0 and a fill in point 12x12,
+                   when the Resize method is called to shrink it to 10x10 anchored to the top left corner,
+                   then an out of bounds error is returned and the canvas is not modified`,
+			height:                 10,
+			width:                  10,
+			anchor:                 domain.AnchorTopLeft,
+			expectedHeight:         30,
+			expectedWidth:          30,
+			expectedRectanglePoint: domain.NewPoint(0, 0),
+			expectedErr:            domain.ErrOutOfBounds,
+		},
+		{
+			name: `Given a canvas with dimensions 30x30 with a rectangle in point 0x0 and a fill in point 12x12,
+                   when the Resize method is called to shrink it to 10x10 anchored to the top left corner with clip,
+                   then no error is returned and the fill left outside of the canvas is removed`,
+			height:                 10,
+			width:                  10,
+			anchor:                 domain.AnchorTopLeft,
+			clip:                   true,
+			expectedHeight:         10,
+			expectedWidth:          10,
+			expectedRectanglePoint: domain.NewPoint(0, 0),
+			expectedRemoved:        1,
+		},
+		{
+			name: `Given a canvas with dimensions 30x30 with a rectangle in point 0x0,
+                   when the Resize method is called to shrink it to 20x20 anchored to the top right corner,
+                   then an out of bounds error is returned`,
+			height:                 20,
+			width:                  20,
+			anchor:                 domain.AnchorTopRight,
+			expectedHeight:         30,
+			expectedWidth:          30,
+			expectedRectanglePoint: domain.NewPoint(0, 0),
+			expectedErr:            domain.ErrOutOfBounds,
+		},
+		{
+			name: `Given a canvas with dimensions 30x30,
+                   when the Resize method is called with an unknown anchor,
+                   then an invalid anchor error is returned`,
+			height:                 40,
+			width:                  40,
+			anchor:                 domain.Anchor("center"),
+			expectedHeight:         30,
+			expectedWidth:          30,
+			expectedRectanglePoint: domain.NewPoint(0, 0),
+			expectedErr:            domain.ErrInvalidAnchor,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			canvas := validCanvas()
+			err := canvas.Resize(tt.height, tt.width, tt.anchor, tt.clip)
+			if tt.expectedErr != nil {
+				require.ErrorIs(t, err, tt.expectedErr)
+			} else {
+				require.NoError(t, err)
+			}
+
+			require.Equal(t, tt.expectedHeight, canvas.Height())
+			require.Equal(t, tt.expectedWidth, canvas.Width())
+			require.Len(t, canvas.Tasks(), 2-tt.expectedRemoved)
+			require.Len(t, canvas.RemovedTasks(), tt.expectedRemoved)
+			rectangle, ok := canvas.Tasks()[0].(domain.DrawRectangle)
+			require.True(t, ok)
+			require.Equal(t, tt.expectedRectanglePoint, rectangle.Point())
+		})
+	}
+}
+
+func TestCanvas_ResizeCropAndGrow(t *testing.T) {
+	t.Parallel()
+
+	rectangle := domain.NewDrawRectangle(uuid.New(), domain.NewPoint(0, 0), 10, 10, '.', '#', time.Now().UTC())
+	line := domain.NewDrawLine(uuid.New(), domain.NewPoint(5, 5), domain.NewPoint(25, 25), '*', time.Now().UTC())
+	fill := domain.NewFill(uuid.New(), domain.NewPoint(26, 26), '-', time.Now().UTC())
+	canvas := domain.NewCanvasWithHistory(uuid.New(), 30, 30, []domain.Task{rectangle, line, fill}, 2, time.Now().UTC())
+
+	// cropping the top left corner leaves the rectangle outside of the canvas, and the line partially inside of it
+	err := canvas.Resize(20, 20, domain.AnchorBottomRight, true)
+	require.NoError(t, err)
+	require.Equal(t, []uuid.UUID{rectangle.ID()}, canvas.RemovedTasks())
+	require.Len(t, canvas.History(), 2)
+	require.Equal(t, 1, canvas.Cursor())
+	cropped, ok := canvas.Tasks()[0].(domain.DrawLine)
+	require.True(t, ok)
+	require.Equal(t, domain.NewPoint(-5, -5), cropped.From())
+
+	// the line that was cropped does not stop the canvas from growing or shrinking without clip
+	err = canvas.Resize(25, 25, domain.AnchorTopLeft, false)
+	require.NoError(t, err)
+	err = canvas.Resize(30, 30, domain.AnchorBottomRight, false)
+	require.NoError(t, err)
+	grown, ok := canvas.Tasks()[0].(domain.DrawLine)
+	require.True(t, ok)
+	require.Equal(t, domain.NewPoint(0, 0), grown.From())
+
+	// but the tasks that fit in the canvas still do
+	err = canvas.Resize(5, 5, domain.AnchorTopLeft, false)
+	require.ErrorIs(t, err, domain.ErrOutOfBounds)
+}
+
+func TestCanvas_RemoveTask(t *testing.T) {
+	rectangle := validDrawRectangle()
+
+	tests := []struct {
+		name          string
+		taskID        uuid.UUID
+		expectedTasks int
+		expectedErr   error
+	}{
+		{
+			name: `Given a canvas with a rectangle and a fill,
+                   when the RemoveTask method is called with the ID of the rectangle,
+                   then no error is returned and only the fill is left`,
+			taskID:        rectangle.ID(),
+			expectedTasks: 1,
+		},
+		{
+			name: `Given a canvas with a rectangle and a fill,
+                   when the RemoveTask method is called with an unknown ID,
+                   then a task not found error is returned and the canvas is not modified`,
+			taskID:        uuid.New(),
+			expectedTasks: 2,
+			expectedErr:   domain.ErrTaskNotFound,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			canvas := domain.NewCanvas(uuid.New(), 30, 30, []domain.Task{rectangle, validFill()}, time.Now().UTC())
+			err := canvas.RemoveTask(tt.taskID)
+			if tt.expectedErr != nil {
+				require.ErrorIs(t, err, tt.expectedErr)
+				require.Empty(t, canvas.RemovedTasks())
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, []uuid.UUID{tt.taskID}, canvas.RemovedTasks())
+			}
+			require.Len(t, canvas.Tasks(), tt.expectedTasks)
+		})
+	}
+}
+
+func TestCanvas_UndoRedo(t *testing.T) {
+	t.Parallel()
+
+	rectangle := validDrawRectangle().WithSequence(1)
+	fill := validFill().WithSequence(2)
+	canvas := domain.NewCanvas(uuid.New(), 30, 30, []domain.Task{rectangle, fill}, time.Now().UTC())
+
+	err := canvas.Redo()
+	require.ErrorIs(t, err, domain.ErrNothingToRedo)
+
+	err = canvas.Undo()
+	require.NoError(t, err)
+	require.Equal(t, []domain.Task{rectangle}, canvas.Tasks())
+	require.Equal(t, []domain.Task{rectangle, fill}, canvas.History())
+	require.Equal(t, 1, canvas.Cursor())
+
+	err = canvas.Undo()
+	require.NoError(t, err)
+	require.Empty(t, canvas.Tasks())
+
+	err = canvas.Undo()
+	require.ErrorIs(t, err, domain.ErrNothingToUndo)
+
+	err = canvas.Redo()
+	require.NoError(t, err)
+	require.Equal(t, []domain.Task{rectangle}, canvas.Tasks())
+
+	// adding a new task discards the undone ones
+	line := domain.NewDrawLine(uuid.New(), domain.NewPoint(0, 0), domain.NewPoint(5, 5), '*', time.Now().UTC())
+	err = canvas.AddDrawLine(line)
+	require.NoError(t, err)
+	require.Equal(t, []domain.Task{rectangle, line.WithSequence(3)}, canvas.Tasks())
+	require.Equal(t, []domain.Task{rectangle, line.WithSequence(3)}, canvas.History())
+	require.Equal(t, []uuid.UUID{fill.ID()}, canvas.RemovedTasks())
+
+	err = canvas.Redo()
+	require.ErrorIs(t, err, domain.ErrNothingToRedo)
+}
+
+func TestCanvas_AddDuplicatedTask(t *testing.T) {
+	t.Parallel()
+
+	canvas := validCanvas()
+	rectangle := validDrawRectangle()
+
+	err := canvas.AddDrawRectangle(rectangle)
+	require.NoError(t, err)
+	err = canvas.AddDrawRectangle(rectangle)
+	require.ErrorIs(t, err, domain.ErrTaskAlreadyExists)
+
+	require.Len(t, canvas.Tasks(), 3)
+
+	// the undone tasks are kept for redo, so their IDs are taken too
+	err = canvas.Undo()
+	require.NoError(t, err)
+	err = canvas.AddDrawRectangle(rectangle)
+	require.ErrorIs(t, err, domain.ErrTaskAlreadyExists)
+
+	require.Len(t, canvas.History(), 3)
+	require.Empty(t, canvas.RemovedTasks())
+}
+
+func TestNewCanvasWithHistory(t *testing.T) {
+	t.Parallel()
+
+	tasks := []domain.Task{validDrawRectangle().WithSequence(1), validFill().WithSequence(2)}
+
+	canvas := domain.NewCanvasWithHistory(uuid.New(), 30, 30, tasks, 1, time.Now().UTC())
+	require.Equal(t, tasks[:1], canvas.Tasks())
+	require.Equal(t, tasks, canvas.History())
+
+	canvas = domain.NewCanvasWithHistory(uuid.New(), 30, 30, tasks, 5, time.Now().UTC())
+	require.Equal(t, tasks, canvas.Tasks())
+
+	canvas = domain.NewCanvasWithHistory(uuid.New(), 30, 30, tasks, -1, time.Now().UTC())
+	require.Empty(t, canvas.Tasks())
+}
+
+func TestCanvas_Sequence(t *testing.T) {
+	t.Parallel()
+
+	rectangle := validDrawRectangle()
+	fill := validFill().WithSequence(5)
+	line := domain.NewDrawLine(uuid.New(), domain.NewPoint(0, 0), domain.NewPoint(5, 5), '*', rectangle.CreatedAt())
+	history := []domain.Task{rectangle, fill, line}
+
+	// tasks without sequence get the one following the tasks before them, without changing the tasks given
+	canvas := domain.NewCanvasWithHistory(uuid.New(), 30, 30, history, len(history), time.Now().UTC())
+	require.Equal(t, []domain.Task{rectangle, fill, line}, history)
+	sequences := make([]int, len(canvas.History()))
+	for i, task := range canvas.History() {
+		sequence, ok := domain.TaskSequence(task)
+		require.True(t, ok)
+		sequences[i] = sequence
+	}
+	require.Equal(t, []int{1, 5, 6}, sequences)
+	require.Empty(t, canvas.AddedTasks())
+
+	// tasks added get the following sequence, even when they are created at the same time as the others
+	ellipse := domain.NewDrawEllipse(uuid.New(), domain.NewPoint(1, 1), 3, 3, '.', 'O', rectangle.CreatedAt())
+	err := canvas.AddDrawEllipse(ellipse)
+	require.NoError(t, err)
+	require.Equal(t, ellipse.WithSequence(7), canvas.Tasks()[3])
+
+	// the sequences are kept when the tasks are edited, undone, removed, or moved
+	err = canvas.UpdateTask(domain.NewDrawEllipse(ellipse.ID(), domain.NewPoint(2, 2), 3, 3, '.', 'O', time.Now().UTC()))
+	require.NoError(t, err)
+	err = canvas.Undo()
+	require.NoError(t, err)
+	err = canvas.RemoveTask(line.ID())
+	require.NoError(t, err)
+	err = canvas.Resize(40, 40, domain.AnchorBottomRight, false)
+	require.NoError(t, err)
+	sequence, ok := domain.TaskSequence(canvas.History()[2])
+	require.True(t, ok)
+	require.Equal(t, 7, sequence)
+
+	// a task added after undoing tasks gets a sequence following the ones discarded
+	err = canvas.AddDrawLine(line)
+	require.NoError(t, err)
+	sequence, ok = domain.TaskSequence(canvas.History()[2])
+	require.True(t, ok)
+	require.Equal(t, 8, sequence)
+	require.Equal(t, []uuid.UUID{ellipse.ID(), line.ID()}, canvas.AddedTasks())
+	require.Equal(t, 8, canvas.Sequence())
+
+	// a restored canvas keeps the highest sequence given, even when its last tasks have been removed, but never a lower one
+	restored := domain.NewCanvasWithHistory(uuid.New(), 30, 30, history, len(history), time.Now().UTC()).WithSequence(10)
+	require.Equal(t, 10, restored.Sequence())
+	require.Equal(t, 10, restored.WithSequence(3).Sequence())
+	err = restored.AddDrawEllipse(ellipse)
+	require.NoError(t, err)
+	sequence, ok = domain.TaskSequence(restored.Tasks()[3])
+	require.True(t, ok)
+	require.Equal(t, 11, sequence)
+
+	_, ok = domain.TaskSequence("I am not a task")
+	require.False(t, ok)
+}
+
+func TestCanvas_Changes(t *testing.T) {
+	t.Parallel()
+
+	rectangle := validDrawRectangle()
+	fill := validFill()
+
+	// the tasks of a new canvas are added to it, unlike the ones of a stored canvas
+	canvas := domain.NewCanvas(uuid.New(), 30, 30, []domain.Task{rectangle, fill}, time.Now().UTC())
+	require.Equal(t, []uuid.UUID{rectangle.ID(), fill.ID()}, canvas.AddedTasks())
+
+	canvas = domain.NewCanvasWithHistory(uuid.New(), 30, 30, canvas.History(), 2, time.Now().UTC())
+	require.Empty(t, canvas.AddedTasks())
+	require.Empty(t, canvas.EditedTasks())
+
+	// growing the canvas from the top left corner does not move the tasks
+	err := canvas.Resize(40, 40, domain.AnchorTopLeft, false)
+	require.NoError(t, err)
+	require.Empty(t, canvas.EditedTasks())
+
+	err = canvas.UpdateTask(domain.NewFill(fill.ID(), domain.NewPoint(1, 1), '*', time.Now().UTC()))
+	require.NoError(t, err)
+	require.Equal(t, []uuid.UUID{fill.ID()}, canvas.EditedTasks())
+
+	// the tasks moved are edited once
+	err = canvas.Resize(50, 50, domain.AnchorBottomRight, false)
+	require.NoError(t, err)
+	require.Equal(t, []uuid.UUID{fill.ID(), rectangle.ID()}, canvas.EditedTasks())
+}
+
+func TestCanvas_UpdateTask(t *testing.T) {
+	rectangle := validDrawRectangle().WithSequence(1)
+	fill := validFill().WithSequence(2)
+
+	tests := []struct {
+		name              string
+		task              domain.Task
+		expectedIndex     int
+		expectedCreatedAt time.Time
+		expectedErr       error
+	}{
+		{
+			name: `Given a canvas with a rectangle and a fill,
+                   when the UpdateTask method is called with a moved rectangle with the same ID,
+                   then no error is returned`,
+			task:              domain.NewDrawRectangle(rectangle.ID(), domain.NewPoint(5, 5), 3, 3, '.', '#', time.Now().UTC()),
+			expectedIndex:     0,
+			expectedCreatedAt: rectangle.CreatedAt(),
+		},
+		{
+			name: `Given a canvas with a rectangle and a fill,
+                   when the UpdateTask method is called with a restyled fill with the same ID,
+                   then no error is returned`,
+			task:              domain.NewFill(fill.ID(), fill.Point(), '*', time.Now().UTC()),
+			expectedIndex:     1,
+			expectedCreatedAt: fill.CreatedAt(),
+		},
+		{
+			name: `Given a canvas with a rectangle and a fill,
+                   when the UpdateTask method is called with a rectangle that does not fit in the canvas,
+                   then an out of bounds error is returned`,
+			task:        domain.NewDrawRectangle(rectangle.ID(), domain.NewPoint(25, 25), 10, 10, '.', '#', time.Now().UTC()),
+			expectedErr: domain.ErrOutOfBounds,
+		},
+		{
+			name: `Given a canvas with a rectangle and a fill,
+                   when the UpdateTask method is called with a fill that has the ID of the rectangle,
+                   then a task type mismatch error is returned`,
+			task:        domain.NewFill(rectangle.ID(), domain.NewPoint(1, 1), '*', time.Now().UTC()),
+			expectedErr: domain.ErrTaskTypeMismatch,
+		},
+		{
+			name: `Given a canvas with a rectangle and a fill,
+                   when the UpdateTask method is called with an unknown ID,
+                   then a task not found error is returned`,
+			task:        domain.NewFill(uuid.New(), domain.NewPoint(1, 1), '*', time.Now().UTC()),
+			expectedErr: domain.ErrTaskNotFound,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			canvas := domain.NewCanvas(uuid.New(), 30, 30, []domain.Task{rectangle, fill}, time.Now().UTC())
+			err := canvas.UpdateTask(tt.task)
+			if tt.expectedErr != nil {
+				require.ErrorIs(t, err, tt.expectedErr)
+				require.Equal(t, []domain.Task{rectangle, fill}, canvas.Tasks())
+				return
+			}
+
+			require.NoError(t, err)
+			require.Len(t, canvas.Tasks(), 2)
+			updated, ok := canvas.Tasks()[tt.expectedIndex].(interface{ CreatedAt() time.Time })
+			require.True(t, ok)
+			require.Equal(t, tt.expectedCreatedAt, updated.CreatedAt())
+			sequence, ok := domain.TaskSequence(updated)
+			require.True(t, ok)
+			require.Equal(t, tt.expectedIndex+1, sequence)
+		})
+	}
+}
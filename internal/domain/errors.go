@@ -2,5 +2,26 @@ package domain
 
 import "errors"
 
-// ErrOutOfBounds used when a draw rectangle or a fill operation does not fit into the canvas
+// ErrOutOfBounds used when a task does not fit into the canvas
 var ErrOutOfBounds = errors.New("task out of bounds")
+
+// ErrInvalidAnchor used when a canvas is resized using an unknown anchor
+var ErrInvalidAnchor = errors.New("invalid anchor")
+
+// ErrTaskNotFound used when a task is not present in the canvas
+var ErrTaskNotFound = errors.New("task not found")
+
+// ErrNothingToUndo used when there are no tasks to undo in the canvas
+var ErrNothingToUndo = errors.New("nothing to undo")
+
+// ErrNothingToRedo used when there are no undone tasks to redo in the canvas
+var ErrNothingToRedo = errors.New("nothing to redo")
+
+// ErrTaskTypeMismatch used when a task is replaced by a task of a different type
+var ErrTaskTypeMismatch = errors.New("task type mismatch")
+
+// ErrInvalidSize used when a task has a height or a width that is not positive
+var ErrInvalidSize = errors.New("task size must be positive")
+
+// ErrTaskAlreadyExists used when a task is added with the ID of a task already in the canvas, even an undone one
+var ErrTaskAlreadyExists = errors.New("task already exists")
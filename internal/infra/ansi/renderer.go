@@ -0,0 +1,72 @@
+package ansi
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/maitesin/sketch/internal/domain"
+	"github.com/maitesin/sketch/internal/infra/raster"
+)
+
+const (
+	foregroundCodes = 30
+	backgroundCodes = 40
+
+	reset = "\x1b[0m"
+)
+
+// Renderer draws the canvas as text for terminals, using ANSI escape codes for the colours of the tasks. Each run of
+// cells with the same style shares the same escape code, and the style is reset at the end of it
+type Renderer struct{}
+
+func (Renderer) Render(writer io.Writer, c domain.Canvas, viewport raster.Viewport) error {
+	grid, styles, err := raster.RasterizeWithStyles(c, viewport)
+	if err != nil {
+		return err
+	}
+
+	buffered := bufio.NewWriter(writer)
+	for y := range grid {
+		for left := 0; left < len(grid[y]); {
+			style := styles[y][left]
+			right := left
+			for right < len(grid[y]) && styles[y][right] == style {
+				right++
+			}
+
+			text := string(grid[y][left:right])
+			if style.IsDefault() {
+				buffered.WriteString(text)
+			} else {
+				fmt.Fprintf(buffered, "\x1b[%sm%s%s", codes(style), text, reset)
+			}
+			left = right
+		}
+		buffered.WriteString("\n")
+	}
+
+	return buffered.Flush()
+}
+
+// codes returns the parameters of the escape code that sets the colours of the style
+func codes(style domain.Style) string {
+	var params []string
+	if index, ok := colourIndex(style.Foreground()); ok {
+		params = append(params, fmt.Sprint(foregroundCodes+index))
+	}
+	if index, ok := colourIndex(style.Background()); ok {
+		params = append(params, fmt.Sprint(backgroundCodes+index))
+	}
+	return strings.Join(params, ";")
+}
+
+func colourIndex(colour domain.Colour) (int, bool) {
+	for i, c := range domain.Colours {
+		if c == colour {
+			return i, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,107 @@
+package ansi_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/maitesin/sketch/internal/domain"
+	"github.com/maitesin/sketch/internal/infra/ansi"
+	"github.com/maitesin/sketch/internal/infra/raster"
+	"github.com/stretchr/testify/require"
+)
+
+func canvasFixture1() domain.Canvas {
+	return domain.NewCanvas(
+		uuid.New(),
+		3,
+		5,
+		[]domain.Task{
+			domain.NewDrawRectangle(uuid.New(), domain.NewPoint(0, 0), 3, 3, '.', '#', time.Now().UTC()).
+				WithStyles(
+					domain.NewStyle(domain.ColourYellow, domain.ColourBlue),
+					domain.NewStyle(domain.ColourRed, domain.DefaultColour),
+				),
+			domain.NewFill(uuid.New(), domain.NewPoint(4, 0), '~', time.Now().UTC()).
+				WithStyle(domain.NewStyle(domain.DefaultColour, domain.ColourCyan)),
+			domain.NewDrawLine(uuid.New(), domain.NewPoint(3, 2), domain.NewPoint(4, 2), '-', time.Now().UTC()),
+		},
+		time.Now().UTC(),
+	)
+}
+
+func outputFixture1() string {
+	return "\x1b[31m###\x1b[0m\x1b[46m~~\x1b[0m\n" +
+		"\x1b[31m#\x1b[0m\x1b[33;44m.\x1b[0m\x1b[31m#\x1b[0m\x1b[46m~~\x1b[0m\n" +
+		"\x1b[31m###\x1b[0m--\n"
+}
+
+func canvasWithoutColours() domain.Canvas {
+	return domain.NewCanvas(
+		uuid.New(),
+		2,
+		3,
+		[]domain.Task{
+			domain.NewDrawRectangle(uuid.New(), domain.NewPoint(0, 0), 2, 2, '#', '#', time.Now().UTC()),
+		},
+		time.Now().UTC(),
+	)
+}
+
+func invalidCanvas() domain.Canvas {
+	return domain.NewCanvas(
+		uuid.New(),
+		10,
+		10,
+		[]domain.Task{"this will fail"},
+		time.Now().UTC(),
+	)
+}
+
+func TestRenderer(t *testing.T) {
+	tests := []struct {
+		name           string
+		canvas         domain.Canvas
+		expectedOutput string
+		expectedErr    error
+	}{
+		{
+			name: `Given a canvas with a styled rectangle, a styled fill, and a line without colours,
+                   when the render method is called from the ANSI renderer,
+                   then each run of cells with the same style is wrapped in its escape codes`,
+			canvas:         canvasFixture1(),
+			expectedOutput: outputFixture1(),
+		},
+		{
+			name: `Given a canvas without colours,
+                   when the render method is called from the ANSI renderer,
+                   then the output does not contain escape codes`,
+			canvas:         canvasWithoutColours(),
+			expectedOutput: "## \n## \n",
+		},
+		{
+			name: `Given a canvas with an invalid task,
+                   when the render method is called from the ANSI renderer,
+                   then an invalid task error is returned`,
+			canvas:      invalidCanvas(),
+			expectedErr: raster.ErrInvalidTask,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var output bytes.Buffer
+			err := ansi.Renderer{}.Render(&output, tt.canvas, raster.FullViewport(tt.canvas))
+			if tt.expectedErr != nil {
+				require.ErrorAs(t, err, &tt.expectedErr)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tt.expectedOutput, output.String())
+			}
+		})
+	}
+}
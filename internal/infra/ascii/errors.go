@@ -1,5 +1,5 @@
 package ascii
 
-import "errors"
+import "github.com/maitesin/sketch/internal/infra/raster"
 
-var ErrInvalidTask = errors.New("invalid task")
+var ErrInvalidTask = raster.ErrInvalidTask
@@ -2,12 +2,15 @@ package ascii_test
 
 import (
 	"bytes"
+	"io"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/maitesin/sketch/internal/domain"
 	"github.com/maitesin/sketch/internal/infra/ascii"
+	"github.com/maitesin/sketch/internal/infra/raster"
 	"github.com/stretchr/testify/require"
 )
 
@@ -128,6 +131,301 @@ OOOOOXXXXX-----------
 `
 }
 
+func canvasFixture4() domain.Canvas {
+	diagonal := domain.NewDrawLine(
+		uuid.New(),
+		domain.NewPoint(0, 0),
+		domain.NewPoint(5, 5),
+		'\\',
+		time.Now().UTC(),
+	)
+	vertical := domain.NewDrawLine(
+		uuid.New(),
+		domain.NewPoint(7, 0),
+		domain.NewPoint(7, 5),
+		'|',
+		time.Now().UTC(),
+	)
+	horizontal := domain.NewDrawLine(
+		uuid.New(),
+		domain.NewPoint(1, 0),
+		domain.NewPoint(6, 0),
+		'-',
+		time.Now().UTC(),
+	)
+	steep := domain.NewDrawLine(
+		uuid.New(),
+		domain.NewPoint(0, 5),
+		domain.NewPoint(2, 1),
+		'/',
+		time.Now().UTC(),
+	)
+	return domain.NewCanvas(
+		uuid.New(),
+		6,
+		8,
+		[]domain.Task{diagonal, vertical, horizontal, steep},
+		time.Now().UTC(),
+	)
+}
+
+func outputFixture4() string {
+	return `\------|
+ \/    |
+  /    |
+ / \   |
+ /  \  |
+/    \ |
+`
+}
+
+func canvasFixture5() domain.Canvas {
+	circle := domain.NewDrawEllipse(
+		uuid.New(),
+		domain.NewPoint(0, 0),
+		5,
+		7,
+		'.',
+		'*',
+		time.Now().UTC(),
+	)
+	even := domain.NewDrawEllipse(
+		uuid.New(),
+		domain.NewPoint(8, 1),
+		4,
+		6,
+		' ',
+		'O',
+		time.Now().UTC(),
+	)
+	flat := domain.NewDrawEllipse(
+		uuid.New(),
+		domain.NewPoint(8, 0),
+		1,
+		6,
+		'-',
+		'-',
+		time.Now().UTC(),
+	)
+	return domain.NewCanvas(
+		uuid.New(),
+		5,
+		14,
+		[]domain.Task{circle, even, flat},
+		time.Now().UTC(),
+	)
+}
+
+func outputFixture5() string {
+	return `  ***   ------
+ *...*   OOOO 
+*.....* O    O
+ *...*  O    O
+  ***    OOOO 
+`
+}
+
+func canvasFixture6() domain.Canvas {
+	box := domain.NewDrawRectangle(
+		uuid.New(),
+		domain.NewPoint(0, 0),
+		5,
+		13,
+		' ',
+		'#',
+		time.Now().UTC(),
+	)
+	caption := domain.NewDrawText(
+		uuid.New(),
+		domain.NewPoint(2, 1),
+		"Diseño de la caja",
+		9,
+		domain.AlignCenter,
+		time.Now().UTC(),
+	)
+	return domain.NewCanvas(
+		uuid.New(),
+		5,
+		13,
+		[]domain.Task{box, caption},
+		time.Now().UTC(),
+	)
+}
+
+func outputFixture6() string {
+	return `#############
+# Diseño de #
+#  la caja  #
+#           #
+#############
+`
+}
+
+func canvasFixture7() domain.Canvas {
+	triangle := domain.NewDrawPolygon(
+		uuid.New(),
+		[]domain.Point{domain.NewPoint(0, 0), domain.NewPoint(8, 0), domain.NewPoint(4, 4)},
+		'.',
+		'#',
+		time.Now().UTC(),
+	)
+	arrow := domain.NewDrawPolyline(
+		uuid.New(),
+		[]domain.Point{domain.NewPoint(10, 0), domain.NewPoint(14, 2), domain.NewPoint(10, 4)},
+		'>',
+		time.Now().UTC(),
+	)
+	return domain.NewCanvas(
+		uuid.New(),
+		5,
+		16,
+		[]domain.Task{triangle, arrow},
+		time.Now().UTC(),
+	)
+}
+
+func outputFixture7() string {
+	return `######### >     
+ #.....#   >>   
+  #...#      >> 
+   #.#      >>  
+    #     >>    
+`
+}
+
+func canvasFixture8(t *testing.T) domain.Canvas {
+	t.Helper()
+
+	canvas := canvasFixture7()
+	err := canvas.Resize(3, 12, domain.AnchorTopLeft, true)
+	require.NoError(t, err)
+
+	return canvas
+}
+
+func outputFixture8() string {
+	return `######### > 
+ #.....#   >
+  #...#     
+`
+}
+
+func canvasFixture9(t *testing.T, connectivity domain.Connectivity) domain.Canvas {
+	t.Helper()
+
+	canvas := domain.NewCanvas(uuid.New(), 5, 5, nil, time.Now().UTC())
+	err := canvas.AddDrawLine(domain.NewDrawLine(uuid.New(), domain.NewPoint(0, 4), domain.NewPoint(4, 0), '/', time.Now().UTC()))
+	require.NoError(t, err)
+	err = canvas.AddFill(domain.NewFillWithMode(
+		uuid.New(),
+		domain.NewPoint(0, 0),
+		'.',
+		connectivity,
+		domain.FloodFillMode,
+		0,
+		time.Now().UTC(),
+	))
+	require.NoError(t, err)
+
+	return canvas
+}
+
+func outputFixture9FourConnected() string {
+	return `..../
+.../ 
+../  
+./   
+/    
+`
+}
+
+func outputFixture9EightConnected() string {
+	return `..../
+.../.
+../..
+./...
+/....
+`
+}
+
+func canvasFixture10(t *testing.T, mode domain.FillMode) domain.Canvas {
+	t.Helper()
+
+	canvas := domain.NewCanvas(uuid.New(), 5, 7, nil, time.Now().UTC())
+	err := canvas.AddDrawRectangle(domain.NewDrawRectangle(uuid.New(), domain.NewPoint(0, 0), 5, 7, 'o', '#', time.Now().UTC()))
+	require.NoError(t, err)
+	err = canvas.AddDrawText(domain.NewDrawText(uuid.New(), domain.NewPoint(2, 2), "ab", 0, domain.AlignLeft, time.Now().UTC()))
+	require.NoError(t, err)
+	err = canvas.AddFill(domain.NewFillWithMode(
+		uuid.New(),
+		domain.NewPoint(1, 1),
+		'.',
+		domain.FourConnected,
+		mode,
+		'#',
+		time.Now().UTC(),
+	))
+	require.NoError(t, err)
+
+	return canvas
+}
+
+func outputFixture10Flood() string {
+	return `#######
+#.....#
+#.ab..#
+#.....#
+#######
+`
+}
+
+func outputFixture10Boundary() string {
+	return `#######
+#.....#
+#.....#
+#.....#
+#######
+`
+}
+
+func canvasFixture11(t *testing.T) domain.Canvas {
+	t.Helper()
+
+	canvas := domain.NewCanvas(uuid.New(), 6, 10, nil, time.Now().UTC())
+	rectangle := domain.NewDrawRectangle(uuid.New(), domain.NewPoint(1, 1), 4, 6, ' ', '#', time.Now().UTC())
+	err := canvas.AddDrawRectangle(rectangle.WithPattern(domain.NewPattern([]string{"/\\"}, domain.PatternAnchorShape)))
+	require.NoError(t, err)
+	fill := domain.NewFill(uuid.New(), domain.NewPoint(0, 0), ' ', time.Now().UTC())
+	err = canvas.AddFill(fill.WithPattern(domain.NewPattern([]string{"xo", "ox"}, domain.PatternAnchorCanvas)))
+	require.NoError(t, err)
+
+	return canvas
+}
+
+func outputFixture11() string {
+	return `xoxoxoxoxo
+o######xox
+x#\/\/#oxo
+o#\/\/#xox
+x######oxo
+oxoxoxoxox
+`
+}
+
+// canvasFixture12 is the canvas from the fixture 1 with colours, that are ignored by the ASCII renderer
+func canvasFixture12() domain.Canvas {
+	tasks := canvasFixture1().Tasks()
+	for i, task := range tasks {
+		tasks[i] = task.(domain.DrawRectangle).WithStyles(
+			domain.NewStyle(domain.ColourYellow, domain.ColourBlue),
+			domain.NewStyle(domain.ColourRed, domain.DefaultColour),
+		)
+	}
+	tasks = append(tasks, domain.NewFill(uuid.New(), domain.NewPoint(4, 3), 'X', time.Now().UTC()).
+		WithStyle(domain.NewStyle(domain.ColourGreen, domain.ColourBlack)))
+	return domain.NewCanvas(uuid.New(), 9, 24, tasks, time.Now().UTC())
+}
+
 func invalidCanvas() domain.Canvas {
 	return domain.NewCanvas(
 		uuid.New(),
@@ -166,6 +464,83 @@ func TestRenderer(t *testing.T) {
 			canvas:         canvasFixture3(t),
 			expectedOutput: outputFixture3(),
 		},
+		{
+			name: `Given the canvas from the fixture 4 containing lines in several directions,
+                   when the render method is called from the ASCII renderer,
+                   then it outputs the lines rasterised`,
+			canvas:         canvasFixture4(),
+			expectedOutput: outputFixture4(),
+		},
+		{
+			name: `Given the canvas from the fixture 5 containing ellipses with odd and even dimensions,
+                   when the render method is called from the ASCII renderer,
+                   then it outputs the ellipses rasterised`,
+			canvas:         canvasFixture5(),
+			expectedOutput: outputFixture5(),
+		},
+		{
+			name: `Given the canvas from the fixture 6 containing a wrapped and centered text inside a rectangle,
+                   when the render method is called from the ASCII renderer,
+                   then it outputs the text inside the rectangle`,
+			canvas:         canvasFixture6(),
+			expectedOutput: outputFixture6(),
+		},
+		{
+			name: `Given the canvas from the fixture 7 containing a polygon and a polyline,
+                   when the render method is called from the ASCII renderer,
+                   then it outputs the filled polygon and the open polyline`,
+			canvas:         canvasFixture7(),
+			expectedOutput: outputFixture7(),
+		},
+		{
+			name: `Given the canvas from the fixture 8 that has been cropped while containing a polygon and a polyline,
+                   when the render method is called from the ASCII renderer,
+                   then it outputs the part of the polygon and the polyline inside the canvas`,
+			canvas:         canvasFixture8(t),
+			expectedOutput: outputFixture8(),
+		},
+		{
+			name: `Given the canvas from the fixture 9 with a 4-connected fill next to a diagonal line,
+                   when the render method is called from the ASCII renderer,
+                   then it outputs only the side of the line where the fill starts filled`,
+			canvas:         canvasFixture9(t, domain.FourConnected),
+			expectedOutput: outputFixture9FourConnected(),
+		},
+		{
+			name: `Given the canvas from the fixture 9 with an 8-connected fill next to a diagonal line,
+                   when the render method is called from the ASCII renderer,
+                   then it outputs both sides of the line filled`,
+			canvas:         canvasFixture9(t, domain.EightConnected),
+			expectedOutput: outputFixture9EightConnected(),
+		},
+		{
+			name: `Given the canvas from the fixture 10 with a flood fill inside a rectangle containing a text,
+                   when the render method is called from the ASCII renderer,
+                   then it outputs the rectangle filled around the text`,
+			canvas:         canvasFixture10(t, domain.FloodFillMode),
+			expectedOutput: outputFixture10Flood(),
+		},
+		{
+			name: `Given the canvas from the fixture 10 with a boundary fill inside a rectangle containing a text,
+                   when the render method is called from the ASCII renderer,
+                   then it outputs the whole rectangle filled until its outline`,
+			canvas:         canvasFixture10(t, domain.BoundaryFillMode),
+			expectedOutput: outputFixture10Boundary(),
+		},
+		{
+			name: `Given the canvas from the fixture 11 with a rectangle and a fill using patterns,
+                   when the render method is called from the ASCII renderer,
+                   then it outputs the rectangle and the fill painted with the tiles of their patterns`,
+			canvas:         canvasFixture11(t),
+			expectedOutput: outputFixture11(),
+		},
+		{
+			name: `Given the canvas from the fixture 12 with colours in its rectangles and fill,
+                   when the render method is called from the ASCII renderer,
+                   then it outputs the same as without colours`,
+			canvas:         canvasFixture12(),
+			expectedOutput: outputFixture1(),
+		},
 		{
 			name: `Given a canvas with an invalid task,
                    when the render method is called from the ASCII renderer,
@@ -181,7 +556,7 @@ func TestRenderer(t *testing.T) {
 
 			re := ascii.Renderer{}
 			writer := &bytes.Buffer{}
-			err := re.Render(writer, tt.canvas)
+			err := re.Render(writer, tt.canvas, raster.FullViewport(tt.canvas))
 			if tt.expectedErr != nil {
 				require.ErrorAs(t, err, &tt.expectedErr)
 			} else {
@@ -191,3 +566,313 @@ func TestRenderer(t *testing.T) {
 		})
 	}
 }
+
+const largeCanvasSize = 2000
+
+func canvasLargeFixture3(t testing.TB) domain.Canvas {
+	t.Helper()
+
+	fill := domain.NewFill(
+		uuid.New(),
+		domain.NewPoint(largeCanvasSize-1, 0),
+		'-',
+		time.Now().UTC(),
+	)
+	canvas := canvasFixture2()
+	err := canvas.Resize(largeCanvasSize, largeCanvasSize, domain.AnchorBottomLeft, false)
+	require.NoError(t, err)
+	err = canvas.AddFill(fill)
+	require.NoError(t, err)
+	return canvas
+}
+
+// outputLargeFixture3 is the output of the fixture 3 placed in the bottom left corner of a large canvas, where the
+// flood fill has to go around it to reach the same cells
+func outputLargeFixture3() string {
+	var builder strings.Builder
+	fixture := strings.Split(strings.TrimSuffix(outputFixture3(), "\n"), "\n")
+	for i := 0; i < largeCanvasSize-len(fixture); i++ {
+		builder.WriteString(strings.Repeat("-", largeCanvasSize) + "\n")
+	}
+	for _, line := range fixture {
+		builder.WriteString(line + strings.Repeat("-", largeCanvasSize-len(line)) + "\n")
+	}
+	return builder.String()
+}
+
+func canvasLargeEmptyFill(t testing.TB) domain.Canvas {
+	t.Helper()
+
+	canvas := domain.NewCanvas(uuid.New(), largeCanvasSize, largeCanvasSize, nil, time.Now().UTC())
+	err := canvas.AddFill(domain.NewFill(uuid.New(), domain.NewPoint(largeCanvasSize/2, largeCanvasSize/2), '~', time.Now().UTC()))
+	require.NoError(t, err)
+	return canvas
+}
+
+func outputLargeEmptyFill() string {
+	return strings.Repeat(strings.Repeat("~", largeCanvasSize)+"\n", largeCanvasSize)
+}
+
+// canvasSerpentine is a canvas split by vertical walls that leave a gap alternatively at the top and the bottom, so
+// the flood fill has to follow a single path that goes up and down through the whole canvas
+func canvasSerpentine(t testing.TB, size int) domain.Canvas {
+	t.Helper()
+
+	canvas := domain.NewCanvas(uuid.New(), size, size, nil, time.Now().UTC())
+	for x := 1; x < size; x += 2 {
+		from, to := domain.NewPoint(x, 1), domain.NewPoint(x, size-1)
+		if (x/2)%2 == 1 {
+			from, to = domain.NewPoint(x, 0), domain.NewPoint(x, size-2)
+		}
+		err := canvas.AddDrawLine(domain.NewDrawLine(uuid.New(), from, to, '#', time.Now().UTC()))
+		require.NoError(t, err)
+	}
+	err := canvas.AddFill(domain.NewFill(uuid.New(), domain.NewPoint(0, 0), '.', time.Now().UTC()))
+	require.NoError(t, err)
+	return canvas
+}
+
+func outputSerpentine(size int) string {
+	var builder strings.Builder
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			wall := x%2 == 1 && ((x/2)%2 == 0 && y >= 1 || (x/2)%2 == 1 && y <= size-2)
+			if wall {
+				builder.WriteRune('#')
+			} else {
+				builder.WriteRune('.')
+			}
+		}
+		builder.WriteRune('\n')
+	}
+	return builder.String()
+}
+
+func canvasFillWithSameRune(t testing.TB) domain.Canvas {
+	t.Helper()
+
+	canvas := domain.NewCanvas(uuid.New(), 3, 4, nil, time.Now().UTC())
+	err := canvas.AddFill(domain.NewFill(uuid.New(), domain.NewPoint(1, 1), ' ', time.Now().UTC()))
+	require.NoError(t, err)
+	return canvas
+}
+
+func TestRenderer_Fill(t *testing.T) {
+	tests := []struct {
+		name           string
+		canvas         domain.Canvas
+		expectedOutput string
+	}{
+		{
+			name: `Given the canvas from the fixture 3 placed in the corner of a very large canvas,
+                   when the render method is called from the ASCII renderer,
+                   then it outputs the same cells filled as in the fixture 3 and the rest of the canvas filled`,
+			canvas:         canvasLargeFixture3(t),
+			expectedOutput: outputLargeFixture3(),
+		},
+		{
+			name: `Given a very large empty canvas with a fill,
+                   when the render method is called from the ASCII renderer,
+                   then it outputs the whole canvas filled`,
+			canvas:         canvasLargeEmptyFill(t),
+			expectedOutput: outputLargeEmptyFill(),
+		},
+		{
+			name: `Given a large canvas split in a single path that goes up and down through the whole canvas with a fill,
+                   when the render method is called from the ASCII renderer,
+                   then it outputs the whole path filled`,
+			canvas:         canvasSerpentine(t, 1000),
+			expectedOutput: outputSerpentine(1000),
+		},
+		{
+			name: `Given a canvas with a fill using the same rune as the cell where it starts,
+                   when the render method is called from the ASCII renderer,
+                   then it outputs the canvas unchanged`,
+			canvas:         canvasFillWithSameRune(t),
+			expectedOutput: "    \n    \n    \n",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			re := ascii.Renderer{}
+			writer := &bytes.Buffer{}
+			err := re.Render(writer, tt.canvas, raster.FullViewport(tt.canvas))
+			require.NoError(t, err)
+			require.Equal(t, tt.expectedOutput, writer.String())
+		})
+	}
+}
+
+func BenchmarkRenderer_FillLargeEmptyCanvas(b *testing.B) {
+	canvas := canvasLargeEmptyFill(b)
+	re := ascii.Renderer{}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		err := re.Render(io.Discard, canvas, raster.FullViewport(canvas))
+		require.NoError(b, err)
+	}
+}
+
+func BenchmarkRenderer_FillSerpentine(b *testing.B) {
+	canvas := canvasSerpentine(b, largeCanvasSize)
+	re := ascii.Renderer{}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		err := re.Render(io.Discard, canvas, raster.FullViewport(canvas))
+		require.NoError(b, err)
+	}
+}
+
+func TestRenderer_Viewport(t *testing.T) {
+	t.Parallel()
+
+	re := ascii.Renderer{}
+	writer := &bytes.Buffer{}
+	err := re.Render(writer, canvasFixture1(), raster.Viewport{X: 2, Y: 2, Width: 10, Height: 4})
+	require.NoError(t, err)
+	require.Equal(t, ` @@@@@    
+ @XXX@  XX
+ @@@@@  X0
+        X0
+`, writer.String())
+
+	err = re.Render(writer, canvasFixture1(), raster.Viewport{X: 20, Y: 0, Width: 10, Height: 4})
+	require.ErrorIs(t, err, raster.ErrInvalidViewport)
+}
+
+// canvasLargeRectangles returns a large canvas covered by rectangles, without fills
+func canvasLargeRectangles(t testing.TB) domain.Canvas {
+	t.Helper()
+
+	canvas := domain.NewCanvas(uuid.New(), largeCanvasSize, largeCanvasSize, nil, time.Now().UTC())
+	for i := 0; i < largeCanvasSize/2; i += 100 {
+		size := largeCanvasSize - 2*i
+		err := canvas.AddDrawRectangle(
+			domain.NewDrawRectangle(uuid.New(), domain.NewPoint(i, i), size, size, '.', '#', time.Now().UTC()))
+		require.NoError(t, err)
+	}
+	return canvas
+}
+
+func BenchmarkRenderer_LargeCanvas(b *testing.B) {
+	canvas := canvasLargeRectangles(b)
+	re := ascii.Renderer{}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		err := re.Render(io.Discard, canvas, raster.FullViewport(canvas))
+		require.NoError(b, err)
+	}
+}
+
+func BenchmarkRenderer_LargeCanvasViewport(b *testing.B) {
+	canvas := canvasLargeRectangles(b)
+	re := ascii.Renderer{}
+	viewport := raster.Viewport{X: largeCanvasSize / 2, Y: largeCanvasSize / 2, Width: 80, Height: 24}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		err := re.Render(io.Discard, canvas, viewport)
+		require.NoError(b, err)
+	}
+}
+
+// canvasLargeFills returns a large canvas covered by rectangles, with a fill either before or after them
+func canvasLargeFills(t testing.TB, fillLast bool) domain.Canvas {
+	t.Helper()
+
+	canvas := canvasLargeRectangles(t)
+	fill := domain.NewFill(uuid.New(), domain.NewPoint(largeCanvasSize/2, largeCanvasSize/2), '~', time.Now().UTC())
+	if fillLast {
+		err := canvas.AddFill(fill)
+		require.NoError(t, err)
+		return canvas
+	}
+
+	tasks := append([]domain.Task{fill}, canvas.Tasks()...)
+	return domain.NewCanvas(canvas.ID(), canvas.Height(), canvas.Width(), tasks, canvas.CreatedAt())
+}
+
+// BenchmarkRenderer_LargeCanvasViewportFillFirst renders a viewport of a canvas whose only fill is its first task, so
+// only the fill is drawn in the whole canvas
+func BenchmarkRenderer_LargeCanvasViewportFillFirst(b *testing.B) {
+	canvas := canvasLargeFills(b, false)
+	re := ascii.Renderer{}
+	viewport := raster.Viewport{X: largeCanvasSize / 2, Y: largeCanvasSize / 2, Width: 80, Height: 24}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		err := re.Render(io.Discard, canvas, viewport)
+		require.NoError(b, err)
+	}
+}
+
+// BenchmarkRenderer_LargeCanvasViewportFillLast renders a viewport of a canvas whose last task is a fill, so all its
+// tasks are drawn in the whole canvas
+func BenchmarkRenderer_LargeCanvasViewportFillLast(b *testing.B) {
+	canvas := canvasLargeFills(b, true)
+	re := ascii.Renderer{}
+	viewport := raster.Viewport{X: largeCanvasSize / 2, Y: largeCanvasSize / 2, Width: 80, Height: 24}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		err := re.Render(io.Discard, canvas, viewport)
+		require.NoError(b, err)
+	}
+}
+
+const (
+	manyTasks        = 10000
+	manyTasksSize    = 200
+	snapshottedTasks = 9500
+)
+
+// canvasManyTasks returns a canvas with ten thousand overlapping rectangles and lines
+func canvasManyTasks(t testing.TB) domain.Canvas {
+	t.Helper()
+
+	tasks := make([]domain.Task, manyTasks)
+	for i := range tasks {
+		x, y := (i*37)%(manyTasksSize-40), (i*53)%(manyTasksSize-40)
+		if i%2 == 0 {
+			tasks[i] = domain.NewDrawRectangle(uuid.New(), domain.NewPoint(x, y), 40, 40, rune('a'+i%26), '#', time.Now().UTC())
+		} else {
+			tasks[i] = domain.NewDrawLine(uuid.New(), domain.NewPoint(x, y), domain.NewPoint(y, x), '*', time.Now().UTC())
+		}
+	}
+	return domain.NewCanvas(uuid.New(), manyTasksSize, manyTasksSize, tasks, time.Now().UTC())
+}
+
+func BenchmarkRenderer_ManyTasks(b *testing.B) {
+	canvas := canvasManyTasks(b)
+	re := ascii.Renderer{}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		err := re.Render(io.Discard, canvas, raster.FullViewport(canvas))
+		require.NoError(b, err)
+	}
+}
+
+// BenchmarkRenderer_ManyTasksSnapshot renders the same canvas resumed from a snapshot that leaves the tasks added
+// after it to be drawn, including the check that the snapshot still matches the tasks of the canvas
+func BenchmarkRenderer_ManyTasksSnapshot(b *testing.B) {
+	canvas := canvasManyTasks(b)
+	re := ascii.Renderer{}
+	snapshot, err := raster.NewSnapshot(domain.NewCanvas(
+		canvas.ID(), canvas.Height(), canvas.Width(), canvas.Tasks()[:snapshottedTasks], canvas.CreatedAt()))
+	require.NoError(b, err)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		resumed, ok := snapshot.Resume(canvas)
+		require.True(b, ok)
+		err := re.Render(io.Discard, resumed, raster.FullViewport(resumed))
+		require.NoError(b, err)
+	}
+}
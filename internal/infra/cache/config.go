@@ -0,0 +1,7 @@
+package cache
+
+// Config defines the configuration for the render cache
+type Config struct {
+	// Capacity is the maximum number of bytes of renderings stored
+	Capacity int
+}
@@ -0,0 +1,164 @@
+package cache
+
+import (
+	"container/list"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/maitesin/sketch/internal/app"
+)
+
+// LRU is an in-memory render cache that evicts the least recently used renderings when the size of the renderings
+// stored exceeds its capacity
+type LRU struct {
+	mutex    sync.Mutex
+	capacity int
+	size     int
+	order    *list.List
+	entries  map[app.RenderKey]*list.Element
+	byCanvas map[uuid.UUID]map[app.RenderKey]*list.Element
+	versions map[uuid.UUID]uint64
+	floor    uint64
+	clock    uint64
+	hits     uint64
+	misses   uint64
+}
+
+// maxIdleVersions is the number of versions of canvases without renderings kept before they are discarded
+const maxIdleVersions = 1024
+
+type entry struct {
+	key       app.RenderKey
+	rendering []byte
+}
+
+// NewLRU is a constructor. The capacity is the maximum number of bytes of renderings stored
+func NewLRU(capacity int) *LRU {
+	return &LRU{
+		capacity: capacity,
+		order:    list.New(),
+		entries:  map[app.RenderKey]*list.Element{},
+		byCanvas: map[uuid.UUID]map[app.RenderKey]*list.Element{},
+		versions: map[uuid.UUID]uint64{},
+	}
+}
+
+// Version returns the current version of the canvas
+func (l *LRU) Version(canvasID uuid.UUID) uint64 {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	return l.version(canvasID)
+}
+
+// version returns the version of the canvas when it has been invalidated since the versions were last discarded, or
+// the floor shared by the rest of the canvases otherwise
+func (l *LRU) version(canvasID uuid.UUID) uint64 {
+	if version, ok := l.versions[canvasID]; ok {
+		return version
+	}
+	return l.floor
+}
+
+// Get returns the rendering for the key, and marks it as the most recently used
+func (l *LRU) Get(key app.RenderKey) ([]byte, bool) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	element, ok := l.entries[key]
+	if !ok {
+		l.misses++
+		return nil, false
+	}
+
+	l.hits++
+	l.order.MoveToFront(element)
+	return element.Value.(entry).rendering, true
+}
+
+// Set stores the rendering for the key. Renderings of outdated versions and renderings larger than the capacity are
+// discarded
+func (l *LRU) Set(key app.RenderKey, rendering []byte) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if key.Version != l.version(key.CanvasID) || len(rendering) > l.capacity {
+		return
+	}
+
+	if element, ok := l.entries[key]; ok {
+		l.remove(element)
+	}
+
+	element := l.order.PushFront(entry{key: key, rendering: rendering})
+	l.entries[key] = element
+	if l.byCanvas[key.CanvasID] == nil {
+		l.byCanvas[key.CanvasID] = map[app.RenderKey]*list.Element{}
+	}
+	l.byCanvas[key.CanvasID][key] = element
+	l.size += len(rendering)
+
+	for l.size > l.capacity {
+		l.remove(l.order.Back())
+	}
+}
+
+// Invalidate moves the canvas to a new version and discards all its renderings
+func (l *LRU) Invalidate(canvasID uuid.UUID) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.clock++
+	l.versions[canvasID] = l.clock
+	for _, element := range l.byCanvas[canvasID] {
+		l.remove(element)
+	}
+
+	l.discardIdleVersions()
+}
+
+// discardIdleVersions forgets the versions of the canvases without renderings once there are too many of them, so
+// they do not grow with every canvas ever invalidated. The floor is moved past all of them, so the version of a canvas
+// never goes back and the renderings started before its last invalidation are still discarded. The canvases with
+// renderings keep their version, so their renderings are still found
+func (l *LRU) discardIdleVersions() {
+	if len(l.versions) <= len(l.byCanvas)+maxIdleVersions {
+		return
+	}
+
+	for canvasID := range l.versions {
+		if _, ok := l.byCanvas[canvasID]; !ok {
+			delete(l.versions, canvasID)
+		}
+	}
+	for canvasID := range l.byCanvas {
+		if _, ok := l.versions[canvasID]; !ok {
+			l.versions[canvasID] = l.floor
+		}
+	}
+	l.floor = l.clock
+}
+
+// Stats returns the counters of the cache
+func (l *LRU) Stats() app.RenderCacheStats {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	return app.RenderCacheStats{
+		Hits:    l.hits,
+		Misses:  l.misses,
+		Entries: len(l.entries),
+		Size:    l.size,
+	}
+}
+
+func (l *LRU) remove(element *list.Element) {
+	key := element.Value.(entry).key
+	l.order.Remove(element)
+	delete(l.entries, key)
+	delete(l.byCanvas[key.CanvasID], key)
+	if len(l.byCanvas[key.CanvasID]) == 0 {
+		delete(l.byCanvas, key.CanvasID)
+	}
+	l.size -= len(element.Value.(entry).rendering)
+}
@@ -0,0 +1,195 @@
+package cache_test
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/maitesin/sketch/internal/app"
+	"github.com/maitesin/sketch/internal/infra/cache"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLRU(t *testing.T) {
+	canvasID := uuid.New()
+	otherCanvasID := uuid.New()
+	key := app.RenderKey{CanvasID: canvasID, Variant: "text/plain"}
+	otherKey := app.RenderKey{CanvasID: otherCanvasID, Variant: "text/plain"}
+
+	tests := []struct {
+		name              string
+		capacity          int
+		actions           func(*cache.LRU)
+		key               app.RenderKey
+		expectedRendering []byte
+		expectedStats     app.RenderCacheStats
+	}{
+		{
+			name: `Given an empty cache,
+                   when a rendering is requested,
+                   then it is a miss`,
+			capacity:      10,
+			actions:       func(*cache.LRU) {},
+			key:           key,
+			expectedStats: app.RenderCacheStats{Misses: 1},
+		},
+		{
+			name: `Given a cache with a rendering,
+                   when the rendering is requested,
+                   then it is a hit`,
+			capacity: 10,
+			actions: func(lru *cache.LRU) {
+				lru.Set(key, []byte("abc"))
+			},
+			key:               key,
+			expectedRendering: []byte("abc"),
+			expectedStats:     app.RenderCacheStats{Hits: 1, Entries: 1, Size: 3},
+		},
+		{
+			name: `Given a cache with a rendering that is replaced,
+                   when the rendering is requested,
+                   then the latest rendering is returned`,
+			capacity: 10,
+			actions: func(lru *cache.LRU) {
+				lru.Set(key, []byte("abc"))
+				lru.Set(key, []byte("abcd"))
+			},
+			key:               key,
+			expectedRendering: []byte("abcd"),
+			expectedStats:     app.RenderCacheStats{Hits: 1, Entries: 1, Size: 4},
+		},
+		{
+			name: `Given a cache with a rendering of a canvas that has been invalidated,
+                   when the rendering is requested,
+                   then it is a miss`,
+			capacity: 10,
+			actions: func(lru *cache.LRU) {
+				lru.Set(key, []byte("abc"))
+				lru.Set(otherKey, []byte("def"))
+				lru.Invalidate(canvasID)
+			},
+			key:           key,
+			expectedStats: app.RenderCacheStats{Misses: 1, Entries: 1, Size: 3},
+		},
+		{
+			name: `Given a cache with a rendering of another canvas that has been invalidated,
+                   when the rendering is requested,
+                   then it is a hit`,
+			capacity: 10,
+			actions: func(lru *cache.LRU) {
+				lru.Set(key, []byte("abc"))
+				lru.Invalidate(otherCanvasID)
+			},
+			key:               key,
+			expectedRendering: []byte("abc"),
+			expectedStats:     app.RenderCacheStats{Hits: 1, Entries: 1, Size: 3},
+		},
+		{
+			name: `Given a cache where a rendering of an outdated version has been stored,
+                   when the rendering is requested,
+                   then it is a miss`,
+			capacity: 10,
+			actions: func(lru *cache.LRU) {
+				lru.Invalidate(canvasID)
+				lru.Set(key, []byte("abc"))
+			},
+			key:           key,
+			expectedStats: app.RenderCacheStats{Misses: 1},
+		},
+		{
+			name: `Given a cache where a rendering of the current version has been stored after an invalidation,
+                   when the rendering is requested,
+                   then it is a hit`,
+			capacity: 10,
+			actions: func(lru *cache.LRU) {
+				lru.Invalidate(canvasID)
+				lru.Set(app.RenderKey{CanvasID: canvasID, Version: lru.Version(canvasID), Variant: "text/plain"}, []byte("abc"))
+			},
+			key:               app.RenderKey{CanvasID: canvasID, Version: 1, Variant: "text/plain"},
+			expectedRendering: []byte("abc"),
+			expectedStats:     app.RenderCacheStats{Hits: 1, Entries: 1, Size: 3},
+		},
+		{
+			name: `Given a cache where a rendering larger than the capacity has been stored,
+                   when the rendering is requested,
+                   then it is a miss`,
+			capacity: 2,
+			actions: func(lru *cache.LRU) {
+				lru.Set(key, []byte("abc"))
+			},
+			key:           key,
+			expectedStats: app.RenderCacheStats{Misses: 1},
+		},
+		{
+			name: `Given a full cache where the least recently used rendering has been evicted,
+                   when the evicted rendering is requested,
+                   then it is a miss`,
+			capacity: 6,
+			actions: func(lru *cache.LRU) {
+				lru.Set(key, []byte("abc"))
+				lru.Set(otherKey, []byte("def"))
+				lru.Set(app.RenderKey{CanvasID: otherCanvasID, Variant: "image/svg+xml"}, []byte("ghi"))
+			},
+			key:           key,
+			expectedStats: app.RenderCacheStats{Misses: 1, Entries: 2, Size: 6},
+		},
+		{
+			name: `Given a full cache where a rendering has been used before storing a new one,
+                   when the used rendering is requested,
+                   then it is a hit`,
+			capacity: 6,
+			actions: func(lru *cache.LRU) {
+				lru.Set(key, []byte("abc"))
+				lru.Set(otherKey, []byte("def"))
+				lru.Get(key)
+				lru.Set(app.RenderKey{CanvasID: otherCanvasID, Variant: "image/svg+xml"}, []byte("ghi"))
+			},
+			key:               key,
+			expectedRendering: []byte("abc"),
+			expectedStats:     app.RenderCacheStats{Hits: 2, Entries: 2, Size: 6},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			lru := cache.NewLRU(tt.capacity)
+			tt.actions(lru)
+
+			rendering, ok := lru.Get(tt.key)
+			require.Equal(t, tt.expectedRendering != nil, ok)
+			require.Equal(t, tt.expectedRendering, rendering)
+			require.Equal(t, tt.expectedStats, lru.Stats())
+		})
+	}
+}
+
+func TestLRU_ManyInvalidations(t *testing.T) {
+	t.Parallel()
+
+	lru := cache.NewLRU(10)
+	cached := app.RenderKey{CanvasID: uuid.New(), Variant: "text/plain"}
+	lru.Set(cached, []byte("abc"))
+
+	// a rendering started before the last invalidation of its canvas
+	invalidatedID := uuid.New()
+	lru.Invalidate(invalidatedID)
+	outdated := app.RenderKey{CanvasID: invalidatedID, Version: lru.Version(invalidatedID), Variant: "text/plain"}
+	lru.Invalidate(invalidatedID)
+	version := lru.Version(invalidatedID)
+
+	// the versions of the canvases without renderings are discarded along the way
+	for i := 0; i < 10000; i++ {
+		lru.Invalidate(uuid.New())
+	}
+
+	require.GreaterOrEqual(t, lru.Version(invalidatedID), version)
+	lru.Set(outdated, []byte("def"))
+	_, ok := lru.Get(outdated)
+	require.False(t, ok)
+
+	require.Equal(t, cached.Version, lru.Version(cached.CanvasID))
+	rendering, ok := lru.Get(cached)
+	require.True(t, ok)
+	require.Equal(t, []byte("abc"), rendering)
+}
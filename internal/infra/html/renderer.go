@@ -0,0 +1,59 @@
+package html
+
+import (
+	"bufio"
+	"fmt"
+	"html"
+	"io"
+	"strings"
+
+	"github.com/maitesin/sketch/internal/domain"
+	"github.com/maitesin/sketch/internal/infra/raster"
+)
+
+// Renderer draws the canvas as a preformatted HTML block. Each run of cells with the same colours is wrapped in a
+// span styled with them, while the cells with the default colours are left as plain text
+type Renderer struct{}
+
+func (Renderer) Render(writer io.Writer, c domain.Canvas, viewport raster.Viewport) error {
+	grid, styles, err := raster.RasterizeWithStyles(c, viewport)
+	if err != nil {
+		return err
+	}
+
+	buffered := bufio.NewWriter(writer)
+	buffered.WriteString(`<pre class="canvas">` + "\n")
+	for y := range grid {
+		for left := 0; left < len(grid[y]); {
+			style := styles[y][left]
+			right := left
+			for right < len(grid[y]) && styles[y][right] == style {
+				right++
+			}
+
+			text := html.EscapeString(string(grid[y][left:right]))
+			if style.IsDefault() {
+				buffered.WriteString(text)
+			} else {
+				fmt.Fprintf(buffered, `<span style="%s">%s</span>`, css(style), text)
+			}
+			left = right
+		}
+		buffered.WriteString("\n")
+	}
+	buffered.WriteString("</pre>\n")
+
+	return buffered.Flush()
+}
+
+// css returns the declarations that set the colours of the style
+func css(style domain.Style) string {
+	var declarations []string
+	if style.Foreground() != domain.DefaultColour {
+		declarations = append(declarations, "color: "+string(style.Foreground()))
+	}
+	if style.Background() != domain.DefaultColour {
+		declarations = append(declarations, "background-color: "+string(style.Background()))
+	}
+	return strings.Join(declarations, "; ")
+}
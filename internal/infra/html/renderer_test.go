@@ -0,0 +1,129 @@
+package html_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/maitesin/sketch/internal/domain"
+	"github.com/maitesin/sketch/internal/infra/html"
+	"github.com/maitesin/sketch/internal/infra/raster"
+	"github.com/stretchr/testify/require"
+)
+
+func canvasFixture1() domain.Canvas {
+	return domain.NewCanvas(
+		uuid.New(),
+		3,
+		5,
+		[]domain.Task{
+			domain.NewDrawRectangle(uuid.New(), domain.NewPoint(0, 0), 3, 3, '.', '#', time.Now().UTC()).
+				WithStyles(
+					domain.NewStyle(domain.ColourYellow, domain.ColourBlue),
+					domain.NewStyle(domain.ColourRed, domain.DefaultColour),
+				),
+			domain.NewFill(uuid.New(), domain.NewPoint(4, 0), '~', time.Now().UTC()).
+				WithStyle(domain.NewStyle(domain.DefaultColour, domain.ColourCyan)),
+			domain.NewDrawLine(uuid.New(), domain.NewPoint(3, 2), domain.NewPoint(4, 2), '-', time.Now().UTC()),
+		},
+		time.Now().UTC(),
+	)
+}
+
+func outputFixture1() string {
+	return `<pre class="canvas">
+<span style="color: red">###</span><span style="background-color: cyan">~~</span>
+<span style="color: red">#</span><span style="color: yellow; background-color: blue">.</span><span style="color: red">#</span><span style="background-color: cyan">~~</span>
+<span style="color: red">###</span>--
+</pre>
+`
+}
+
+func canvasWithEscapedText() domain.Canvas {
+	return domain.NewCanvas(
+		uuid.New(),
+		1,
+		8,
+		[]domain.Task{
+			domain.NewDrawText(uuid.New(), domain.NewPoint(0, 0), "<a & b>", 8, domain.AlignLeft, time.Now().UTC()),
+		},
+		time.Now().UTC(),
+	)
+}
+
+func canvasWithoutColours() domain.Canvas {
+	return domain.NewCanvas(
+		uuid.New(),
+		2,
+		3,
+		[]domain.Task{
+			domain.NewDrawRectangle(uuid.New(), domain.NewPoint(0, 0), 2, 2, '#', '#', time.Now().UTC()),
+		},
+		time.Now().UTC(),
+	)
+}
+
+func invalidCanvas() domain.Canvas {
+	return domain.NewCanvas(
+		uuid.New(),
+		10,
+		10,
+		[]domain.Task{"this will fail"},
+		time.Now().UTC(),
+	)
+}
+
+func TestRenderer(t *testing.T) {
+	tests := []struct {
+		name           string
+		canvas         domain.Canvas
+		expectedOutput string
+		expectedErr    error
+	}{
+		{
+			name: `Given a canvas with a styled rectangle, a styled fill, and a line without colours,
+                   when the render method is called from the HTML renderer,
+                   then each run of cells with the same style is wrapped in a span with its colours`,
+			canvas:         canvasFixture1(),
+			expectedOutput: outputFixture1(),
+		},
+		{
+			name: `Given a canvas without colours,
+                   when the render method is called from the HTML renderer,
+                   then the output does not contain spans`,
+			canvas:         canvasWithoutColours(),
+			expectedOutput: "<pre class=\"canvas\">\n## \n## \n</pre>\n",
+		},
+		{
+			name: `Given a canvas with a text that contains HTML special characters,
+                   when the render method is called from the HTML renderer,
+                   then the characters are escaped`,
+			canvas:         canvasWithEscapedText(),
+			expectedOutput: "<pre class=\"canvas\">\n&lt;a &amp; b&gt; \n</pre>\n",
+		},
+		{
+			name: `Given a canvas with an invalid task,
+                   when the render method is called from the HTML renderer,
+                   then an invalid task error is returned`,
+			canvas:      invalidCanvas(),
+			expectedErr: raster.ErrInvalidTask,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var output bytes.Buffer
+			err := html.Renderer{}.Render(&output, tt.canvas, raster.FullViewport(tt.canvas))
+			if tt.expectedErr != nil {
+				require.ErrorAs(t, err, &tt.expectedErr)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tt.expectedOutput, output.String())
+			}
+		})
+	}
+}
@@ -4,4 +4,7 @@ package http
 type Config struct {
 	Host string
 	Port string
+
+	// SnapshotInterval is the number of tasks between the snapshots of a canvas, or zero to disable them
+	SnapshotInterval int
 }
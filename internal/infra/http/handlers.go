@@ -1,15 +1,19 @@
 package http
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
+	"strconv"
 
 	"github.com/go-chi/chi"
 	"github.com/google/uuid"
 	"github.com/maitesin/sketch/internal/app"
 	"github.com/maitesin/sketch/internal/domain"
+	"github.com/maitesin/sketch/internal/infra/raster"
 	log "github.com/sirupsen/logrus" //nolint: depguard
 )
 
@@ -23,12 +27,28 @@ func CreateCanvasHandler(handler app.CommandHandler) http.HandlerFunc {
 			return
 		}
 
+		if err := createCanvasRequest.Validate(); err != nil {
+			logger.Error(err)
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+
 		cmd := app.CreateCanvasCmd{
 			ID: createCanvasRequest.ID,
 		}
+		if createCanvasRequest.Height != nil {
+			cmd.Height = *createCanvasRequest.Height
+		}
+		if createCanvasRequest.Width != nil {
+			cmd.Width = *createCanvasRequest.Width
+		}
 
 		if err := handler.Handle(r.Context(), cmd); err != nil {
 			logger.WithField("canvas_id", createCanvasRequest.ID).Error(err)
+			if errors.As(err, &app.InvalidCanvasSizeError{}) {
+				http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+				return
+			}
 			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
 			return
 		}
@@ -38,7 +58,7 @@ func CreateCanvasHandler(handler app.CommandHandler) http.HandlerFunc {
 	}
 }
 
-func AddTaskHandler(drawRectangle, addFill app.CommandHandler) http.HandlerFunc {
+func AddTaskHandler(handlers map[RequestType]app.CommandHandler) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		logger := LoggerFromContext(r.Context())
 		var taskRequest TaskRequest
@@ -61,23 +81,28 @@ func AddTaskHandler(drawRectangle, addFill app.CommandHandler) http.HandlerFunc
 			return
 		}
 
-		cmd := createCmdFromTaskRequest(taskRequest, canvasID)
-
-		handler := drawRectangle
-		if taskRequest.Type == AddFillRequestType {
-			handler = addFill
+		handler, ok := handlers[taskRequest.Type]
+		if !ok {
+			logger.WithField("canvas_id", canvasID).Errorf("no handler found for task %q", taskRequest.Type)
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
 		}
 
+		cmd := createCmdFromTaskRequest(taskRequest, canvasID)
+
 		if err := handler.Handle(r.Context(), cmd); err != nil {
 			logger.WithField("canvas_id", canvasID).Error(err)
 			switch {
 			case errors.As(err, &app.CanvasNotFound{}):
 				http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
-			case errors.Is(err, domain.ErrOutOfBounds):
+			case errors.Is(err, domain.ErrOutOfBounds), errors.Is(err, domain.ErrInvalidSize):
 				http.Error(w, http.StatusText(http.StatusUnprocessableEntity), http.StatusUnprocessableEntity)
+			case errors.As(err, &app.CanvasConflict{}), errors.Is(err, domain.ErrTaskAlreadyExists):
+				http.Error(w, err.Error(), http.StatusConflict)
 			default:
 				http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
 			}
+			return
 		}
 		w.WriteHeader(http.StatusOK)
 	}
@@ -89,6 +114,16 @@ func createCmdFromTaskRequest(request TaskRequest, canvasID uuid.UUID) app.Comma
 		return createDrawRectangleCmdFromTaskRequest(request, canvasID)
 	case AddFillRequestType:
 		return createAddFillCmdFromTaskRequest(request, canvasID)
+	case DrawLineRequestType:
+		return createDrawLineCmdFromTaskRequest(request, canvasID)
+	case DrawEllipseRequestType:
+		return createDrawEllipseCmdFromTaskRequest(request, canvasID)
+	case DrawTextRequestType:
+		return createDrawTextCmdFromTaskRequest(request, canvasID)
+	case DrawPolylineRequestType:
+		return createDrawPolylineCmdFromTaskRequest(request, canvasID)
+	case DrawPolygonRequestType:
+		return createDrawPolygonCmdFromTaskRequest(request, canvasID)
 	}
 
 	return nil
@@ -112,14 +147,54 @@ func createDrawRectangleCmdFromTaskRequest(request TaskRequest, canvasID uuid.UU
 			request.Rectangle.Point.X,
 			request.Rectangle.Point.Y,
 		),
-		Height:  request.Rectangle.Height,
-		Width:   request.Rectangle.Width,
-		Filler:  filler,
-		Outline: outline,
+		Height:       request.Rectangle.Height,
+		Width:        request.Rectangle.Width,
+		Filler:       filler,
+		Outline:      outline,
+		Pattern:      patternFromRequest(request.Rectangle.Pattern),
+		FillerStyle:  styleFromRequest(request.Rectangle.FillerStyle),
+		OutlineStyle: styleFromRequest(request.Rectangle.OutlineStyle),
+	}
+}
+
+func patternFromRequest(request *PatternRequest) domain.Pattern {
+	if request == nil {
+		return domain.Pattern{}
+	}
+
+	anchor := domain.PatternAnchorCanvas
+	if request.Anchor != "" {
+		anchor = domain.PatternAnchor(request.Anchor)
+	}
+	return domain.NewPattern(request.Tile, anchor)
+}
+
+func styleFromRequest(request *StyleRequest) domain.Style {
+	if request == nil {
+		return domain.Style{}
 	}
+	return domain.NewStyle(domain.Colour(request.Foreground), domain.Colour(request.Background))
 }
 
 func createAddFillCmdFromTaskRequest(request TaskRequest, canvasID uuid.UUID) app.Command {
+	connectivity := domain.FourConnected
+	if request.Fill.Connectivity != 0 {
+		connectivity = domain.Connectivity(request.Fill.Connectivity)
+	}
+	mode := domain.FloodFillMode
+	var boundary rune
+	if request.Fill.Mode != "" {
+		mode = domain.FillMode(request.Fill.Mode)
+	}
+	if mode == domain.BoundaryFillMode {
+		boundary = []rune(request.Fill.Boundary)[0]
+	}
+
+	filler := ' '
+	if request.Fill.Filler != "" {
+		filler = []rune(request.Fill.Filler)[0]
+	}
+
 	return app.AddFillCmd{
 		CanvasID: canvasID,
 		FillID:   request.Fill.ID,
@@ -127,11 +202,303 @@ func createAddFillCmdFromTaskRequest(request TaskRequest, canvasID uuid.UUID) ap
 			request.Fill.Point.X,
 			request.Fill.Point.Y,
 		),
-		Filler: []rune(request.Fill.Filler)[0],
+		Filler:       filler,
+		Connectivity: connectivity,
+		Mode:         mode,
+		Boundary:     boundary,
+		Pattern:      patternFromRequest(request.Fill.Pattern),
+		Style:        styleFromRequest(request.Fill.Style),
+	}
+}
+
+func createDrawLineCmdFromTaskRequest(request TaskRequest, canvasID uuid.UUID) app.Command {
+	return app.DrawLineCmd{
+		CanvasID: canvasID,
+		LineID:   request.Line.ID,
+		From: domain.NewPoint(
+			request.Line.From.X,
+			request.Line.From.Y,
+		),
+		To: domain.NewPoint(
+			request.Line.To.X,
+			request.Line.To.Y,
+		),
+		Outline: []rune(request.Line.Outline)[0],
+	}
+}
+
+func createDrawEllipseCmdFromTaskRequest(request TaskRequest, canvasID uuid.UUID) app.Command {
+	filler := ' '
+	if request.Ellipse.Filler != nil {
+		filler = []rune(*request.Ellipse.Filler)[0]
+	}
+
+	outline := filler
+	if request.Ellipse.Outline != nil {
+		outline = []rune(*request.Ellipse.Outline)[0]
+	}
+
+	return app.DrawEllipseCmd{
+		CanvasID:  canvasID,
+		EllipseID: request.Ellipse.ID,
+		Point: domain.NewPoint(
+			request.Ellipse.Point.X,
+			request.Ellipse.Point.Y,
+		),
+		Height:  request.Ellipse.Height,
+		Width:   request.Ellipse.Width,
+		Filler:  filler,
+		Outline: outline,
+	}
+}
+
+func createDrawTextCmdFromTaskRequest(request TaskRequest, canvasID uuid.UUID) app.Command {
+	alignment := domain.AlignLeft
+	if request.Text.Alignment != "" {
+		alignment = domain.Alignment(request.Text.Alignment)
+	}
+
+	return app.DrawTextCmd{
+		CanvasID: canvasID,
+		TextID:   request.Text.ID,
+		Point: domain.NewPoint(
+			request.Text.Point.X,
+			request.Text.Point.Y,
+		),
+		Text:      request.Text.Text,
+		WrapWidth: request.Text.WrapWidth,
+		Alignment: alignment,
+	}
+}
+
+func createDrawPolylineCmdFromTaskRequest(request TaskRequest, canvasID uuid.UUID) app.Command {
+	return app.DrawPolylineCmd{
+		CanvasID:   canvasID,
+		PolylineID: request.Polyline.ID,
+		Points:     pointsFromRequest(request.Polyline.Points),
+		Outline:    []rune(request.Polyline.Outline)[0],
+	}
+}
+
+func createDrawPolygonCmdFromTaskRequest(request TaskRequest, canvasID uuid.UUID) app.Command {
+	filler := ' '
+	if request.Polygon.Filler != nil {
+		filler = []rune(*request.Polygon.Filler)[0]
+	}
+
+	outline := filler
+	if request.Polygon.Outline != nil {
+		outline = []rune(*request.Polygon.Outline)[0]
+	}
+
+	return app.DrawPolygonCmd{
+		CanvasID:  canvasID,
+		PolygonID: request.Polygon.ID,
+		Points:    pointsFromRequest(request.Polygon.Points),
+		Filler:    filler,
+		Outline:   outline,
+	}
+}
+
+func pointsFromRequest(requestPoints []Point) []domain.Point {
+	points := make([]domain.Point, len(requestPoints))
+	for i := range requestPoints {
+		points[i] = domain.NewPoint(requestPoints[i].X, requestPoints[i].Y)
+	}
+	return points
+}
+
+func ResizeCanvasHandler(handler app.CommandHandler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		logger := LoggerFromContext(r.Context())
+		var resizeCanvasRequest ResizeCanvasRequest
+		if err := json.NewDecoder(r.Body).Decode(&resizeCanvasRequest); err != nil {
+			logger.Error(err)
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+
+		if err := resizeCanvasRequest.Validate(); err != nil {
+			logger.Error(err)
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+
+		canvasID, err := uuid.Parse(chi.URLParam(r, "canvasID"))
+		if err != nil {
+			logger.Error(err)
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+
+		cmd := app.ResizeCanvasCmd{
+			CanvasID: canvasID,
+			Anchor:   domain.AnchorTopLeft,
+			Clip:     resizeCanvasRequest.Clip,
+		}
+		if resizeCanvasRequest.Height != nil {
+			cmd.Height = *resizeCanvasRequest.Height
+		}
+		if resizeCanvasRequest.Width != nil {
+			cmd.Width = *resizeCanvasRequest.Width
+		}
+		if resizeCanvasRequest.Anchor != "" {
+			cmd.Anchor = domain.Anchor(resizeCanvasRequest.Anchor)
+		}
+
+		if err := handler.Handle(r.Context(), cmd); err != nil {
+			logger.WithField("canvas_id", canvasID).Error(err)
+			switch {
+			case errors.As(err, &app.CanvasNotFound{}):
+				http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+			case errors.As(err, &app.InvalidCanvasSizeError{}):
+				http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+			case errors.Is(err, domain.ErrOutOfBounds):
+				http.Error(w, "some tasks do not fit in the new size of the canvas. Use clip to crop them", http.StatusUnprocessableEntity)
+			default:
+				http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			}
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}
+}
+
+// UpdateTaskHandler replaces a task using a body with the same shape as the one used to add it. The ID of the task
+// is taken from the URL, so the one in the body is ignored
+func UpdateTaskHandler(handler app.CommandHandler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		logger := LoggerFromContext(r.Context())
+		var taskRequest TaskRequest
+		if err := json.NewDecoder(r.Body).Decode(&taskRequest); err != nil {
+			logger.Error(err)
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+
+		if err := taskRequest.Validate(); err != nil {
+			logger.Error(err)
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+
+		canvasID, err := uuid.Parse(chi.URLParam(r, "canvasID"))
+		if err != nil {
+			logger.Error(err)
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+
+		taskID, err := uuid.Parse(chi.URLParam(r, "taskID"))
+		if err != nil {
+			logger.Error(err)
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+
+		cmd := app.UpdateTaskCmd{
+			CanvasID: canvasID,
+			TaskID:   taskID,
+			Task:     createCmdFromTaskRequest(taskRequest, canvasID),
+		}
+
+		if err := handler.Handle(r.Context(), cmd); err != nil {
+			logger.WithFields(log.Fields{"canvas_id": canvasID, "task_id": taskID}).Error(err)
+			switch {
+			case errors.As(err, &app.CanvasNotFound{}), errors.Is(err, domain.ErrTaskNotFound):
+				http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+			case errors.Is(err, domain.ErrOutOfBounds), errors.Is(err, domain.ErrTaskTypeMismatch), errors.Is(err, domain.ErrInvalidSize):
+				http.Error(w, http.StatusText(http.StatusUnprocessableEntity), http.StatusUnprocessableEntity)
+			case errors.As(err, &app.CanvasConflict{}), errors.Is(err, domain.ErrTaskAlreadyExists):
+				http.Error(w, err.Error(), http.StatusConflict)
+			case errors.Is(err, app.ErrTaskRequired):
+				http.Error(w, err.Error(), http.StatusBadRequest)
+			default:
+				http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			}
+			return
+		}
+		w.WriteHeader(http.StatusOK)
 	}
 }
 
-func RenderCanvasHandler(handler app.QueryHandler, renderer Renderer) http.HandlerFunc {
+func RemoveTaskHandler(handler app.CommandHandler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		logger := LoggerFromContext(r.Context())
+
+		canvasID, err := uuid.Parse(chi.URLParam(r, "canvasID"))
+		if err != nil {
+			logger.Error(err)
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+
+		taskID, err := uuid.Parse(chi.URLParam(r, "taskID"))
+		if err != nil {
+			logger.Error(err)
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+
+		cmd := app.RemoveTaskCmd{
+			CanvasID: canvasID,
+			TaskID:   taskID,
+		}
+
+		if err := handler.Handle(r.Context(), cmd); err != nil {
+			logger.WithFields(log.Fields{"canvas_id": canvasID, "task_id": taskID}).Error(err)
+			switch {
+			case errors.As(err, &app.CanvasNotFound{}), errors.Is(err, domain.ErrTaskNotFound):
+				http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+			default:
+				http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			}
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}
+}
+
+func UndoHandler(handler app.CommandHandler) http.HandlerFunc {
+	return historyHandler(handler, func(canvasID uuid.UUID) app.Command {
+		return app.UndoCmd{CanvasID: canvasID}
+	})
+}
+
+func RedoHandler(handler app.CommandHandler) http.HandlerFunc {
+	return historyHandler(handler, func(canvasID uuid.UUID) app.Command {
+		return app.RedoCmd{CanvasID: canvasID}
+	})
+}
+
+func historyHandler(handler app.CommandHandler, newCmd func(uuid.UUID) app.Command) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		logger := LoggerFromContext(r.Context())
+
+		canvasID, err := uuid.Parse(chi.URLParam(r, "canvasID"))
+		if err != nil {
+			logger.Error(err)
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+
+		if err := handler.Handle(r.Context(), newCmd(canvasID)); err != nil {
+			logger.WithField("canvas_id", canvasID).Error(err)
+			switch {
+			case errors.As(err, &app.CanvasNotFound{}):
+				http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+			case errors.Is(err, domain.ErrNothingToUndo), errors.Is(err, domain.ErrNothingToRedo):
+				http.Error(w, err.Error(), http.StatusConflict)
+			default:
+				http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			}
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}
+}
+
+func RenderCanvasHandler(handler app.QueryHandler, renderers Renderers, cache app.RenderCache, snapshotter app.Snapshotter) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		logger := LoggerFromContext(r.Context())
 
@@ -146,6 +513,47 @@ func RenderCanvasHandler(handler app.QueryHandler, renderer Renderer) http.Handl
 			"canvas_id": canvasID,
 		}
 
+		w.Header().Add("Vary", "Accept")
+		mediaType, ok := renderers.negotiate(r.Header.Get("Accept"))
+		if format := r.URL.Query().Get("format"); format != "" {
+			mediaType, ok = formats[format]
+			if !ok {
+				logger.WithFields(loggerFields).Errorf("unknown format %q", format)
+				http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+				return
+			}
+			_, ok = renderers[mediaType]
+		}
+		if !ok {
+			logger.WithFields(loggerFields).Errorf("no renderer for the requested media types %q", r.Header.Get("Accept"))
+			http.Error(w, http.StatusText(http.StatusNotAcceptable), http.StatusNotAcceptable)
+			return
+		}
+
+		var owners bool
+		if value := r.URL.Query().Get("owners"); value != "" {
+			owners, err = strconv.ParseBool(value)
+			if err != nil {
+				logger.WithFields(loggerFields).Error(err)
+				http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+				return
+			}
+		}
+
+		key := app.RenderKey{
+			CanvasID: canvasID,
+			Version:  cache.Version(canvasID),
+			Variant:  renderVariant(r, mediaType, owners),
+		}
+		if rendering, ok := cache.Get(key); ok {
+			w.Header().Set("Content-Type", mediaType.contentType())
+			w.Header().Set("X-Cache", "HIT")
+			if _, err := w.Write(rendering); err != nil {
+				logger.WithFields(loggerFields).Error(err)
+			}
+			return
+		}
+
 		query := app.RetrieveCanvasQuery{
 			ID: canvasID,
 		}
@@ -169,11 +577,211 @@ func RenderCanvasHandler(handler app.QueryHandler, renderer Renderer) http.Handl
 			return
 		}
 
-		err = renderer.Render(w, canvas)
+		viewport, err := viewportFromRequest(r, canvas)
+		if err != nil {
+			logger.WithFields(loggerFields).Error(err)
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+
+		// the snapshot only saves work, so the canvas is rendered from all its tasks when it cannot be found
+		canvas, err = snapshotter.Resume(r.Context(), canvas)
+		if err != nil {
+			logger.WithFields(loggerFields).Error(err)
+		}
+
+		var rendering bytes.Buffer
+		renderer := renderers[mediaType]
+		if ownersRenderer, ok := renderer.(OwnersRenderer); ok && owners {
+			err = ownersRenderer.RenderWithOwners(&rendering, canvas, viewport)
+		} else {
+			err = renderer.Render(&rendering, canvas, viewport)
+		}
 		if err != nil {
 			logger.Error(err)
 			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
 			return
 		}
+		cache.Set(key, rendering.Bytes())
+
+		w.Header().Set("Content-Type", mediaType.contentType())
+		w.Header().Set("X-Cache", "MISS")
+		if _, err := w.Write(rendering.Bytes()); err != nil {
+			logger.WithFields(loggerFields).Error(err)
+		}
 	}
 }
+
+// renderVariant identifies the rendering requested among the renderings of the same version of a canvas. The viewport
+// parameters are used as received, because their defaults depend on the canvas and are the same for a given version
+func renderVariant(r *http.Request, mediaType MediaType, owners bool) string {
+	values := r.URL.Query()
+	return fmt.Sprintf("%s;owners=%t;x=%s;y=%s;width=%s;height=%s",
+		mediaType.contentType(), owners,
+		values.Get("x"), values.Get("y"), values.Get("width"), values.Get("height"),
+	)
+}
+
+func RenderCacheStatsHandler(cache app.RenderCache) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		logger := LoggerFromContext(r.Context())
+
+		stats := cache.Stats()
+		response := RenderCacheStatsResponse{
+			Hits:    stats.Hits,
+			Misses:  stats.Misses,
+			Entries: stats.Entries,
+			Size:    stats.Size,
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			logger.Error(err)
+		}
+	}
+}
+
+// viewportFromRequest returns the window of the canvas in the x, y, width, and height query parameters. By default, the
+// window starts in the top left corner of the canvas and it extends to the bottom right corner
+func viewportFromRequest(r *http.Request, canvas domain.Canvas) (raster.Viewport, error) {
+	values := r.URL.Query()
+
+	x, err := intFromQuery(values, "x", 0)
+	if err != nil {
+		return raster.Viewport{}, err
+	}
+
+	y, err := intFromQuery(values, "y", 0)
+	if err != nil {
+		return raster.Viewport{}, err
+	}
+
+	width, err := intFromQuery(values, "width", canvas.Width()-x)
+	if err != nil {
+		return raster.Viewport{}, err
+	}
+
+	height, err := intFromQuery(values, "height", canvas.Height()-y)
+	if err != nil {
+		return raster.Viewport{}, err
+	}
+
+	viewport := raster.Viewport{X: x, Y: y, Width: width, Height: height}
+	return viewport, viewport.Validate(canvas)
+}
+
+// intFromQuery returns the integer in the query parameter, or the default value when the parameter is missing
+func intFromQuery(values url.Values, name string, defaultValue int) (int, error) {
+	value := values.Get(name)
+	if value == "" {
+		return defaultValue, nil
+	}
+
+	number, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s %q", name, value)
+	}
+	return number, nil
+}
+
+const (
+	defaultListTasksLimit = 100
+	maxListTasksLimit     = 1000
+)
+
+func ListTasksHandler(handler app.QueryHandler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		logger := LoggerFromContext(r.Context())
+
+		canvasID, err := uuid.Parse(chi.URLParam(r, "canvasID"))
+		if err != nil {
+			logger.Error(err)
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+
+		loggerFields := log.Fields{
+			"canvas_id": canvasID,
+		}
+
+		query, err := listTasksQueryFromRequest(r, canvasID)
+		if err != nil {
+			logger.WithFields(loggerFields).Error(err)
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+
+		queryResponse, err := handler.Handle(r.Context(), query)
+		if err != nil {
+			logger.WithFields(loggerFields).Error(err)
+			switch {
+			case errors.As(err, &app.CanvasNotFound{}):
+				http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+			default:
+				http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			}
+			return
+		}
+
+		listResponse, ok := queryResponse.(app.ListTasksResponse)
+		if !ok {
+			logger.WithFields(loggerFields).Errorf("unexpected query response: %#v", queryResponse)
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+
+		response := ListTasksResponse{
+			Tasks:  make([]TaskResponse, len(listResponse.Tasks)),
+			Total:  listResponse.Total,
+			Offset: query.Offset,
+			Limit:  query.Limit,
+		}
+		for i, task := range listResponse.Tasks {
+			response.Tasks[i], err = taskResponseFromDomain(task)
+			if err != nil {
+				logger.WithFields(loggerFields).Error(err)
+				http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+				return
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			logger.WithFields(loggerFields).Error(err)
+		}
+	}
+}
+
+func listTasksQueryFromRequest(r *http.Request, canvasID uuid.UUID) (app.ListTasksQuery, error) {
+	values := r.URL.Query()
+	query := app.ListTasksQuery{
+		CanvasID: canvasID,
+		Limit:    defaultListTasksLimit,
+	}
+
+	if value := values.Get("type"); value != "" {
+		taskType, ok := taskTypes[RequestType(value)]
+		if !ok {
+			return app.ListTasksQuery{}, fmt.Errorf("invalid task type %q", value)
+		}
+		query.Type = taskType
+	}
+
+	if value := values.Get("offset"); value != "" {
+		offset, err := strconv.Atoi(value)
+		if err != nil || offset < 0 {
+			return app.ListTasksQuery{}, fmt.Errorf("invalid offset %q", value)
+		}
+		query.Offset = offset
+	}
+
+	if value := values.Get("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 1 || limit > maxListTasksLimit {
+			return app.ListTasksQuery{}, fmt.Errorf("invalid limit %q", value)
+		}
+		query.Limit = limit
+	}
+
+	return query, nil
+}
@@ -9,18 +9,24 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/go-chi/chi"
 	"github.com/google/uuid"
 	"github.com/maitesin/sketch/internal/app"
 	"github.com/maitesin/sketch/internal/domain"
+	"github.com/maitesin/sketch/internal/infra/cache"
 	httpx "github.com/maitesin/sketch/internal/infra/http"
+	"github.com/maitesin/sketch/internal/infra/raster"
 	log "github.com/sirupsen/logrus" //nolint: depguard
 	"github.com/stretchr/testify/require"
 )
 
+const renderCacheCapacity = 1 << 20
+
 func validCommandHandler() app.CommandHandler {
 	return &CommandHandlerMock{
 		HandleFunc: func(context.Context, app.Command) error {
@@ -73,6 +79,29 @@ func TestCreateCanvasHandler(t *testing.T) {
 			bodyReader:            func() io.Reader { return strings.NewReader("") }(),
 			expectedStatusCode:    http.StatusBadRequest,
 		},
+		{
+			name: `Given a working command handler, a valid canvas ID, and a body request with a non-positive height,
+                   when the create canvas handler is called,
+                   then a status bad request (400) response is returned`,
+			commandHandlerMutator: noopCommandHandlerMutator,
+			bodyReader:            strings.NewReader(fmt.Sprintf(`{"id":%q,"height":0}`, uuid.New().String())),
+			expectedStatusCode:    http.StatusBadRequest,
+		},
+		{
+			name: `Given a command handler that rejects the canvas size, a valid canvas ID, and a valid body request,
+                   when the create canvas handler is called,
+                   then a status unprocessable entity (422) response is returned`,
+			commandHandlerMutator: func(app.CommandHandler) app.CommandHandler {
+				handler := &CommandHandlerMock{
+					HandleFunc: func(context.Context, app.Command) error {
+						return app.InvalidCanvasSizeError{}
+					},
+				}
+				return handler
+			},
+			bodyReader:         strings.NewReader(fmt.Sprintf(`{"id":%q,"height":5000,"width":10}`, uuid.New().String())),
+			expectedStatusCode: http.StatusUnprocessableEntity,
+		},
 		{
 			name: `Given a non-working command handler, a valid canvas ID, and a valid body request,
                    when the add fill handler is called,
@@ -123,6 +152,78 @@ func validAddFillerBodyReader(t *testing.T) io.Reader {
 	return bytes.NewReader(b)
 }
 
+func validBoundaryFillBodyReader(t *testing.T) io.Reader {
+	t.Helper()
+
+	b, err := json.Marshal(addFillRequestWithMode(8, "boundary", "#"))
+	require.NoError(t, err)
+
+	return bytes.NewReader(b)
+}
+
+func expectAddFillCmd(connectivity domain.Connectivity, mode domain.FillMode, boundary rune) commandHandlerMutator {
+	return func(app.CommandHandler) app.CommandHandler {
+		return &CommandHandlerMock{
+			HandleFunc: func(_ context.Context, cmd app.Command) error {
+				fillCmd, ok := cmd.(app.AddFillCmd)
+				if !ok || fillCmd.Connectivity != connectivity || fillCmd.Mode != mode || fillCmd.Boundary != boundary {
+					return fmt.Errorf("unexpected command %#v", cmd)
+				}
+				return nil
+			},
+		}
+	}
+}
+
+func validPatternFillBodyReader(t *testing.T) io.Reader {
+	t.Helper()
+
+	b, err := json.Marshal(addFillRequestWithPattern([]string{"xo", "ox"}, ""))
+	require.NoError(t, err)
+
+	return bytes.NewReader(b)
+}
+
+func expectAddFillCmdWithPattern(pattern domain.Pattern) commandHandlerMutator {
+	return func(app.CommandHandler) app.CommandHandler {
+		return &CommandHandlerMock{
+			HandleFunc: func(_ context.Context, cmd app.Command) error {
+				fillCmd, ok := cmd.(app.AddFillCmd)
+				if !ok || !reflect.DeepEqual(fillCmd.Pattern, pattern) {
+					return fmt.Errorf("unexpected command %#v", cmd)
+				}
+				return nil
+			},
+		}
+	}
+}
+
+func validStyledRectangleBodyReader(t *testing.T) io.Reader {
+	t.Helper()
+
+	b, err := json.Marshal(drawRectangleRequestWithStyles(
+		httpx.StyleRequest{Foreground: "yellow", Background: "blue"},
+		httpx.StyleRequest{Foreground: "red"},
+	))
+	require.NoError(t, err)
+
+	return bytes.NewReader(b)
+}
+
+func expectDrawRectangleCmdWithStyles(filler, outline domain.Style) commandHandlerMutator {
+	return func(app.CommandHandler) app.CommandHandler {
+		return &CommandHandlerMock{
+			HandleFunc: func(_ context.Context, cmd app.Command) error {
+				rectangleCmd, ok := cmd.(app.DrawRectangleCmd)
+				if !ok || rectangleCmd.FillerStyle != filler || rectangleCmd.OutlineStyle != outline {
+					return fmt.Errorf("unexpected command %#v", cmd)
+				}
+				return nil
+			},
+		}
+	}
+}
+
 func validDrawRectangleBodyReader(t *testing.T) io.Reader {
 	t.Helper()
 
@@ -132,6 +233,51 @@ func validDrawRectangleBodyReader(t *testing.T) io.Reader {
 	return bytes.NewReader(b)
 }
 
+func validDrawLineBodyReader(t *testing.T) io.Reader {
+	t.Helper()
+
+	b, err := json.Marshal(validDrawLineRequest())
+	require.NoError(t, err)
+
+	return bytes.NewReader(b)
+}
+
+func validDrawEllipseBodyReader(t *testing.T) io.Reader {
+	t.Helper()
+
+	b, err := json.Marshal(validDrawEllipseRequest())
+	require.NoError(t, err)
+
+	return bytes.NewReader(b)
+}
+
+func validDrawTextBodyReader(t *testing.T) io.Reader {
+	t.Helper()
+
+	b, err := json.Marshal(validDrawTextRequest())
+	require.NoError(t, err)
+
+	return bytes.NewReader(b)
+}
+
+func validDrawPolylineBodyReader(t *testing.T) io.Reader {
+	t.Helper()
+
+	b, err := json.Marshal(validDrawPolylineRequest())
+	require.NoError(t, err)
+
+	return bytes.NewReader(b)
+}
+
+func validDrawPolygonBodyReader(t *testing.T) io.Reader {
+	t.Helper()
+
+	b, err := json.Marshal(validDrawPolygonRequest())
+	require.NoError(t, err)
+
+	return bytes.NewReader(b)
+}
+
 func invalidDrawRectangleBodyReader(t *testing.T) io.Reader {
 	t.Helper()
 
@@ -159,6 +305,45 @@ func TestAddTaskHandler(t *testing.T) {
 			bodyReader:            validAddFillerBodyReader(t),
 			expectedStatusCode:    http.StatusOK,
 		},
+		{
+			name: `Given a working command handler, a valid canvas ID, and a valid add fill body request without mode,
+                   when the add task handler is called,
+                   then a 4-connected flood fill command is handled and a status ok (200) response is returned`,
+			commandHandlerMutator: expectAddFillCmd(domain.FourConnected, domain.FloodFillMode, 0),
+			canvasID:              uuid.New().String(),
+			bodyReader:            validAddFillerBodyReader(t),
+			expectedStatusCode:    http.StatusOK,
+		},
+		{
+			name: `Given a working command handler, a valid canvas ID, and a valid 8-connected boundary fill body request,
+                   when the add task handler is called,
+                   then an 8-connected boundary fill command is handled and a status ok (200) response is returned`,
+			commandHandlerMutator: expectAddFillCmd(domain.EightConnected, domain.BoundaryFillMode, '#'),
+			canvasID:              uuid.New().String(),
+			bodyReader:            validBoundaryFillBodyReader(t),
+			expectedStatusCode:    http.StatusOK,
+		},
+		{
+			name: `Given a working command handler, a valid canvas ID, and a valid add fill body request with a pattern,
+                   when the add task handler is called,
+                   then a fill command with the pattern anchored to the canvas is handled and a status ok (200) response is returned`,
+			commandHandlerMutator: expectAddFillCmdWithPattern(domain.NewPattern([]string{"xo", "ox"}, domain.PatternAnchorCanvas)),
+			canvasID:              uuid.New().String(),
+			bodyReader:            validPatternFillBodyReader(t),
+			expectedStatusCode:    http.StatusOK,
+		},
+		{
+			name: `Given a working command handler, a valid canvas ID, and a valid draw rectangle body request with styles,
+                   when the add task handler is called,
+                   then a rectangle command with the styles is handled and a status ok (200) response is returned`,
+			commandHandlerMutator: expectDrawRectangleCmdWithStyles(
+				domain.NewStyle(domain.ColourYellow, domain.ColourBlue),
+				domain.NewStyle(domain.ColourRed, domain.DefaultColour),
+			),
+			canvasID:           uuid.New().String(),
+			bodyReader:         validStyledRectangleBodyReader(t),
+			expectedStatusCode: http.StatusOK,
+		},
 		{
 			name: `Given a working command handler, a valid canvas ID, and another valid body request,
                    when the add task handler is called,
@@ -168,6 +353,51 @@ func TestAddTaskHandler(t *testing.T) {
 			bodyReader:            validDrawRectangleBodyReader(t),
 			expectedStatusCode:    http.StatusOK,
 		},
+		{
+			name: `Given a working command handler, a valid canvas ID, and a valid draw line body request,
+                   when the add task handler is called,
+                   then a status ok (200) response is returned`,
+			commandHandlerMutator: noopCommandHandlerMutator,
+			canvasID:              uuid.New().String(),
+			bodyReader:            validDrawLineBodyReader(t),
+			expectedStatusCode:    http.StatusOK,
+		},
+		{
+			name: `Given a working command handler, a valid canvas ID, and a valid draw ellipse body request,
+                   when the add task handler is called,
+                   then a status ok (200) response is returned`,
+			commandHandlerMutator: noopCommandHandlerMutator,
+			canvasID:              uuid.New().String(),
+			bodyReader:            validDrawEllipseBodyReader(t),
+			expectedStatusCode:    http.StatusOK,
+		},
+		{
+			name: `Given a working command handler, a valid canvas ID, and a valid draw text body request,
+                   when the add task handler is called,
+                   then a status ok (200) response is returned`,
+			commandHandlerMutator: noopCommandHandlerMutator,
+			canvasID:              uuid.New().String(),
+			bodyReader:            validDrawTextBodyReader(t),
+			expectedStatusCode:    http.StatusOK,
+		},
+		{
+			name: `Given a working command handler, a valid canvas ID, and a valid draw polyline body request,
+                   when the add task handler is called,
+                   then a status ok (200) response is returned`,
+			commandHandlerMutator: noopCommandHandlerMutator,
+			canvasID:              uuid.New().String(),
+			bodyReader:            validDrawPolylineBodyReader(t),
+			expectedStatusCode:    http.StatusOK,
+		},
+		{
+			name: `Given a working command handler, a valid canvas ID, and a valid draw polygon body request,
+                   when the add task handler is called,
+                   then a status ok (200) response is returned`,
+			commandHandlerMutator: noopCommandHandlerMutator,
+			canvasID:              uuid.New().String(),
+			bodyReader:            validDrawPolygonBodyReader(t),
+			expectedStatusCode:    http.StatusOK,
+		},
 		{
 			name: `Given a working command handler, an invalid canvas ID, and a valid body request,
                    when the add task handler is called,
@@ -227,6 +457,38 @@ func TestAddTaskHandler(t *testing.T) {
 			bodyReader:         validDrawRectangleBodyReader(t),
 			expectedStatusCode: http.StatusUnprocessableEntity,
 		},
+		{
+			name: `Given a command handler that conflicts with a task added concurrently, a valid canvas ID, and a valid body request,
+                   when the add task handler is called,
+                   then a status conflict (409) response is returned`,
+			commandHandlerMutator: func(app.CommandHandler) app.CommandHandler {
+				handler := &CommandHandlerMock{
+					HandleFunc: func(context.Context, app.Command) error {
+						return app.CanvasConflict{ID: uuid.New()}
+					},
+				}
+				return handler
+			},
+			canvasID:           uuid.New().String(),
+			bodyReader:         validDrawRectangleBodyReader(t),
+			expectedStatusCode: http.StatusConflict,
+		},
+		{
+			name: `Given a command handler that returns a task already exists error, a valid canvas ID, and a valid body request,
+                   when the add task handler is called,
+                   then a status conflict (409) response is returned`,
+			commandHandlerMutator: func(app.CommandHandler) app.CommandHandler {
+				handler := &CommandHandlerMock{
+					HandleFunc: func(context.Context, app.Command) error {
+						return domain.ErrTaskAlreadyExists
+					},
+				}
+				return handler
+			},
+			canvasID:           uuid.New().String(),
+			bodyReader:         validDrawRectangleBodyReader(t),
+			expectedStatusCode: http.StatusConflict,
+		},
 		{
 			name: `Given a non-working command handler, a valid canvas ID, and a valid body request,
                    when the add task handler is called,
@@ -262,7 +524,15 @@ func TestAddTaskHandler(t *testing.T) {
 
 			res := httptest.NewRecorder()
 
-			httpx.AddTaskHandler(commandHandler, noopCommandHandlerMutator(validCommandHandler()))(res, req)
+			httpx.AddTaskHandler(map[httpx.RequestType]app.CommandHandler{
+				httpx.DrawRectangleRequestType: commandHandler,
+				httpx.AddFillRequestType:       commandHandler,
+				httpx.DrawLineRequestType:      commandHandler,
+				httpx.DrawEllipseRequestType:   commandHandler,
+				httpx.DrawTextRequestType:      commandHandler,
+				httpx.DrawPolylineRequestType:  commandHandler,
+				httpx.DrawPolygonRequestType:   commandHandler,
+			})(res, req)
 			result := res.Result()
 			defer result.Body.Close()
 
@@ -271,109 +541,1321 @@ func TestAddTaskHandler(t *testing.T) {
 	}
 }
 
-func validQueryHandler() app.QueryHandler {
-	return &QueryHandlerMock{
-		HandleFunc: func(context.Context, app.Query) (app.QueryResponse, error) {
-			return domain.Canvas{}, nil
-		},
-	}
-}
-
-type queryHandlerMutator func(app.QueryHandler) app.QueryHandler
-
-var noopQueryHandlerMutator = func(handler app.QueryHandler) app.QueryHandler { return handler }
-
-//nolint:funlen
-func TestRenderCanvasHandler(t *testing.T) {
+func TestResizeCanvasHandler(t *testing.T) {
 	tests := []struct {
-		name                string
-		queryHandlerMutator queryHandlerMutator
-		rendererMutator     rendererMutator
-		canvasID            string
-		expectedStatusCode  int
+		name                  string
+		commandHandlerMutator commandHandlerMutator
+		canvasID              string
+		body                  string
+		expectedStatusCode    int
 	}{
 		{
-			name: `Given a working query handler, a working renderer, and a valid canvas ID,
-                   when the render canvas handler is called,
-                   then a status code ok (200) is returned`,
-			queryHandlerMutator: noopQueryHandlerMutator,
-			rendererMutator:     noopRendererMutator,
-			canvasID:            uuid.New().String(),
-			expectedStatusCode:  http.StatusOK,
+			name: `Given a working command handler, a valid canvas ID, and a valid body request,
+                   when the resize canvas handler is called,
+                   then a status ok (200) response is returned`,
+			commandHandlerMutator: noopCommandHandlerMutator,
+			canvasID:              uuid.New().String(),
+			body:                  `{"height":20,"width":40,"anchor":"bottom_right"}`,
+			expectedStatusCode:    http.StatusOK,
 		},
 		{
-			name: `Given a working query handler, a working renderer, and an invalid canvas ID,
-                   when the render canvas handler is called,
-                   then a status code bad request (400) is returned`,
-			queryHandlerMutator: noopQueryHandlerMutator,
-			rendererMutator:     noopRendererMutator,
-			canvasID:            "",
-			expectedStatusCode:  http.StatusBadRequest,
+			name: `Given a working command handler, an invalid canvas ID, and a valid body request,
+                   when the resize canvas handler is called,
+                   then a status bad request (400) response is returned`,
+			commandHandlerMutator: noopCommandHandlerMutator,
+			canvasID:              "wololo",
+			body:                  `{"height":20}`,
+			expectedStatusCode:    http.StatusBadRequest,
 		},
 		{
-			name: `Given a working query handler, a working renderer, and a valid canvas ID, but that does not exists,
-                   when the render canvas handler is called,
-                   then a status code not found (404) is returned`,
-			queryHandlerMutator: func(app.QueryHandler) app.QueryHandler {
-				handler := &QueryHandlerMock{
-					HandleFunc: func(context.Context, app.Query) (app.QueryResponse, error) {
-						return nil, app.CanvasNotFound{}
+			name: `Given a working command handler, a valid canvas ID, and a body request that is not JSON,
+                   when the resize canvas handler is called,
+                   then a status bad request (400) response is returned`,
+			commandHandlerMutator: noopCommandHandlerMutator,
+			canvasID:              uuid.New().String(),
+			body:                  "",
+			expectedStatusCode:    http.StatusBadRequest,
+		},
+		{
+			name: `Given a working command handler, a valid canvas ID, and a body request with an unknown anchor,
+                   when the resize canvas handler is called,
+                   then a status bad request (400) response is returned`,
+			commandHandlerMutator: noopCommandHandlerMutator,
+			canvasID:              uuid.New().String(),
+			body:                  `{"height":20,"anchor":"center"}`,
+			expectedStatusCode:    http.StatusBadRequest,
+		},
+		{
+			name: `Given a working command handler, a valid canvas ID (but not associated with any canvas), and a valid body request,
+                   when the resize canvas handler is called,
+                   then a status not found (404) response is returned`,
+			commandHandlerMutator: func(app.CommandHandler) app.CommandHandler {
+				handler := &CommandHandlerMock{
+					HandleFunc: func(context.Context, app.Command) error {
+						return app.CanvasNotFound{}
 					},
 				}
 				return handler
 			},
-			rendererMutator:    noopRendererMutator,
 			canvasID:           uuid.New().String(),
+			body:               `{"height":20}`,
 			expectedStatusCode: http.StatusNotFound,
 		},
 		{
-			name: `Given a non-working query handler, a working renderer, and a valid canvas ID,
-                   when the render canvas handler is called,
-                   then a status code internal server error (500) is returned`,
-			queryHandlerMutator: func(app.QueryHandler) app.QueryHandler {
-				handler := &QueryHandlerMock{
-					HandleFunc: func(context.Context, app.Query) (app.QueryResponse, error) {
-						return nil, errors.New("something else went wrong")
+			name: `Given a command handler that rejects the canvas size, a valid canvas ID, and a valid body request,
+                   when the resize canvas handler is called,
+                   then a status unprocessable entity (422) response is returned`,
+			commandHandlerMutator: func(app.CommandHandler) app.CommandHandler {
+				handler := &CommandHandlerMock{
+					HandleFunc: func(context.Context, app.Command) error {
+						return app.InvalidCanvasSizeError{}
 					},
 				}
 				return handler
 			},
-			rendererMutator:    noopRendererMutator,
 			canvasID:           uuid.New().String(),
-			expectedStatusCode: http.StatusInternalServerError,
+			body:               `{"height":5000}`,
+			expectedStatusCode: http.StatusUnprocessableEntity,
 		},
 		{
-			name: `Given a working query handler, but that returns a string, a working renderer, and a valid canvas ID,
-                   when the render canvas handler is called,
-                   then a status code internal server error (500) is returned`,
+			name: `Given a command handler that cannot fit the tasks in the new size, a valid canvas ID, and a valid body request,
+                   when the resize canvas handler is called,
+                   then a status unprocessable entity (422) response is returned`,
+			commandHandlerMutator: func(app.CommandHandler) app.CommandHandler {
+				handler := &CommandHandlerMock{
+					HandleFunc: func(context.Context, app.Command) error {
+						return domain.ErrOutOfBounds
+					},
+				}
+				return handler
+			},
+			canvasID:           uuid.New().String(),
+			body:               `{"height":2,"width":2}`,
+			expectedStatusCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name: `Given a non-working command handler, a valid canvas ID, and a valid body request,
+                   when the resize canvas handler is called,
+                   then a status internal server error (500) response is returned`,
+			commandHandlerMutator: func(app.CommandHandler) app.CommandHandler {
+				handler := &CommandHandlerMock{
+					HandleFunc: func(context.Context, app.Command) error {
+						return errors.New("something else went wrong")
+					},
+				}
+				return handler
+			},
+			canvasID:           uuid.New().String(),
+			body:               `{"width":20,"clip":true}`,
+			expectedStatusCode: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			commandHandler := tt.commandHandlerMutator(validCommandHandler())
+
+			chiCtx := chi.NewRouteContext()
+			chiCtx.URLParams.Add("canvasID", tt.canvasID)
+			ctx := context.WithValue(context.Background(), chi.RouteCtxKey, chiCtx)
+			logger := log.New()
+			ctx = httpx.ContextWithLogger(ctx, logger)
+
+			req, err := http.NewRequestWithContext(ctx, http.MethodPatch, fmt.Sprintf("/canvas/%s", tt.canvasID), strings.NewReader(tt.body))
+			require.NoError(t, err)
+
+			res := httptest.NewRecorder()
+
+			httpx.ResizeCanvasHandler(commandHandler)(res, req)
+			result := res.Result()
+			defer result.Body.Close()
+
+			require.Equal(t, tt.expectedStatusCode, result.StatusCode)
+		})
+	}
+}
+
+func TestUpdateTaskHandler(t *testing.T) {
+	tests := []struct {
+		name                  string
+		commandHandlerMutator commandHandlerMutator
+		canvasID              string
+		taskID                string
+		bodyReader            io.Reader
+		expectedStatusCode    int
+	}{
+		{
+			name: `Given a working command handler, a valid canvas ID, a valid task ID, and a valid body request,
+                   when the update task handler is called,
+                   then a status ok (200) response is returned`,
+			commandHandlerMutator: noopCommandHandlerMutator,
+			canvasID:              uuid.New().String(),
+			taskID:                uuid.New().String(),
+			bodyReader:            validDrawRectangleBodyReader(t),
+			expectedStatusCode:    http.StatusOK,
+		},
+		{
+			name: `Given a working command handler, a valid canvas ID, an invalid task ID, and a valid body request,
+                   when the update task handler is called,
+                   then a status bad request (400) response is returned`,
+			commandHandlerMutator: noopCommandHandlerMutator,
+			canvasID:              uuid.New().String(),
+			taskID:                "wololo",
+			bodyReader:            validDrawRectangleBodyReader(t),
+			expectedStatusCode:    http.StatusBadRequest,
+		},
+		{
+			name: `Given a working command handler, an invalid canvas ID, a valid task ID, and a valid body request,
+                   when the update task handler is called,
+                   then a status bad request (400) response is returned`,
+			commandHandlerMutator: noopCommandHandlerMutator,
+			canvasID:              "wololo",
+			taskID:                uuid.New().String(),
+			bodyReader:            validDrawRectangleBodyReader(t),
+			expectedStatusCode:    http.StatusBadRequest,
+		},
+		{
+			name: `Given a working command handler, a valid canvas ID, a valid task ID, and an invalid body request,
+                   when the update task handler is called,
+                   then a status bad request (400) response is returned`,
+			commandHandlerMutator: noopCommandHandlerMutator,
+			canvasID:              uuid.New().String(),
+			taskID:                uuid.New().String(),
+			bodyReader:            invalidDrawRectangleBodyReader(t),
+			expectedStatusCode:    http.StatusBadRequest,
+		},
+		{
+			name: `Given a command handler that cannot find the task, a valid canvas ID, a valid task ID, and a valid body request,
+                   when the update task handler is called,
+                   then a status not found (404) response is returned`,
+			commandHandlerMutator: func(app.CommandHandler) app.CommandHandler {
+				handler := &CommandHandlerMock{
+					HandleFunc: func(context.Context, app.Command) error {
+						return domain.ErrTaskNotFound
+					},
+				}
+				return handler
+			},
+			canvasID:           uuid.New().String(),
+			taskID:             uuid.New().String(),
+			bodyReader:         validDrawRectangleBodyReader(t),
+			expectedStatusCode: http.StatusNotFound,
+		},
+		{
+			name: `Given a command handler that rejects changing the type of the task, a valid canvas ID, a valid task ID, and a valid body request,
+                   when the update task handler is called,
+                   then a status unprocessable entity (422) response is returned`,
+			commandHandlerMutator: func(app.CommandHandler) app.CommandHandler {
+				handler := &CommandHandlerMock{
+					HandleFunc: func(context.Context, app.Command) error {
+						return domain.ErrTaskTypeMismatch
+					},
+				}
+				return handler
+			},
+			canvasID:           uuid.New().String(),
+			taskID:             uuid.New().String(),
+			bodyReader:         validDrawRectangleBodyReader(t),
+			expectedStatusCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name: `Given a command handler that finds the canvas changed by another request, a valid canvas ID, a valid task ID, and a valid body request,
+                   when the update task handler is called,
+                   then a status conflict (409) response is returned`,
+			commandHandlerMutator: func(app.CommandHandler) app.CommandHandler {
+				handler := &CommandHandlerMock{
+					HandleFunc: func(context.Context, app.Command) error {
+						return app.CanvasConflict{ID: uuid.New()}
+					},
+				}
+				return handler
+			},
+			canvasID:           uuid.New().String(),
+			taskID:             uuid.New().String(),
+			bodyReader:         validDrawRectangleBodyReader(t),
+			expectedStatusCode: http.StatusConflict,
+		},
+		{
+			name: `Given a command handler that finds the ID of the task taken, a valid canvas ID, a valid task ID, and a valid body request,
+                   when the update task handler is called,
+                   then a status conflict (409) response is returned`,
+			commandHandlerMutator: func(app.CommandHandler) app.CommandHandler {
+				handler := &CommandHandlerMock{
+					HandleFunc: func(context.Context, app.Command) error {
+						return domain.ErrTaskAlreadyExists
+					},
+				}
+				return handler
+			},
+			canvasID:           uuid.New().String(),
+			taskID:             uuid.New().String(),
+			bodyReader:         validDrawRectangleBodyReader(t),
+			expectedStatusCode: http.StatusConflict,
+		},
+		{
+			name: `Given a non-working command handler, a valid canvas ID, a valid task ID, and a valid body request,
+                   when the update task handler is called,
+                   then a status internal server error (500) response is returned`,
+			commandHandlerMutator: func(app.CommandHandler) app.CommandHandler {
+				handler := &CommandHandlerMock{
+					HandleFunc: func(context.Context, app.Command) error {
+						return errors.New("something else went wrong")
+					},
+				}
+				return handler
+			},
+			canvasID:           uuid.New().String(),
+			taskID:             uuid.New().String(),
+			bodyReader:         validDrawRectangleBodyReader(t),
+			expectedStatusCode: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			commandHandler := tt.commandHandlerMutator(validCommandHandler())
+
+			chiCtx := chi.NewRouteContext()
+			chiCtx.URLParams.Add("canvasID", tt.canvasID)
+			chiCtx.URLParams.Add("taskID", tt.taskID)
+			ctx := context.WithValue(context.Background(), chi.RouteCtxKey, chiCtx)
+			logger := log.New()
+			ctx = httpx.ContextWithLogger(ctx, logger)
+
+			req, err := http.NewRequestWithContext(ctx, http.MethodPut, fmt.Sprintf("/canvas/%s/tasks/%s", tt.canvasID, tt.taskID), tt.bodyReader)
+			require.NoError(t, err)
+
+			res := httptest.NewRecorder()
+
+			httpx.UpdateTaskHandler(commandHandler)(res, req)
+			result := res.Result()
+			defer result.Body.Close()
+
+			require.Equal(t, tt.expectedStatusCode, result.StatusCode)
+		})
+	}
+}
+
+func TestRemoveTaskHandler(t *testing.T) {
+	tests := []struct {
+		name                  string
+		commandHandlerMutator commandHandlerMutator
+		canvasID              string
+		taskID                string
+		expectedStatusCode    int
+	}{
+		{
+			name: `Given a working command handler, a valid canvas ID, and a valid task ID,
+                   when the remove task handler is called,
+                   then a status ok (200) response is returned`,
+			commandHandlerMutator: noopCommandHandlerMutator,
+			canvasID:              uuid.New().String(),
+			taskID:                uuid.New().String(),
+			expectedStatusCode:    http.StatusOK,
+		},
+		{
+			name: `Given a working command handler, an invalid canvas ID, and a valid task ID,
+                   when the remove task handler is called,
+                   then a status bad request (400) response is returned`,
+			commandHandlerMutator: noopCommandHandlerMutator,
+			canvasID:              "wololo",
+			taskID:                uuid.New().String(),
+			expectedStatusCode:    http.StatusBadRequest,
+		},
+		{
+			name: `Given a working command handler, a valid canvas ID, and an invalid task ID,
+                   when the remove task handler is called,
+                   then a status bad request (400) response is returned`,
+			commandHandlerMutator: noopCommandHandlerMutator,
+			canvasID:              uuid.New().String(),
+			taskID:                "wololo",
+			expectedStatusCode:    http.StatusBadRequest,
+		},
+		{
+			name: `Given a working command handler, a valid canvas ID (but not associated with any canvas), and a valid task ID,
+                   when the remove task handler is called,
+                   then a status not found (404) response is returned`,
+			commandHandlerMutator: func(app.CommandHandler) app.CommandHandler {
+				handler := &CommandHandlerMock{
+					HandleFunc: func(context.Context, app.Command) error {
+						return app.CanvasNotFound{}
+					},
+				}
+				return handler
+			},
+			canvasID:           uuid.New().String(),
+			taskID:             uuid.New().String(),
+			expectedStatusCode: http.StatusNotFound,
+		},
+		{
+			name: `Given a working command handler, a valid canvas ID, and a valid task ID (but not associated with any task),
+                   when the remove task handler is called,
+                   then a status not found (404) response is returned`,
+			commandHandlerMutator: func(app.CommandHandler) app.CommandHandler {
+				handler := &CommandHandlerMock{
+					HandleFunc: func(context.Context, app.Command) error {
+						return domain.ErrTaskNotFound
+					},
+				}
+				return handler
+			},
+			canvasID:           uuid.New().String(),
+			taskID:             uuid.New().String(),
+			expectedStatusCode: http.StatusNotFound,
+		},
+		{
+			name: `Given a non-working command handler, a valid canvas ID, and a valid task ID,
+                   when the remove task handler is called,
+                   then a status internal server error (500) response is returned`,
+			commandHandlerMutator: func(app.CommandHandler) app.CommandHandler {
+				handler := &CommandHandlerMock{
+					HandleFunc: func(context.Context, app.Command) error {
+						return errors.New("something else went wrong")
+					},
+				}
+				return handler
+			},
+			canvasID:           uuid.New().String(),
+			taskID:             uuid.New().String(),
+			expectedStatusCode: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			commandHandler := tt.commandHandlerMutator(validCommandHandler())
+
+			chiCtx := chi.NewRouteContext()
+			chiCtx.URLParams.Add("canvasID", tt.canvasID)
+			chiCtx.URLParams.Add("taskID", tt.taskID)
+			ctx := context.WithValue(context.Background(), chi.RouteCtxKey, chiCtx)
+			logger := log.New()
+			ctx = httpx.ContextWithLogger(ctx, logger)
+
+			req, err := http.NewRequestWithContext(ctx, http.MethodDelete, fmt.Sprintf("/canvas/%s/tasks/%s", tt.canvasID, tt.taskID), nil)
+			require.NoError(t, err)
+
+			res := httptest.NewRecorder()
+
+			httpx.RemoveTaskHandler(commandHandler)(res, req)
+			result := res.Result()
+			defer result.Body.Close()
+
+			require.Equal(t, tt.expectedStatusCode, result.StatusCode)
+		})
+	}
+}
+
+func TestUndoRedoHandlers(t *testing.T) {
+	handlers := map[string]func(app.CommandHandler) http.HandlerFunc{
+		"undo": httpx.UndoHandler,
+		"redo": httpx.RedoHandler,
+	}
+
+	tests := []struct {
+		name                  string
+		commandHandlerMutator commandHandlerMutator
+		canvasID              string
+		expectedStatusCode    int
+	}{
+		{
+			name: `Given a working command handler and a valid canvas ID,
+                   when the undo or redo handler is called,
+                   then a status ok (200) response is returned`,
+			commandHandlerMutator: noopCommandHandlerMutator,
+			canvasID:              uuid.New().String(),
+			expectedStatusCode:    http.StatusOK,
+		},
+		{
+			name: `Given a working command handler and an invalid canvas ID,
+                   when the undo or redo handler is called,
+                   then a status bad request (400) response is returned`,
+			commandHandlerMutator: noopCommandHandlerMutator,
+			canvasID:              "wololo",
+			expectedStatusCode:    http.StatusBadRequest,
+		},
+		{
+			name: `Given a working command handler and a valid canvas ID (but not associated with any canvas),
+                   when the undo or redo handler is called,
+                   then a status not found (404) response is returned`,
+			commandHandlerMutator: func(app.CommandHandler) app.CommandHandler {
+				handler := &CommandHandlerMock{
+					HandleFunc: func(context.Context, app.Command) error {
+						return app.CanvasNotFound{}
+					},
+				}
+				return handler
+			},
+			canvasID:           uuid.New().String(),
+			expectedStatusCode: http.StatusNotFound,
+		},
+		{
+			name: `Given a command handler without tasks to undo or redo and a valid canvas ID,
+                   when the undo or redo handler is called,
+                   then a status conflict (409) response is returned`,
+			commandHandlerMutator: func(app.CommandHandler) app.CommandHandler {
+				handler := &CommandHandlerMock{
+					HandleFunc: func(_ context.Context, cmd app.Command) error {
+						if _, ok := cmd.(app.UndoCmd); ok {
+							return domain.ErrNothingToUndo
+						}
+						return domain.ErrNothingToRedo
+					},
+				}
+				return handler
+			},
+			canvasID:           uuid.New().String(),
+			expectedStatusCode: http.StatusConflict,
+		},
+		{
+			name: `Given a non-working command handler and a valid canvas ID,
+                   when the undo or redo handler is called,
+                   then a status internal server error (500) response is returned`,
+			commandHandlerMutator: func(app.CommandHandler) app.CommandHandler {
+				handler := &CommandHandlerMock{
+					HandleFunc: func(context.Context, app.Command) error {
+						return errors.New("something else went wrong")
+					},
+				}
+				return handler
+			},
+			canvasID:           uuid.New().String(),
+			expectedStatusCode: http.StatusInternalServerError,
+		},
+	}
+	for action, handler := range handlers {
+		action, handler := action, handler
+		for _, tt := range tests {
+			tt := tt
+			t.Run(fmt.Sprintf("%s: %s", action, tt.name), func(t *testing.T) {
+				t.Parallel()
+
+				commandHandler := tt.commandHandlerMutator(validCommandHandler())
+
+				chiCtx := chi.NewRouteContext()
+				chiCtx.URLParams.Add("canvasID", tt.canvasID)
+				ctx := context.WithValue(context.Background(), chi.RouteCtxKey, chiCtx)
+				logger := log.New()
+				ctx = httpx.ContextWithLogger(ctx, logger)
+
+				req, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf("/canvas/%s/%s", tt.canvasID, action), nil)
+				require.NoError(t, err)
+
+				res := httptest.NewRecorder()
+
+				handler(commandHandler)(res, req)
+				result := res.Result()
+				defer result.Body.Close()
+
+				require.Equal(t, tt.expectedStatusCode, result.StatusCode)
+			})
+		}
+	}
+}
+
+func validQueryHandler() app.QueryHandler {
+	return &QueryHandlerMock{
+		HandleFunc: func(context.Context, app.Query) (app.QueryResponse, error) {
+			return domain.NewCanvas(uuid.New(), 4, 6, nil, time.Now().UTC()), nil
+		},
+	}
+}
+
+type queryHandlerMutator func(app.QueryHandler) app.QueryHandler
+
+var noopQueryHandlerMutator = func(handler app.QueryHandler) app.QueryHandler { return handler }
+
+//nolint:funlen
+func TestRenderCanvasHandler(t *testing.T) {
+	tests := []struct {
+		name                string
+		queryHandlerMutator queryHandlerMutator
+		rendererMutator     rendererMutator
+		canvasID            string
+		expectedStatusCode  int
+	}{
+		{
+			name: `Given a working query handler, a working renderer, and a valid canvas ID,
+                   when the render canvas handler is called,
+                   then a status code ok (200) is returned`,
+			queryHandlerMutator: noopQueryHandlerMutator,
+			rendererMutator:     noopRendererMutator,
+			canvasID:            uuid.New().String(),
+			expectedStatusCode:  http.StatusOK,
+		},
+		{
+			name: `Given a working query handler, a working renderer, and an invalid canvas ID,
+                   when the render canvas handler is called,
+                   then a status code bad request (400) is returned`,
+			queryHandlerMutator: noopQueryHandlerMutator,
+			rendererMutator:     noopRendererMutator,
+			canvasID:            "",
+			expectedStatusCode:  http.StatusBadRequest,
+		},
+		{
+			name: `Given a working query handler, a working renderer, and a valid canvas ID, but that does not exists,
+                   when the render canvas handler is called,
+                   then a status code not found (404) is returned`,
+			queryHandlerMutator: func(app.QueryHandler) app.QueryHandler {
+				handler := &QueryHandlerMock{
+					HandleFunc: func(context.Context, app.Query) (app.QueryResponse, error) {
+						return nil, app.CanvasNotFound{}
+					},
+				}
+				return handler
+			},
+			rendererMutator:    noopRendererMutator,
+			canvasID:           uuid.New().String(),
+			expectedStatusCode: http.StatusNotFound,
+		},
+		{
+			name: `Given a non-working query handler, a working renderer, and a valid canvas ID,
+                   when the render canvas handler is called,
+                   then a status code internal server error (500) is returned`,
+			queryHandlerMutator: func(app.QueryHandler) app.QueryHandler {
+				handler := &QueryHandlerMock{
+					HandleFunc: func(context.Context, app.Query) (app.QueryResponse, error) {
+						return nil, errors.New("something else went wrong")
+					},
+				}
+				return handler
+			},
+			rendererMutator:    noopRendererMutator,
+			canvasID:           uuid.New().String(),
+			expectedStatusCode: http.StatusInternalServerError,
+		},
+		{
+			name: `Given a working query handler, but that returns a string, a working renderer, and a valid canvas ID,
+                   when the render canvas handler is called,
+                   then a status code internal server error (500) is returned`,
+			queryHandlerMutator: func(app.QueryHandler) app.QueryHandler {
+				handler := &QueryHandlerMock{
+					HandleFunc: func(context.Context, app.Query) (app.QueryResponse, error) {
+						return "", nil
+					},
+				}
+				return handler
+			},
+			rendererMutator:    noopRendererMutator,
+			canvasID:           uuid.New().String(),
+			expectedStatusCode: http.StatusInternalServerError,
+		},
+		{
+			name: `Given a working query handler, a non-working renderer, and a valid canvas ID,
+                   when the render canvas handler is called,
+                   then a status code internal server error (500) is returned`,
+			queryHandlerMutator: noopQueryHandlerMutator,
+			rendererMutator: func(httpx.Renderer) httpx.Renderer {
+				renderer := &RendererMock{
+					RenderFunc: func(io.Writer, domain.Canvas, raster.Viewport) error {
+						return errors.New("something went wrong")
+					},
+				}
+				return renderer
+			},
+			canvasID:           uuid.New().String(),
+			expectedStatusCode: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			queryHandler := tt.queryHandlerMutator(validQueryHandler())
+			renderer := tt.rendererMutator(validRenderer())
+
+			chiCtx := chi.NewRouteContext()
+			chiCtx.URLParams.Add("canvasID", tt.canvasID)
+			ctx := context.WithValue(context.Background(), chi.RouteCtxKey, chiCtx)
+			logger := log.New()
+			ctx = httpx.ContextWithLogger(ctx, logger)
+
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("/canvas/%s", uuid.New()), nil)
+			require.NoError(t, err)
+
+			res := httptest.NewRecorder()
+
+			httpx.RenderCanvasHandler(queryHandler, httpx.Renderers{httpx.TextMediaType: renderer}, cache.NewLRU(renderCacheCapacity), app.Snapshotter{})(res, req)
+			result := res.Result()
+			defer result.Body.Close()
+
+			require.Equal(t, tt.expectedStatusCode, result.StatusCode)
+		})
+	}
+}
+
+func writerRenderer(output string) httpx.Renderer {
+	return &RendererMock{
+		RenderFunc: func(writer io.Writer, _ domain.Canvas, _ raster.Viewport) error {
+			_, err := io.WriteString(writer, output)
+			return err
+		},
+	}
+}
+
+func TestRenderCanvasHandler_ContentNegotiation(t *testing.T) {
+	tests := []struct {
+		name                string
+		accept              string
+		format              string
+		expectedStatusCode  int
+		expectedContentType string
+		expectedBody        string
+	}{
+		{
+			name: `Given a request without Accept header,
+                   when the render canvas handler is called,
+                   then the canvas is rendered as plain text`,
+			expectedStatusCode:  http.StatusOK,
+			expectedContentType: "text/plain; charset=utf-8",
+			expectedBody:        "text",
+		},
+		{
+			name: `Given a request that accepts any media type,
+                   when the render canvas handler is called,
+                   then the canvas is rendered as plain text`,
+			accept:              "*/*",
+			expectedStatusCode:  http.StatusOK,
+			expectedContentType: "text/plain; charset=utf-8",
+			expectedBody:        "text",
+		},
+		{
+			name: `Given a request that accepts SVG,
+                   when the render canvas handler is called,
+                   then the canvas is rendered as SVG`,
+			accept:              "image/svg+xml",
+			expectedStatusCode:  http.StatusOK,
+			expectedContentType: "image/svg+xml",
+			expectedBody:        "svg",
+		},
+		{
+			name: `Given a request that accepts PNG,
+                   when the render canvas handler is called,
+                   then the canvas is rendered as PNG`,
+			accept:              "image/png",
+			expectedStatusCode:  http.StatusOK,
+			expectedContentType: "image/png",
+			expectedBody:        "png",
+		},
+		{
+			name: `Given a request that accepts any image,
+                   when the render canvas handler is called,
+                   then the canvas is rendered as the first image media type in alphabetical order`,
+			accept:              "image/*",
+			expectedStatusCode:  http.StatusOK,
+			expectedContentType: "image/png",
+			expectedBody:        "png",
+		},
+		{
+			name: `Given a request that prefers SVG over any other media type,
+                   when the render canvas handler is called,
+                   then the canvas is rendered as SVG`,
+			accept:              "text/html, */*;q=0.1, image/svg+xml;q=0.9",
+			expectedStatusCode:  http.StatusOK,
+			expectedContentType: "image/svg+xml",
+			expectedBody:        "svg",
+		},
+		{
+			name: `Given a request that prefers plain text over SVG,
+                   when the render canvas handler is called,
+                   then the canvas is rendered as plain text`,
+			accept:              "image/svg+xml;q=0.5, text/plain",
+			expectedStatusCode:  http.StatusOK,
+			expectedContentType: "text/plain; charset=utf-8",
+			expectedBody:        "text",
+		},
+		{
+			name: `Given a request that only accepts a media type without renderer,
+                   when the render canvas handler is called,
+                   then a status code not acceptable (406) is returned`,
+			accept:             "application/pdf",
+			expectedStatusCode: http.StatusNotAcceptable,
+		},
+		{
+			name: `Given a request that accepts SVG with quality zero,
+                   when the render canvas handler is called,
+                   then a status code not acceptable (406) is returned`,
+			accept:             "image/svg+xml;q=0",
+			expectedStatusCode: http.StatusNotAcceptable,
+		},
+		{
+			name: `Given a request with the png format,
+                   when the render canvas handler is called,
+                   then the canvas is rendered as PNG`,
+			format:              "png",
+			expectedStatusCode:  http.StatusOK,
+			expectedContentType: "image/png",
+			expectedBody:        "png",
+		},
+		{
+			name: `Given a request with the svg format that accepts plain text,
+                   when the render canvas handler is called,
+                   then the format takes precedence and the canvas is rendered as SVG`,
+			accept:              "text/plain",
+			format:              "svg",
+			expectedStatusCode:  http.StatusOK,
+			expectedContentType: "image/svg+xml",
+			expectedBody:        "svg",
+		},
+		{
+			name: `Given a request with the text format that only accepts a media type without renderer,
+                   when the render canvas handler is called,
+                   then the canvas is rendered as plain text`,
+			accept:              "application/pdf",
+			format:              "text",
+			expectedStatusCode:  http.StatusOK,
+			expectedContentType: "text/plain; charset=utf-8",
+			expectedBody:        "text",
+		},
+		{
+			name: `Given a request that accepts text with ANSI escape codes,
+                   when the render canvas handler is called,
+                   then the canvas is rendered with ANSI escape codes`,
+			accept:              "text/x-ansi",
+			expectedStatusCode:  http.StatusOK,
+			expectedContentType: "text/x-ansi; charset=utf-8",
+			expectedBody:        "ansi",
+		},
+		{
+			name: `Given a request with the ansi format,
+                   when the render canvas handler is called,
+                   then the canvas is rendered with ANSI escape codes`,
+			format:              "ansi",
+			expectedStatusCode:  http.StatusOK,
+			expectedContentType: "text/x-ansi; charset=utf-8",
+			expectedBody:        "ansi",
+		},
+		{
+			name: `Given a request with the html format and no renderer for HTML,
+                   when the render canvas handler is called,
+                   then a status code not acceptable (406) is returned`,
+			format:             "html",
+			expectedStatusCode: http.StatusNotAcceptable,
+		},
+		{
+			name: `Given a request with an unknown format,
+                   when the render canvas handler is called,
+                   then a status code bad request (400) is returned`,
+			format:             "bmp",
+			expectedStatusCode: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			chiCtx := chi.NewRouteContext()
+			chiCtx.URLParams.Add("canvasID", uuid.New().String())
+			ctx := context.WithValue(context.Background(), chi.RouteCtxKey, chiCtx)
+			logger := log.New()
+			ctx = httpx.ContextWithLogger(ctx, logger)
+
+			target := fmt.Sprintf("/canvas/%s", uuid.New())
+			if tt.format != "" {
+				target += "?format=" + tt.format
+			}
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+			require.NoError(t, err)
+			if tt.accept != "" {
+				req.Header.Set("Accept", tt.accept)
+			}
+
+			res := httptest.NewRecorder()
+
+			httpx.RenderCanvasHandler(validQueryHandler(), httpx.Renderers{
+				httpx.TextMediaType: writerRenderer("text"),
+				httpx.SVGMediaType:  writerRenderer("svg"),
+				httpx.PNGMediaType:  writerRenderer("png"),
+				httpx.ANSIMediaType: writerRenderer("ansi"),
+			}, cache.NewLRU(renderCacheCapacity), app.Snapshotter{})(res, req)
+			result := res.Result()
+			defer result.Body.Close()
+
+			require.Equal(t, tt.expectedStatusCode, result.StatusCode)
+			require.Equal(t, "Accept", result.Header.Get("Vary"))
+			if tt.expectedStatusCode == http.StatusOK {
+				require.Equal(t, tt.expectedContentType, result.Header.Get("Content-Type"))
+				body, err := io.ReadAll(result.Body)
+				require.NoError(t, err)
+				require.Equal(t, tt.expectedBody, string(body))
+			}
+		})
+	}
+}
+
+func TestRenderCanvasHandler_Owners(t *testing.T) {
+	tests := []struct {
+		name               string
+		format             string
+		owners             string
+		expectedStatusCode int
+		expectedBody       string
+	}{
+		{
+			name: `Given a request for the owners of the cells of a renderer that supports them,
+                   when the render canvas handler is called,
+                   then the canvas is rendered with the owners`,
+			format:             "json",
+			owners:             "true",
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       "json with owners",
+		},
+		{
+			name: `Given a request without the owners of the cells for a renderer that supports them,
+                   when the render canvas handler is called,
+                   then the canvas is rendered without the owners`,
+			format:             "json",
+			owners:             "false",
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       "json",
+		},
+		{
+			name: `Given a request for the owners of the cells of a renderer that does not support them,
+                   when the render canvas handler is called,
+                   then the owners are ignored`,
+			format:             "text",
+			owners:             "true",
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       "text",
+		},
+		{
+			name: `Given a request with an invalid owners value,
+                   when the render canvas handler is called,
+                   then a status code bad request (400) is returned`,
+			format:             "json",
+			owners:             "sometimes",
+			expectedStatusCode: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			chiCtx := chi.NewRouteContext()
+			chiCtx.URLParams.Add("canvasID", uuid.New().String())
+			ctx := context.WithValue(context.Background(), chi.RouteCtxKey, chiCtx)
+			logger := log.New()
+			ctx = httpx.ContextWithLogger(ctx, logger)
+
+			target := fmt.Sprintf("/canvas/%s?format=%s&owners=%s", uuid.New(), tt.format, tt.owners)
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+			require.NoError(t, err)
+
+			res := httptest.NewRecorder()
+
+			httpx.RenderCanvasHandler(validQueryHandler(), httpx.Renderers{
+				httpx.TextMediaType: writerRenderer("text"),
+				httpx.JSONMediaType: &OwnersRendererMock{
+					RenderFunc: func(writer io.Writer, _ domain.Canvas, _ raster.Viewport) error {
+						_, err := io.WriteString(writer, "json")
+						return err
+					},
+					RenderWithOwnersFunc: func(writer io.Writer, _ domain.Canvas, _ raster.Viewport) error {
+						_, err := io.WriteString(writer, "json with owners")
+						return err
+					},
+				},
+			}, cache.NewLRU(renderCacheCapacity), app.Snapshotter{})(res, req)
+			result := res.Result()
+			defer result.Body.Close()
+
+			require.Equal(t, tt.expectedStatusCode, result.StatusCode)
+			if tt.expectedStatusCode == http.StatusOK {
+				body, err := io.ReadAll(result.Body)
+				require.NoError(t, err)
+				require.Equal(t, tt.expectedBody, string(body))
+			}
+		})
+	}
+}
+
+func TestRenderCanvasHandler_Viewport(t *testing.T) {
+	tests := []struct {
+		name               string
+		query              string
+		expectedStatusCode int
+		expectedViewport   raster.Viewport
+	}{
+		{
+			name: `Given a request without viewport,
+                   when the render canvas handler is called,
+                   then the whole canvas is rendered`,
+			expectedStatusCode: http.StatusOK,
+			expectedViewport:   raster.Viewport{X: 0, Y: 0, Width: 6, Height: 4},
+		},
+		{
+			name: `Given a request with a viewport inside of the canvas,
+                   when the render canvas handler is called,
+                   then the viewport of the canvas is rendered`,
+			query:              "x=1&y=2&width=3&height=2",
+			expectedStatusCode: http.StatusOK,
+			expectedViewport:   raster.Viewport{X: 1, Y: 2, Width: 3, Height: 2},
+		},
+		{
+			name: `Given a request with only the start of the viewport,
+                   when the render canvas handler is called,
+                   then the canvas is rendered from the start to its bottom right corner`,
+			query:              "x=2&y=1",
+			expectedStatusCode: http.StatusOK,
+			expectedViewport:   raster.Viewport{X: 2, Y: 1, Width: 4, Height: 3},
+		},
+		{
+			name: `Given a request with a viewport with a width that is not a number,
+                   when the render canvas handler is called,
+                   then a status code bad request (400) is returned`,
+			query:              "width=wide",
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name: `Given a request with a viewport that starts outside of the canvas,
+                   when the render canvas handler is called,
+                   then a status code bad request (400) is returned`,
+			query:              "x=-1",
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name: `Given a request with a viewport that does not fit in the canvas,
+                   when the render canvas handler is called,
+                   then a status code bad request (400) is returned`,
+			query:              "y=1&height=4",
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name: `Given a request with an empty viewport,
+                   when the render canvas handler is called,
+                   then a status code bad request (400) is returned`,
+			query:              "width=0",
+			expectedStatusCode: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			chiCtx := chi.NewRouteContext()
+			chiCtx.URLParams.Add("canvasID", uuid.New().String())
+			ctx := context.WithValue(context.Background(), chi.RouteCtxKey, chiCtx)
+			logger := log.New()
+			ctx = httpx.ContextWithLogger(ctx, logger)
+
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("/canvas/%s?%s", uuid.New(), tt.query), nil)
+			require.NoError(t, err)
+
+			res := httptest.NewRecorder()
+
+			renderer := &RendererMock{
+				RenderFunc: func(io.Writer, domain.Canvas, raster.Viewport) error {
+					return nil
+				},
+			}
+			httpx.RenderCanvasHandler(validQueryHandler(), httpx.Renderers{httpx.TextMediaType: renderer}, cache.NewLRU(renderCacheCapacity), app.Snapshotter{})(res, req)
+			result := res.Result()
+			defer result.Body.Close()
+
+			require.Equal(t, tt.expectedStatusCode, result.StatusCode)
+			if tt.expectedStatusCode == http.StatusOK {
+				require.Len(t, renderer.RenderCalls(), 1)
+				require.Equal(t, tt.expectedViewport, renderer.RenderCalls()[0].Viewport)
+			} else {
+				require.Empty(t, renderer.RenderCalls())
+			}
+		})
+	}
+}
+
+func TestRenderCanvasHandler_Cache(t *testing.T) {
+	type step struct {
+		query      string
+		invalidate bool
+	}
+
+	tests := []struct {
+		name                string
+		steps               []step
+		expectedCache       []string
+		expectedRenderCalls int
+	}{
+		{
+			name: `Given a canvas that has not been rendered,
+                   when the render canvas handler is called,
+                   then the canvas is rendered and stored in the cache`,
+			steps:               []step{{}},
+			expectedCache:       []string{"MISS"},
+			expectedRenderCalls: 1,
+		},
+		{
+			name: `Given a canvas that has been rendered,
+                   when the render canvas handler is called with the same parameters,
+                   then the rendering is returned from the cache`,
+			steps:               []step{{}, {}},
+			expectedCache:       []string{"MISS", "HIT"},
+			expectedRenderCalls: 1,
+		},
+		{
+			name: `Given a canvas that has been rendered,
+                   when the render canvas handler is called with another viewport,
+                   then the canvas is rendered again`,
+			steps:               []step{{}, {query: "x=1"}, {query: "x=1"}},
+			expectedCache:       []string{"MISS", "MISS", "HIT"},
+			expectedRenderCalls: 2,
+		},
+		{
+			name: `Given a canvas that has been rendered,
+                   when the render canvas handler is called with another format,
+                   then the canvas is rendered again`,
+			steps:               []step{{}, {query: "format=json"}},
+			expectedCache:       []string{"MISS", "MISS"},
+			expectedRenderCalls: 2,
+		},
+		{
+			name: `Given a canvas that has been rendered and then invalidated,
+                   when the render canvas handler is called with the same parameters,
+                   then the canvas is rendered again`,
+			steps:               []step{{}, {invalidate: true}},
+			expectedCache:       []string{"MISS", "MISS"},
+			expectedRenderCalls: 2,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			canvasID := uuid.New()
+			renderer := writerRenderer("canvas").(*RendererMock)
+			renderers := httpx.Renderers{httpx.TextMediaType: renderer, httpx.JSONMediaType: renderer}
+			renderCache := cache.NewLRU(renderCacheCapacity)
+			handler := httpx.RenderCanvasHandler(validQueryHandler(), renderers, renderCache, app.Snapshotter{})
+
+			for i, step := range tt.steps {
+				if step.invalidate {
+					renderCache.Invalidate(canvasID)
+				}
+
+				chiCtx := chi.NewRouteContext()
+				chiCtx.URLParams.Add("canvasID", canvasID.String())
+				ctx := context.WithValue(context.Background(), chi.RouteCtxKey, chiCtx)
+				logger := log.New()
+				ctx = httpx.ContextWithLogger(ctx, logger)
+
+				req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("/canvas/%s?%s", canvasID, step.query), nil)
+				require.NoError(t, err)
+
+				res := httptest.NewRecorder()
+				handler(res, req)
+				result := res.Result()
+				body, err := io.ReadAll(result.Body)
+				require.NoError(t, err)
+				result.Body.Close()
+
+				require.Equal(t, http.StatusOK, result.StatusCode)
+				require.Equal(t, tt.expectedCache[i], result.Header.Get("X-Cache"))
+				require.Equal(t, "canvas", string(body))
+			}
+			require.Len(t, renderer.RenderCalls(), tt.expectedRenderCalls)
+		})
+	}
+}
+
+func TestRenderCacheStatsHandler(t *testing.T) {
+	t.Parallel()
+
+	renderCache := &RenderCacheMock{
+		StatsFunc: func() app.RenderCacheStats {
+			return app.RenderCacheStats{Hits: 3, Misses: 2, Entries: 1, Size: 42}
+		},
+	}
+
+	ctx := httpx.ContextWithLogger(context.Background(), log.New())
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "/render-cache/stats", nil)
+	require.NoError(t, err)
+
+	res := httptest.NewRecorder()
+	httpx.RenderCacheStatsHandler(renderCache)(res, req)
+	result := res.Result()
+	defer result.Body.Close()
+
+	require.Equal(t, http.StatusOK, result.StatusCode)
+	require.Equal(t, "application/json", result.Header.Get("Content-Type"))
+
+	var response httpx.RenderCacheStatsResponse
+	require.NoError(t, json.NewDecoder(result.Body).Decode(&response))
+	require.Equal(t, httpx.RenderCacheStatsResponse{Hits: 3, Misses: 2, Entries: 1, Size: 42}, response)
+}
+
+func TestListTasksHandler(t *testing.T) {
+	rectangle := domain.NewDrawRectangle(uuid.New(), domain.NewPoint(1, 2), 3, 4, 'x', '@', time.Now().UTC()).WithSequence(1)
+	fill := domain.NewFill(uuid.New(), domain.NewPoint(0, 0), '-', time.Now().UTC()).WithSequence(2)
+	filler, outline := "x", "@"
+	listTasksQueryHandler := func(app.QueryHandler) app.QueryHandler {
+		return &QueryHandlerMock{
+			HandleFunc: func(context.Context, app.Query) (app.QueryResponse, error) {
+				return app.ListTasksResponse{Tasks: []domain.Task{rectangle, fill}, Total: 2}, nil
+			},
+		}
+	}
+
+	tests := []struct {
+		name                string
+		queryHandlerMutator queryHandlerMutator
+		canvasID            string
+		rawQuery            string
+		expectedStatusCode  int
+		expectedResponse    *httpx.ListTasksResponse
+	}{
+		{
+			name: `Given a working query handler and a valid canvas ID,
+                   when the list tasks handler is called,
+                   then a status code ok (200) is returned with the tasks of the canvas`,
+			queryHandlerMutator: listTasksQueryHandler,
+			canvasID:            uuid.New().String(),
+			expectedStatusCode:  http.StatusOK,
+			expectedResponse: &httpx.ListTasksResponse{
+				Tasks: []httpx.TaskResponse{
+					{
+						TaskRequest: httpx.TaskRequest{
+							Type: httpx.DrawRectangleRequestType,
+							Rectangle: &httpx.DrawRectangleRequest{
+								ID:      rectangle.ID(),
+								Point:   httpx.Point{X: 1, Y: 2},
+								Height:  3,
+								Width:   4,
+								Filler:  &filler,
+								Outline: &outline,
+							},
+						},
+						ID:        rectangle.ID(),
+						Sequence:  1,
+						CreatedAt: rectangle.CreatedAt(),
+					},
+					{
+						TaskRequest: httpx.TaskRequest{
+							Type: httpx.AddFillRequestType,
+							Fill: &httpx.AddFillRequest{
+								ID:           fill.ID(),
+								Point:        httpx.Point{X: 0, Y: 0},
+								Filler:       "-",
+								Connectivity: 4,
+								Mode:         "flood",
+							},
+						},
+						ID:        fill.ID(),
+						Sequence:  2,
+						CreatedAt: fill.CreatedAt(),
+					},
+				},
+				Total: 2,
+				Limit: 100,
+			},
+		},
+		{
+			name: `Given a working query handler, a valid canvas ID, and a valid type filter and pagination,
+                   when the list tasks handler is called,
+                   then a status code ok (200) is returned`,
 			queryHandlerMutator: func(app.QueryHandler) app.QueryHandler {
-				handler := &QueryHandlerMock{
+				return &QueryHandlerMock{
+					HandleFunc: func(_ context.Context, query app.Query) (app.QueryResponse, error) {
+						if query != (app.ListTasksQuery{CanvasID: query.(app.ListTasksQuery).CanvasID, Type: app.FillTaskType, Offset: 1, Limit: 5}) {
+							return nil, errors.New("unexpected query")
+						}
+						return app.ListTasksResponse{}, nil
+					},
+				}
+			},
+			canvasID:           uuid.New().String(),
+			rawQuery:           "type=add_fill&offset=1&limit=5",
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name: `Given a working query handler and an invalid canvas ID,
+                   when the list tasks handler is called,
+                   then a status code bad request (400) is returned`,
+			queryHandlerMutator: noopQueryHandlerMutator,
+			canvasID:            "",
+			expectedStatusCode:  http.StatusBadRequest,
+		},
+		{
+			name: `Given a working query handler, a valid canvas ID, and an unknown task type,
+                   when the list tasks handler is called,
+                   then a status code bad request (400) is returned`,
+			queryHandlerMutator: noopQueryHandlerMutator,
+			canvasID:            uuid.New().String(),
+			rawQuery:            "type=draw_star",
+			expectedStatusCode:  http.StatusBadRequest,
+		},
+		{
+			name: `Given a working query handler, a valid canvas ID, and a negative offset,
+                   when the list tasks handler is called,
+                   then a status code bad request (400) is returned`,
+			queryHandlerMutator: noopQueryHandlerMutator,
+			canvasID:            uuid.New().String(),
+			rawQuery:            "offset=-1",
+			expectedStatusCode:  http.StatusBadRequest,
+		},
+		{
+			name: `Given a working query handler, a valid canvas ID, and a limit that is too big,
+                   when the list tasks handler is called,
+                   then a status code bad request (400) is returned`,
+			queryHandlerMutator: noopQueryHandlerMutator,
+			canvasID:            uuid.New().String(),
+			rawQuery:            "limit=1001",
+			expectedStatusCode:  http.StatusBadRequest,
+		},
+		{
+			name: `Given a working query handler, a valid canvas ID, and a limit that is not a number,
+                   when the list tasks handler is called,
+                   then a status code bad request (400) is returned`,
+			queryHandlerMutator: noopQueryHandlerMutator,
+			canvasID:            uuid.New().String(),
+			rawQuery:            "limit=ten",
+			expectedStatusCode:  http.StatusBadRequest,
+		},
+		{
+			name: `Given a working query handler and a valid canvas ID, but that does not exists,
+                   when the list tasks handler is called,
+                   then a status code not found (404) is returned`,
+			queryHandlerMutator: func(app.QueryHandler) app.QueryHandler {
+				return &QueryHandlerMock{
 					HandleFunc: func(context.Context, app.Query) (app.QueryResponse, error) {
-						return "", nil
+						return nil, app.CanvasNotFound{}
 					},
 				}
-				return handler
 			},
-			rendererMutator:    noopRendererMutator,
 			canvasID:           uuid.New().String(),
-			expectedStatusCode: http.StatusInternalServerError,
+			expectedStatusCode: http.StatusNotFound,
 		},
 		{
-			name: `Given a working query handler, a non-working renderer, and a valid canvas ID,
-                   when the render canvas handler is called,
+			name: `Given a non-working query handler and a valid canvas ID,
+                   when the list tasks handler is called,
                    then a status code internal server error (500) is returned`,
-			queryHandlerMutator: noopQueryHandlerMutator,
-			rendererMutator: func(httpx.Renderer) httpx.Renderer {
-				renderer := &RendererMock{
-					RenderFunc: func(io.Writer, domain.Canvas) error {
-						return errors.New("something went wrong")
+			queryHandlerMutator: func(app.QueryHandler) app.QueryHandler {
+				return &QueryHandlerMock{
+					HandleFunc: func(context.Context, app.Query) (app.QueryResponse, error) {
+						return nil, errors.New("something else went wrong")
 					},
 				}
-				return renderer
 			},
 			canvasID:           uuid.New().String(),
 			expectedStatusCode: http.StatusInternalServerError,
 		},
+		{
+			name: `Given a working query handler, but that returns a canvas, and a valid canvas ID,
+                   when the list tasks handler is called,
+                   then a status code internal server error (500) is returned`,
+			queryHandlerMutator: noopQueryHandlerMutator,
+			canvasID:            uuid.New().String(),
+			expectedStatusCode:  http.StatusInternalServerError,
+		},
 	}
 	for _, tt := range tests {
 		tt := tt
@@ -381,7 +1863,6 @@ func TestRenderCanvasHandler(t *testing.T) {
 			t.Parallel()
 
 			queryHandler := tt.queryHandlerMutator(validQueryHandler())
-			renderer := tt.rendererMutator(validRenderer())
 
 			chiCtx := chi.NewRouteContext()
 			chiCtx.URLParams.Add("canvasID", tt.canvasID)
@@ -389,16 +1870,22 @@ func TestRenderCanvasHandler(t *testing.T) {
 			logger := log.New()
 			ctx = httpx.ContextWithLogger(ctx, logger)
 
-			req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("/canvas/%s", uuid.New()), nil)
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("/canvas/%s/tasks?%s", uuid.New(), tt.rawQuery), nil)
 			require.NoError(t, err)
 
 			res := httptest.NewRecorder()
 
-			httpx.RenderCanvasHandler(queryHandler, renderer)(res, req)
+			httpx.ListTasksHandler(queryHandler)(res, req)
 			result := res.Result()
 			defer result.Body.Close()
 
 			require.Equal(t, tt.expectedStatusCode, result.StatusCode)
+			if tt.expectedResponse != nil {
+				require.Equal(t, "application/json", result.Header.Get("Content-Type"))
+				var response httpx.ListTasksResponse
+				require.NoError(t, json.NewDecoder(result.Body).Decode(&response))
+				require.Equal(t, *tt.expectedResponse, response)
+			}
 		})
 	}
 }
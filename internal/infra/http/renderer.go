@@ -2,12 +2,130 @@ package http
 
 import (
 	"io"
+	"mime"
+	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/maitesin/sketch/internal/domain"
+	"github.com/maitesin/sketch/internal/infra/raster"
 )
 
-//go:generate moq -out zmock_renderer_test.go -pkg http_test . Renderer
+//go:generate moq -out zmock_renderer_test.go -pkg http_test . Renderer OwnersRenderer
 
+// Renderer renders the cells of the canvas inside of the viewport
 type Renderer interface {
-	Render(writer io.Writer, canvas domain.Canvas) error
+	Render(writer io.Writer, canvas domain.Canvas, viewport raster.Viewport) error
+}
+
+// OwnersRenderer is a renderer that can also include the task that drew each cell of the canvas last
+type OwnersRenderer interface {
+	Renderer
+	RenderWithOwners(writer io.Writer, canvas domain.Canvas, viewport raster.Viewport) error
+}
+
+// MediaType identifies the format that a canvas is rendered to
+type MediaType string
+
+const (
+	TextMediaType MediaType = "text/plain"
+	SVGMediaType  MediaType = "image/svg+xml"
+	PNGMediaType  MediaType = "image/png"
+	ANSIMediaType MediaType = "text/x-ansi"
+	HTMLMediaType MediaType = "text/html"
+	JSONMediaType MediaType = "application/json"
+)
+
+// formats contains the media type selected by each value of the format query parameter
+var formats = map[string]MediaType{
+	"text": TextMediaType,
+	"svg":  SVGMediaType,
+	"png":  PNGMediaType,
+	"ansi": ANSIMediaType,
+	"html": HTMLMediaType,
+	"json": JSONMediaType,
+}
+
+// contentType returns the value of the Content-Type header for the media type
+func (mt MediaType) contentType() string {
+	if strings.HasPrefix(string(mt), "text/") {
+		return string(mt) + "; charset=utf-8"
+	}
+	return string(mt)
+}
+
+// Renderers contains the renderer for each media type that a canvas can be rendered to. The plain text renderer
+// is used when the client accepts any media type
+type Renderers map[MediaType]Renderer
+
+// negotiate returns the media type with the highest quality in the Accept header that has a renderer. When there
+// is more than one media type with the same quality, the first one in the header is used. Browsers accept HTML along
+// with any other media type, so HTML is only used when it is the only media type in the header
+func (r Renderers) negotiate(accept string) (MediaType, bool) {
+	if strings.TrimSpace(accept) == "" {
+		accept = "*/*"
+	}
+
+	parts := strings.Split(accept, ",")
+	var best MediaType
+	bestQuality := 0.0
+	for _, part := range parts {
+		mediaRange, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+
+		quality := 1.0
+		if value, ok := params["q"]; ok {
+			quality, err = strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+		}
+		if quality <= bestQuality {
+			continue
+		}
+
+		mediaType, ok := r.match(mediaRange)
+		if !ok || (mediaType == HTMLMediaType && len(parts) > 1) {
+			continue
+		}
+		best, bestQuality = mediaType, quality
+	}
+
+	return best, best != ""
+}
+
+// match returns the media type with a renderer that matches the media range, which can contain wildcards. HTML is
+// never matched by a wildcard
+func (r Renderers) match(mediaRange string) (MediaType, bool) {
+	if _, ok := r[MediaType(mediaRange)]; ok {
+		return MediaType(mediaRange), true
+	}
+
+	var prefix string
+	switch {
+	case mediaRange == "*/*":
+	case strings.HasSuffix(mediaRange, "/*"):
+		prefix = strings.TrimSuffix(mediaRange, "*")
+	default:
+		return "", false
+	}
+
+	if _, ok := r[TextMediaType]; ok && strings.HasPrefix(string(TextMediaType), prefix) {
+		return TextMediaType, true
+	}
+
+	mediaTypes := make([]string, 0, len(r))
+	for mediaType := range r {
+		mediaTypes = append(mediaTypes, string(mediaType))
+	}
+	sort.Strings(mediaTypes)
+	for _, mediaType := range mediaTypes {
+		if strings.HasPrefix(mediaType, prefix) && MediaType(mediaType) != HTMLMediaType {
+			return MediaType(mediaType), true
+		}
+	}
+
+	return "", false
 }
@@ -3,12 +3,53 @@ package http
 import (
 	"errors"
 	"fmt"
+	"unicode/utf8"
 
 	"github.com/google/uuid"
+	"github.com/maitesin/sketch/internal/domain"
 )
 
 type CreateCanvasRequest struct {
-	ID uuid.UUID `json:"id"`
+	ID     uuid.UUID `json:"id"`
+	Height *int      `json:"height,omitempty"`
+	Width  *int      `json:"width,omitempty"`
+}
+
+func (ccr CreateCanvasRequest) Validate() error {
+	if ccr.Height != nil && *ccr.Height <= 0 {
+		return errors.New("height must be a positive number")
+	}
+	if ccr.Width != nil && *ccr.Width <= 0 {
+		return errors.New("width must be a positive number")
+	}
+
+	return nil
+}
+
+type ResizeCanvasRequest struct {
+	Height *int   `json:"height,omitempty"`
+	Width  *int   `json:"width,omitempty"`
+	Anchor string `json:"anchor,omitempty"`
+	Clip   bool   `json:"clip,omitempty"`
+}
+
+func (rcr ResizeCanvasRequest) Validate() error {
+	if rcr.Height == nil && rcr.Width == nil {
+		return errors.New("both height and width cannot be empty. Once of them must be present")
+	}
+	if rcr.Height != nil && *rcr.Height <= 0 {
+		return errors.New("height must be a positive number")
+	}
+	if rcr.Width != nil && *rcr.Width <= 0 {
+		return errors.New("width must be a positive number")
+	}
+	switch domain.Anchor(rcr.Anchor) {
+	case "", domain.AnchorTopLeft, domain.AnchorTopRight, domain.AnchorBottomLeft, domain.AnchorBottomRight:
+	default:
+		return fmt.Errorf("unsupported anchor %q", rcr.Anchor)
+	}
+
+	return nil
 }
 
 type RequestType string
@@ -16,6 +57,11 @@ type RequestType string
 const (
 	DrawRectangleRequestType RequestType = "draw_rectangle"
 	AddFillRequestType       RequestType = "add_fill"
+	DrawLineRequestType      RequestType = "draw_line"
+	DrawEllipseRequestType   RequestType = "draw_ellipse"
+	DrawTextRequestType      RequestType = "draw_text"
+	DrawPolylineRequestType  RequestType = "draw_polyline"
+	DrawPolygonRequestType   RequestType = "draw_polygon"
 )
 
 type Point struct {
@@ -23,7 +69,162 @@ type Point struct {
 	Y int `json:"y"`
 }
 
+const (
+	maxPatternHeight = 8
+	maxPatternWidth  = 8
+)
+
+type PatternRequest struct {
+	Tile   []string `json:"tile"`
+	Anchor string   `json:"anchor,omitempty"`
+}
+
+func (pr PatternRequest) Validate() error {
+	if len(pr.Tile) == 0 || len(pr.Tile) > maxPatternHeight {
+		return fmt.Errorf("pattern tile must have between 1 and %d rows", maxPatternHeight)
+	}
+	width := utf8.RuneCountInString(pr.Tile[0])
+	if width == 0 || width > maxPatternWidth {
+		return fmt.Errorf("pattern tile must have between 1 and %d columns", maxPatternWidth)
+	}
+	for _, row := range pr.Tile {
+		if utf8.RuneCountInString(row) != width {
+			return errors.New("all the rows of the pattern tile must have the same number of columns")
+		}
+	}
+	switch domain.PatternAnchor(pr.Anchor) {
+	case "", domain.PatternAnchorCanvas, domain.PatternAnchorShape:
+	default:
+		return fmt.Errorf("unsupported pattern anchor %q", pr.Anchor)
+	}
+
+	return nil
+}
+
+type StyleRequest struct {
+	Foreground string `json:"foreground,omitempty"`
+	Background string `json:"background,omitempty"`
+}
+
+func (sr StyleRequest) Validate() error {
+	for _, colour := range []string{sr.Foreground, sr.Background} {
+		if colour != "" && !validColour(domain.Colour(colour)) {
+			return fmt.Errorf("unsupported colour %q", colour)
+		}
+	}
+
+	return nil
+}
+
+func validColour(colour domain.Colour) bool {
+	for _, valid := range domain.Colours {
+		if colour == valid {
+			return true
+		}
+	}
+	return false
+}
+
 type DrawRectangleRequest struct {
+	ID           uuid.UUID       `json:"id"`
+	Point        Point           `json:"point"`
+	Height       int             `json:"height"`
+	Width        int             `json:"width"`
+	Filler       *string         `json:"filler,omitempty"`
+	Outline      *string         `json:"outline,omitempty"`
+	Pattern      *PatternRequest `json:"pattern,omitempty"`
+	FillerStyle  *StyleRequest   `json:"filler_style,omitempty"`
+	OutlineStyle *StyleRequest   `json:"outline_style,omitempty"`
+}
+
+func (drr DrawRectangleRequest) Validate() error {
+	if drr.Filler == nil && drr.Outline == nil && drr.Pattern == nil {
+		return errors.New("filler, outline, and pattern cannot be empty. Once of them must be present")
+	}
+	if drr.Pattern != nil {
+		if err := drr.Pattern.Validate(); err != nil {
+			return err
+		}
+	}
+	if drr.Filler != nil && len(*drr.Filler) != 1 {
+		return errors.New("filler must be a single character")
+	}
+	if drr.Outline != nil && len(*drr.Outline) != 1 {
+		return errors.New("outline must be a single character")
+	}
+	for _, style := range []*StyleRequest{drr.FillerStyle, drr.OutlineStyle} {
+		if style != nil {
+			if err := style.Validate(); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+type AddFillRequest struct {
+	ID           uuid.UUID       `json:"id"`
+	Point        Point           `json:"point"`
+	Filler       string          `json:"filler,omitempty"`
+	Connectivity int             `json:"connectivity,omitempty"`
+	Mode         string          `json:"mode,omitempty"`
+	Boundary     string          `json:"boundary,omitempty"`
+	Pattern      *PatternRequest `json:"pattern,omitempty"`
+	Style        *StyleRequest   `json:"style,omitempty"`
+}
+
+func (afr AddFillRequest) Validate() error {
+	if afr.Pattern != nil {
+		if err := afr.Pattern.Validate(); err != nil {
+			return err
+		}
+	}
+	if (afr.Pattern == nil || afr.Filler != "") && len(afr.Filler) != 1 {
+		return errors.New("filler must be a single character")
+	}
+	switch domain.Connectivity(afr.Connectivity) {
+	case 0, domain.FourConnected, domain.EightConnected:
+	default:
+		return fmt.Errorf("unsupported connectivity %d", afr.Connectivity)
+	}
+	switch domain.FillMode(afr.Mode) {
+	case "", domain.FloodFillMode:
+		if afr.Boundary != "" {
+			return errors.New("boundary can only be used with the boundary fill mode")
+		}
+	case domain.BoundaryFillMode:
+		if len(afr.Boundary) != 1 {
+			return errors.New("boundary must be a single character")
+		}
+	default:
+		return fmt.Errorf("unsupported fill mode %q", afr.Mode)
+	}
+	if afr.Style != nil {
+		if err := afr.Style.Validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+type DrawLineRequest struct {
+	ID      uuid.UUID `json:"id"`
+	From    Point     `json:"from"`
+	To      Point     `json:"to"`
+	Outline string    `json:"outline"`
+}
+
+func (dlr DrawLineRequest) Validate() error {
+	if len(dlr.Outline) != 1 {
+		return errors.New("outline must be a single character")
+	}
+
+	return nil
+}
+
+type DrawEllipseRequest struct {
 	ID      uuid.UUID `json:"id"`
 	Point   Point     `json:"point"`
 	Height  int       `json:"height"`
@@ -32,30 +233,89 @@ type DrawRectangleRequest struct {
 	Outline *string   `json:"outline,omitempty"`
 }
 
-func (drr DrawRectangleRequest) Validate() error {
-	if drr.Filler == nil && drr.Outline == nil {
+func (der DrawEllipseRequest) Validate() error {
+	if der.Height <= 0 || der.Width <= 0 {
+		return errors.New("height and width must be positive")
+	}
+	if der.Filler == nil && der.Outline == nil {
 		return errors.New("both filler and outline cannot be empty. Once of them must be present")
 	}
-	if drr.Filler != nil && len(*drr.Filler) != 1 {
+	if der.Filler != nil && len(*der.Filler) != 1 {
 		return errors.New("filler must be a single character")
 	}
-	if drr.Outline != nil && len(*drr.Outline) != 1 {
+	if der.Outline != nil && len(*der.Outline) != 1 {
 		return errors.New("outline must be a single character")
 	}
 
 	return nil
 }
 
-type AddFillRequest struct {
-	ID     uuid.UUID `json:"id"`
-	Point  Point     `json:"point"`
-	Filler string    `json:"filler"`
+type DrawTextRequest struct {
+	ID        uuid.UUID `json:"id"`
+	Point     Point     `json:"point"`
+	Text      string    `json:"text"`
+	WrapWidth int       `json:"wrap_width,omitempty"`
+	Alignment string    `json:"alignment,omitempty"`
 }
 
-func (afr AddFillRequest) Validate() error {
-	if len(afr.Filler) != 1 {
+func (dtr DrawTextRequest) Validate() error {
+	if dtr.Text == "" {
+		return errors.New("text cannot be empty")
+	}
+	if dtr.WrapWidth < 0 {
+		return errors.New("wrap width cannot be negative")
+	}
+	switch domain.Alignment(dtr.Alignment) {
+	case "", domain.AlignLeft, domain.AlignCenter, domain.AlignRight:
+	default:
+		return fmt.Errorf("unsupported alignment %q", dtr.Alignment)
+	}
+
+	return nil
+}
+
+const (
+	minPolylinePoints = 2
+	minPolygonPoints  = 3
+)
+
+type DrawPolylineRequest struct {
+	ID      uuid.UUID `json:"id"`
+	Points  []Point   `json:"points"`
+	Outline string    `json:"outline"`
+}
+
+func (dpr DrawPolylineRequest) Validate() error {
+	if len(dpr.Points) < minPolylinePoints {
+		return fmt.Errorf("a polyline needs at least %d points", minPolylinePoints)
+	}
+	if len(dpr.Outline) != 1 {
+		return errors.New("outline must be a single character")
+	}
+
+	return nil
+}
+
+type DrawPolygonRequest struct {
+	ID      uuid.UUID `json:"id"`
+	Points  []Point   `json:"points"`
+	Filler  *string   `json:"filler,omitempty"`
+	Outline *string   `json:"outline,omitempty"`
+}
+
+func (dpr DrawPolygonRequest) Validate() error {
+	if len(dpr.Points) < minPolygonPoints {
+		return fmt.Errorf("a polygon needs at least %d points", minPolygonPoints)
+	}
+	if dpr.Filler == nil && dpr.Outline == nil {
+		return errors.New("both filler and outline cannot be empty. Once of them must be present")
+	}
+	if dpr.Filler != nil && len(*dpr.Filler) != 1 {
 		return errors.New("filler must be a single character")
 	}
+	if dpr.Outline != nil && len(*dpr.Outline) != 1 {
+		return errors.New("outline must be a single character")
+	}
 
 	return nil
 }
@@ -64,6 +324,11 @@ type TaskRequest struct {
 	Type      RequestType           `json:"type"`
 	Rectangle *DrawRectangleRequest `json:"rectangle,omitempty"`
 	Fill      *AddFillRequest       `json:"fill,omitempty"`
+	Line      *DrawLineRequest      `json:"line,omitempty"`
+	Ellipse   *DrawEllipseRequest   `json:"ellipse,omitempty"`
+	Text      *DrawTextRequest      `json:"text,omitempty"`
+	Polyline  *DrawPolylineRequest  `json:"polyline,omitempty"`
+	Polygon   *DrawPolygonRequest   `json:"polygon,omitempty"`
 }
 
 func (tr TaskRequest) Validate() error {
@@ -78,6 +343,31 @@ func (tr TaskRequest) Validate() error {
 			return fmt.Errorf("fill attribute must be present in task %q", AddFillRequestType)
 		}
 		return tr.Fill.Validate()
+	case DrawLineRequestType:
+		if tr.Line == nil {
+			return fmt.Errorf("line attribute must be present in task %q", DrawLineRequestType)
+		}
+		return tr.Line.Validate()
+	case DrawEllipseRequestType:
+		if tr.Ellipse == nil {
+			return fmt.Errorf("ellipse attribute must be present in task %q", DrawEllipseRequestType)
+		}
+		return tr.Ellipse.Validate()
+	case DrawTextRequestType:
+		if tr.Text == nil {
+			return fmt.Errorf("text attribute must be present in task %q", DrawTextRequestType)
+		}
+		return tr.Text.Validate()
+	case DrawPolylineRequestType:
+		if tr.Polyline == nil {
+			return fmt.Errorf("polyline attribute must be present in task %q", DrawPolylineRequestType)
+		}
+		return tr.Polyline.Validate()
+	case DrawPolygonRequestType:
+		if tr.Polygon == nil {
+			return fmt.Errorf("polygon attribute must be present in task %q", DrawPolygonRequestType)
+		}
+		return tr.Polygon.Validate()
 	default:
 		return fmt.Errorf("unsupported operation %q", tr.Type)
 	}
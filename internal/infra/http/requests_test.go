@@ -109,6 +109,256 @@ func invalidAddFillRequest() httpx.TaskRequest {
 	}
 }
 
+func addFillRequestWithMode(connectivity int, mode, boundary string) httpx.TaskRequest {
+	request := validAddFillRequest()
+	request.Fill.Connectivity = connectivity
+	request.Fill.Mode = mode
+	request.Fill.Boundary = boundary
+	return request
+}
+
+func drawRectangleRequestWithPattern(tile []string, anchor string) httpx.TaskRequest {
+	request := invalidDrawRectangleRequestMissingBothFillerAndOutline()
+	request.Rectangle.Pattern = &httpx.PatternRequest{Tile: tile, Anchor: anchor}
+	return request
+}
+
+func addFillRequestWithPattern(tile []string, anchor string) httpx.TaskRequest {
+	request := addFillRequestWithoutFiller()
+	request.Fill.Pattern = &httpx.PatternRequest{Tile: tile, Anchor: anchor}
+	return request
+}
+
+func drawRectangleRequestWithStyles(filler, outline httpx.StyleRequest) httpx.TaskRequest {
+	request := validDrawRectangleRequest()
+	request.Rectangle.FillerStyle = &filler
+	request.Rectangle.OutlineStyle = &outline
+	return request
+}
+
+func addFillRequestWithStyle(style httpx.StyleRequest) httpx.TaskRequest {
+	request := validAddFillRequest()
+	request.Fill.Style = &style
+	return request
+}
+
+func addFillRequestWithoutFiller() httpx.TaskRequest {
+	request := validAddFillRequest()
+	request.Fill.Filler = ""
+	return request
+}
+
+func validDrawLineRequest() httpx.TaskRequest {
+	return httpx.TaskRequest{
+		Type: httpx.DrawLineRequestType,
+		Line: &httpx.DrawLineRequest{
+			ID: uuid.New(),
+			From: httpx.Point{
+				X: 0,
+				Y: 0,
+			},
+			To: httpx.Point{
+				X: 5,
+				Y: 5,
+			},
+			Outline: "*",
+		},
+	}
+}
+
+func invalidDrawLineRequest() httpx.TaskRequest {
+	return httpx.TaskRequest{
+		Type: httpx.DrawLineRequestType,
+		Line: &httpx.DrawLineRequest{
+			ID: uuid.New(),
+			From: httpx.Point{
+				X: 0,
+				Y: 0,
+			},
+			To: httpx.Point{
+				X: 5,
+				Y: 5,
+			},
+			Outline: "wololo",
+		},
+	}
+}
+
+func validDrawEllipseRequest() httpx.TaskRequest {
+	filler := "."
+	outline := "O"
+	return httpx.TaskRequest{
+		Type: httpx.DrawEllipseRequestType,
+		Ellipse: &httpx.DrawEllipseRequest{
+			ID: uuid.New(),
+			Point: httpx.Point{
+				X: 10,
+				Y: 10,
+			},
+			Height:  5,
+			Width:   7,
+			Filler:  &filler,
+			Outline: &outline,
+		},
+	}
+}
+
+func invalidDrawEllipseRequestMissingBothFillerAndOutline() httpx.TaskRequest {
+	return httpx.TaskRequest{
+		Type: httpx.DrawEllipseRequestType,
+		Ellipse: &httpx.DrawEllipseRequest{
+			ID: uuid.New(),
+			Point: httpx.Point{
+				X: 10,
+				Y: 10,
+			},
+			Height: 5,
+			Width:  7,
+		},
+	}
+}
+
+func invalidDrawEllipseRequestWithOutlineTooLong() httpx.TaskRequest {
+	outline := "wololo"
+	return httpx.TaskRequest{
+		Type: httpx.DrawEllipseRequestType,
+		Ellipse: &httpx.DrawEllipseRequest{
+			ID: uuid.New(),
+			Point: httpx.Point{
+				X: 10,
+				Y: 10,
+			},
+			Height:  5,
+			Width:   7,
+			Outline: &outline,
+		},
+	}
+}
+
+func invalidDrawEllipseRequestWithSize(height, width int) httpx.TaskRequest {
+	request := validDrawEllipseRequest()
+	request.Ellipse.Height = height
+	request.Ellipse.Width = width
+	return request
+}
+
+func validDrawTextRequest() httpx.TaskRequest {
+	return httpx.TaskRequest{
+		Type: httpx.DrawTextRequestType,
+		Text: &httpx.DrawTextRequest{
+			ID: uuid.New(),
+			Point: httpx.Point{
+				X: 1,
+				Y: 1,
+			},
+			Text:      "Hello world",
+			WrapWidth: 5,
+			Alignment: "center",
+		},
+	}
+}
+
+func invalidDrawTextRequestWithEmptyText() httpx.TaskRequest {
+	return httpx.TaskRequest{
+		Type: httpx.DrawTextRequestType,
+		Text: &httpx.DrawTextRequest{
+			ID: uuid.New(),
+			Point: httpx.Point{
+				X: 1,
+				Y: 1,
+			},
+		},
+	}
+}
+
+func invalidDrawTextRequestWithNegativeWrapWidth() httpx.TaskRequest {
+	return httpx.TaskRequest{
+		Type: httpx.DrawTextRequestType,
+		Text: &httpx.DrawTextRequest{
+			ID: uuid.New(),
+			Point: httpx.Point{
+				X: 1,
+				Y: 1,
+			},
+			Text:      "Hello world",
+			WrapWidth: -1,
+		},
+	}
+}
+
+func invalidDrawTextRequestWithUnknownAlignment() httpx.TaskRequest {
+	return httpx.TaskRequest{
+		Type: httpx.DrawTextRequestType,
+		Text: &httpx.DrawTextRequest{
+			ID: uuid.New(),
+			Point: httpx.Point{
+				X: 1,
+				Y: 1,
+			},
+			Text:      "Hello world",
+			Alignment: "justify",
+		},
+	}
+}
+
+func validDrawPolylineRequest() httpx.TaskRequest {
+	return httpx.TaskRequest{
+		Type: httpx.DrawPolylineRequestType,
+		Polyline: &httpx.DrawPolylineRequest{
+			ID:      uuid.New(),
+			Points:  []httpx.Point{{X: 0, Y: 0}, {X: 5, Y: 5}},
+			Outline: "*",
+		},
+	}
+}
+
+func invalidDrawPolylineRequestWithASinglePoint() httpx.TaskRequest {
+	return httpx.TaskRequest{
+		Type: httpx.DrawPolylineRequestType,
+		Polyline: &httpx.DrawPolylineRequest{
+			ID:      uuid.New(),
+			Points:  []httpx.Point{{X: 0, Y: 0}},
+			Outline: "*",
+		},
+	}
+}
+
+func validDrawPolygonRequest() httpx.TaskRequest {
+	filler := "."
+	outline := "#"
+	return httpx.TaskRequest{
+		Type: httpx.DrawPolygonRequestType,
+		Polygon: &httpx.DrawPolygonRequest{
+			ID:      uuid.New(),
+			Points:  []httpx.Point{{X: 0, Y: 0}, {X: 8, Y: 0}, {X: 4, Y: 4}},
+			Filler:  &filler,
+			Outline: &outline,
+		},
+	}
+}
+
+func invalidDrawPolygonRequestWithTwoPoints() httpx.TaskRequest {
+	outline := "#"
+	return httpx.TaskRequest{
+		Type: httpx.DrawPolygonRequestType,
+		Polygon: &httpx.DrawPolygonRequest{
+			ID:      uuid.New(),
+			Points:  []httpx.Point{{X: 0, Y: 0}, {X: 8, Y: 0}},
+			Outline: &outline,
+		},
+	}
+}
+
+func invalidDrawPolygonRequestMissingBothFillerAndOutline() httpx.TaskRequest {
+	return httpx.TaskRequest{
+		Type: httpx.DrawPolygonRequestType,
+		Polygon: &httpx.DrawPolygonRequest{
+			ID:     uuid.New(),
+			Points: []httpx.Point{{X: 0, Y: 0}, {X: 8, Y: 0}, {X: 4, Y: 4}},
+		},
+	}
+}
+
 func TestTaskRequest_Validate(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -127,6 +377,75 @@ func TestTaskRequest_Validate(t *testing.T) {
                    then no error is returned`,
 			taskRequest: validAddFillRequest(),
 		},
+		{
+			name: `Given a valid add fill request with an 8-connected flood fill,
+                   when the validate method is called,
+                   then no error is returned`,
+			taskRequest: addFillRequestWithMode(8, "flood", ""),
+		},
+		{
+			name: `Given a valid add fill request with a 4-connected boundary fill,
+                   when the validate method is called,
+                   then no error is returned`,
+			taskRequest: addFillRequestWithMode(4, "boundary", "#"),
+		},
+		{
+			name: `Given a valid add fill request with a pattern instead of a filler,
+                   when the validate method is called,
+                   then no error is returned`,
+			taskRequest: addFillRequestWithPattern([]string{"xo", "ox"}, "canvas"),
+		},
+		{
+			name: `Given a valid draw rectangle request with a pattern instead of a filler and an outline,
+                   when the validate method is called,
+                   then no error is returned`,
+			taskRequest: drawRectangleRequestWithPattern([]string{"/\\"}, "shape"),
+		},
+		{
+			name: `Given a valid draw rectangle request with styles for the filler and the outline,
+                   when the validate method is called,
+                   then no error is returned`,
+			taskRequest: drawRectangleRequestWithStyles(
+				httpx.StyleRequest{Foreground: "yellow", Background: "blue"},
+				httpx.StyleRequest{Foreground: "red"},
+			),
+		},
+		{
+			name: `Given a valid add fill request with a style,
+                   when the validate method is called,
+                   then no error is returned`,
+			taskRequest: addFillRequestWithStyle(httpx.StyleRequest{Background: "cyan"}),
+		},
+		{
+			name: `Given a valid draw line request,
+                   when the validate method is called,
+                   then no error is returned`,
+			taskRequest: validDrawLineRequest(),
+		},
+		{
+			name: `Given a valid draw ellipse request,
+                   when the validate method is called,
+                   then no error is returned`,
+			taskRequest: validDrawEllipseRequest(),
+		},
+		{
+			name: `Given a valid draw text request,
+                   when the validate method is called,
+                   then no error is returned`,
+			taskRequest: validDrawTextRequest(),
+		},
+		{
+			name: `Given a valid draw polyline request,
+                   when the validate method is called,
+                   then no error is returned`,
+			taskRequest: validDrawPolylineRequest(),
+		},
+		{
+			name: `Given a valid draw polygon request,
+                   when the validate method is called,
+                   then no error is returned`,
+			taskRequest: validDrawPolygonRequest(),
+		},
 		{
 			name: `Given an invalid draw rectangle request because it has both filler and outline missing,
                    when the validate method is called,
@@ -155,6 +474,170 @@ func TestTaskRequest_Validate(t *testing.T) {
 			taskRequest: invalidAddFillRequest(),
 			expectedErr: errors.New(""),
 		},
+		{
+			name: `Given an invalid add fill request because it has an unsupported connectivity,
+                   when the validate method is called,
+                   then an error is returned`,
+			taskRequest: addFillRequestWithMode(6, "", ""),
+			expectedErr: errors.New(""),
+		},
+		{
+			name: `Given an invalid add fill request because it has an unsupported mode,
+                   when the validate method is called,
+                   then an error is returned`,
+			taskRequest: addFillRequestWithMode(0, "spray", ""),
+			expectedErr: errors.New(""),
+		},
+		{
+			name: `Given an invalid add fill request because it is a boundary fill without a boundary,
+                   when the validate method is called,
+                   then an error is returned`,
+			taskRequest: addFillRequestWithMode(0, "boundary", ""),
+			expectedErr: errors.New(""),
+		},
+		{
+			name: `Given an invalid add fill request because it is a flood fill with a boundary,
+                   when the validate method is called,
+                   then an error is returned`,
+			taskRequest: addFillRequestWithMode(0, "flood", "#"),
+			expectedErr: errors.New(""),
+		},
+		{
+			name: `Given an invalid add fill request because it has neither a filler nor a pattern,
+                   when the validate method is called,
+                   then an error is returned`,
+			taskRequest: addFillRequestWithoutFiller(),
+			expectedErr: errors.New(""),
+		},
+		{
+			name: `Given an invalid add fill request because its pattern has no rows,
+                   when the validate method is called,
+                   then an error is returned`,
+			taskRequest: addFillRequestWithPattern(nil, ""),
+			expectedErr: errors.New(""),
+		},
+		{
+			name: `Given an invalid add fill request because its pattern has too many rows,
+                   when the validate method is called,
+                   then an error is returned`,
+			taskRequest: addFillRequestWithPattern([]string{"x", "o", "x", "o", "x", "o", "x", "o", "x"}, ""),
+			expectedErr: errors.New(""),
+		},
+		{
+			name: `Given an invalid draw rectangle request because its pattern has too many columns,
+                   when the validate method is called,
+                   then an error is returned`,
+			taskRequest: drawRectangleRequestWithPattern([]string{"xoxoxoxox"}, ""),
+			expectedErr: errors.New(""),
+		},
+		{
+			name: `Given an invalid draw rectangle request because the rows of its pattern have different lengths,
+                   when the validate method is called,
+                   then an error is returned`,
+			taskRequest: drawRectangleRequestWithPattern([]string{"xo", "o"}, ""),
+			expectedErr: errors.New(""),
+		},
+		{
+			name: `Given an invalid draw rectangle request because its pattern has an unsupported anchor,
+                   when the validate method is called,
+                   then an error is returned`,
+			taskRequest: drawRectangleRequestWithPattern([]string{"xo"}, "window"),
+			expectedErr: errors.New(""),
+		},
+		{
+			name: `Given an invalid draw rectangle request because its outline style has an unsupported colour,
+                   when the validate method is called,
+                   then an error is returned`,
+			taskRequest: drawRectangleRequestWithStyles(
+				httpx.StyleRequest{Foreground: "yellow"},
+				httpx.StyleRequest{Foreground: "#ff0000"},
+			),
+			expectedErr: errors.New(""),
+		},
+		{
+			name: `Given an invalid add fill request because its style has an unsupported colour,
+                   when the validate method is called,
+                   then an error is returned`,
+			taskRequest: addFillRequestWithStyle(httpx.StyleRequest{Background: "purple"}),
+			expectedErr: errors.New(""),
+		},
+		{
+			name: `Given an invalid draw line request because it has the outline with too many runes,
+                   when the validate method is called,
+                   then an error is returned`,
+			taskRequest: invalidDrawLineRequest(),
+			expectedErr: errors.New(""),
+		},
+		{
+			name: `Given an invalid draw ellipse request because it has both filler and outline missing,
+                   when the validate method is called,
+                   then an error is returned`,
+			taskRequest: invalidDrawEllipseRequestMissingBothFillerAndOutline(),
+			expectedErr: errors.New(""),
+		},
+		{
+			name: `Given an invalid draw ellipse request because it has the outline with too many runes,
+                   when the validate method is called,
+                   then an error is returned`,
+			taskRequest: invalidDrawEllipseRequestWithOutlineTooLong(),
+			expectedErr: errors.New(""),
+		},
+		{
+			name: `Given an invalid draw ellipse request because it has a negative height,
+                   when the validate method is called,
+                   then an error is returned`,
+			taskRequest: invalidDrawEllipseRequestWithSize(-5, 7),
+			expectedErr: errors.New(""),
+		},
+		{
+			name: `Given an invalid draw ellipse request because it has a zero width,
+                   when the validate method is called,
+                   then an error is returned`,
+			taskRequest: invalidDrawEllipseRequestWithSize(5, 0),
+			expectedErr: errors.New(""),
+		},
+		{
+			name: `Given an invalid draw text request because it has an empty text,
+                   when the validate method is called,
+                   then an error is returned`,
+			taskRequest: invalidDrawTextRequestWithEmptyText(),
+			expectedErr: errors.New(""),
+		},
+		{
+			name: `Given an invalid draw text request because it has a negative wrap width,
+                   when the validate method is called,
+                   then an error is returned`,
+			taskRequest: invalidDrawTextRequestWithNegativeWrapWidth(),
+			expectedErr: errors.New(""),
+		},
+		{
+			name: `Given an invalid draw text request because it has an unknown alignment,
+                   when the validate method is called,
+                   then an error is returned`,
+			taskRequest: invalidDrawTextRequestWithUnknownAlignment(),
+			expectedErr: errors.New(""),
+		},
+		{
+			name: `Given an invalid draw polyline request because it has a single point,
+                   when the validate method is called,
+                   then an error is returned`,
+			taskRequest: invalidDrawPolylineRequestWithASinglePoint(),
+			expectedErr: errors.New(""),
+		},
+		{
+			name: `Given an invalid draw polygon request because it has only two points,
+                   when the validate method is called,
+                   then an error is returned`,
+			taskRequest: invalidDrawPolygonRequestWithTwoPoints(),
+			expectedErr: errors.New(""),
+		},
+		{
+			name: `Given an invalid draw polygon request because it has both filler and outline missing,
+                   when the validate method is called,
+                   then an error is returned`,
+			taskRequest: invalidDrawPolygonRequestMissingBothFillerAndOutline(),
+			expectedErr: errors.New(""),
+		},
 		{
 			name: `Given an invalid task request because the task type is invalid,
                    when the validate method is called,
@@ -180,6 +663,51 @@ func TestTaskRequest_Validate(t *testing.T) {
 			},
 			expectedErr: errors.New(""),
 		},
+		{
+			name: `Given an invalid task request because the task type is draw line, but the line attribute is missing,
+                   when the validate method is called,
+                   then an error is returned`,
+			taskRequest: httpx.TaskRequest{
+				Type: httpx.DrawLineRequestType,
+			},
+			expectedErr: errors.New(""),
+		},
+		{
+			name: `Given an invalid task request because the task type is draw ellipse, but the ellipse attribute is missing,
+                   when the validate method is called,
+                   then an error is returned`,
+			taskRequest: httpx.TaskRequest{
+				Type: httpx.DrawEllipseRequestType,
+			},
+			expectedErr: errors.New(""),
+		},
+		{
+			name: `Given an invalid task request because the task type is draw text, but the text attribute is missing,
+                   when the validate method is called,
+                   then an error is returned`,
+			taskRequest: httpx.TaskRequest{
+				Type: httpx.DrawTextRequestType,
+			},
+			expectedErr: errors.New(""),
+		},
+		{
+			name: `Given an invalid task request because the task type is draw polyline, but the polyline attribute is missing,
+                   when the validate method is called,
+                   then an error is returned`,
+			taskRequest: httpx.TaskRequest{
+				Type: httpx.DrawPolylineRequestType,
+			},
+			expectedErr: errors.New(""),
+		},
+		{
+			name: `Given an invalid task request because the task type is draw polygon, but the polygon attribute is missing,
+                   when the validate method is called,
+                   then an error is returned`,
+			taskRequest: httpx.TaskRequest{
+				Type: httpx.DrawPolygonRequestType,
+			},
+			expectedErr: errors.New(""),
+		},
 	}
 	for _, tt := range tests {
 		tt := tt
@@ -195,3 +723,119 @@ func TestTaskRequest_Validate(t *testing.T) {
 		})
 	}
 }
+
+func TestCreateCanvasRequest_Validate(t *testing.T) {
+	validSize := 10
+	invalidSize := 0
+
+	tests := []struct {
+		name                string
+		createCanvasRequest httpx.CreateCanvasRequest
+		expectedErr         error
+	}{
+		{
+			name: `Given a create canvas request without size,
+                   when the validate method is called,
+                   then no error is returned`,
+			createCanvasRequest: httpx.CreateCanvasRequest{ID: uuid.New()},
+		},
+		{
+			name: `Given a create canvas request with a positive height and width,
+                   when the validate method is called,
+                   then no error is returned`,
+			createCanvasRequest: httpx.CreateCanvasRequest{ID: uuid.New(), Height: &validSize, Width: &validSize},
+		},
+		{
+			name: `Given a create canvas request with a non-positive height,
+                   when the validate method is called,
+                   then an error is returned`,
+			createCanvasRequest: httpx.CreateCanvasRequest{ID: uuid.New(), Height: &invalidSize},
+			expectedErr:         errors.New(""),
+		},
+		{
+			name: `Given a create canvas request with a non-positive width,
+                   when the validate method is called,
+                   then an error is returned`,
+			createCanvasRequest: httpx.CreateCanvasRequest{ID: uuid.New(), Width: &invalidSize},
+			expectedErr:         errors.New(""),
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tt.createCanvasRequest.Validate()
+			if tt.expectedErr != nil {
+				require.ErrorAs(t, err, &tt.expectedErr)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestResizeCanvasRequest_Validate(t *testing.T) {
+	validSize := 10
+	invalidSize := -10
+
+	tests := []struct {
+		name                string
+		resizeCanvasRequest httpx.ResizeCanvasRequest
+		expectedErr         error
+	}{
+		{
+			name: `Given a resize canvas request with height, width, and anchor,
+                   when the validate method is called,
+                   then no error is returned`,
+			resizeCanvasRequest: httpx.ResizeCanvasRequest{Height: &validSize, Width: &validSize, Anchor: "top_right"},
+		},
+		{
+			name: `Given a resize canvas request with only the width,
+                   when the validate method is called,
+                   then no error is returned`,
+			resizeCanvasRequest: httpx.ResizeCanvasRequest{Width: &validSize, Clip: true},
+		},
+		{
+			name: `Given a resize canvas request without height and width,
+                   when the validate method is called,
+                   then an error is returned`,
+			resizeCanvasRequest: httpx.ResizeCanvasRequest{Anchor: "top_left"},
+			expectedErr:         errors.New(""),
+		},
+		{
+			name: `Given a resize canvas request with a negative height,
+                   when the validate method is called,
+                   then an error is returned`,
+			resizeCanvasRequest: httpx.ResizeCanvasRequest{Height: &invalidSize},
+			expectedErr:         errors.New(""),
+		},
+		{
+			name: `Given a resize canvas request with a negative width,
+                   when the validate method is called,
+                   then an error is returned`,
+			resizeCanvasRequest: httpx.ResizeCanvasRequest{Width: &invalidSize},
+			expectedErr:         errors.New(""),
+		},
+		{
+			name: `Given a resize canvas request with an unknown anchor,
+                   when the validate method is called,
+                   then an error is returned`,
+			resizeCanvasRequest: httpx.ResizeCanvasRequest{Height: &validSize, Anchor: "middle"},
+			expectedErr:         errors.New(""),
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tt.resizeCanvasRequest.Validate()
+			if tt.expectedErr != nil {
+				require.ErrorAs(t, err, &tt.expectedErr)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
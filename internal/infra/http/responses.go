@@ -0,0 +1,199 @@
+package http
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/maitesin/sketch/internal/app"
+	"github.com/maitesin/sketch/internal/domain"
+)
+
+// taskTypes maps the types of the tasks in the requests to the types of the tasks in the application
+var taskTypes = map[RequestType]app.TaskType{
+	DrawRectangleRequestType: app.RectangleTaskType,
+	AddFillRequestType:       app.FillTaskType,
+	DrawLineRequestType:      app.LineTaskType,
+	DrawEllipseRequestType:   app.EllipseTaskType,
+	DrawTextRequestType:      app.TextTaskType,
+	DrawPolylineRequestType:  app.PolylineTaskType,
+	DrawPolygonRequestType:   app.PolygonTaskType,
+}
+
+// TaskResponse has the same shape as the request used to add the task, so it can be used to edit it
+type TaskResponse struct {
+	TaskRequest
+	ID        uuid.UUID `json:"id"`
+	Sequence  int       `json:"sequence"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type ListTasksResponse struct {
+	Tasks  []TaskResponse `json:"tasks"`
+	Total  int            `json:"total"`
+	Offset int            `json:"offset"`
+	Limit  int            `json:"limit"`
+}
+
+type RenderCacheStatsResponse struct {
+	Hits    uint64 `json:"hits"`
+	Misses  uint64 `json:"misses"`
+	Entries int    `json:"entries"`
+	Size    int    `json:"size"`
+}
+
+func taskResponseFromDomain(task domain.Task) (TaskResponse, error) {
+	switch task := task.(type) {
+	case domain.DrawRectangle:
+		filler, outline := string(task.Filler()), string(task.Outline())
+		return TaskResponse{
+			TaskRequest: TaskRequest{
+				Type: DrawRectangleRequestType,
+				Rectangle: &DrawRectangleRequest{
+					ID:           task.ID(),
+					Point:        pointFromDomain(task.Point()),
+					Height:       task.Height(),
+					Width:        task.Width(),
+					Filler:       &filler,
+					Outline:      &outline,
+					Pattern:      patternFromDomain(task.Pattern()),
+					FillerStyle:  styleFromDomain(task.FillerStyle()),
+					OutlineStyle: styleFromDomain(task.OutlineStyle()),
+				},
+			},
+			ID:        task.ID(),
+			Sequence:  task.Sequence(),
+			CreatedAt: task.CreatedAt(),
+		}, nil
+	case domain.Fill:
+		var boundary string
+		if task.Mode() == domain.BoundaryFillMode {
+			boundary = string(task.Boundary())
+		}
+		return TaskResponse{
+			TaskRequest: TaskRequest{
+				Type: AddFillRequestType,
+				Fill: &AddFillRequest{
+					ID:           task.ID(),
+					Point:        pointFromDomain(task.Point()),
+					Filler:       string(task.Filler()),
+					Connectivity: int(task.Connectivity()),
+					Mode:         string(task.Mode()),
+					Boundary:     boundary,
+					Pattern:      patternFromDomain(task.Pattern()),
+					Style:        styleFromDomain(task.Style()),
+				},
+			},
+			ID:        task.ID(),
+			Sequence:  task.Sequence(),
+			CreatedAt: task.CreatedAt(),
+		}, nil
+	case domain.DrawLine:
+		return TaskResponse{
+			TaskRequest: TaskRequest{
+				Type: DrawLineRequestType,
+				Line: &DrawLineRequest{
+					ID:      task.ID(),
+					From:    pointFromDomain(task.From()),
+					To:      pointFromDomain(task.To()),
+					Outline: string(task.Outline()),
+				},
+			},
+			ID:        task.ID(),
+			Sequence:  task.Sequence(),
+			CreatedAt: task.CreatedAt(),
+		}, nil
+	case domain.DrawEllipse:
+		filler, outline := string(task.Filler()), string(task.Outline())
+		return TaskResponse{
+			TaskRequest: TaskRequest{
+				Type: DrawEllipseRequestType,
+				Ellipse: &DrawEllipseRequest{
+					ID:      task.ID(),
+					Point:   pointFromDomain(task.Point()),
+					Height:  task.Height(),
+					Width:   task.Width(),
+					Filler:  &filler,
+					Outline: &outline,
+				},
+			},
+			ID:        task.ID(),
+			Sequence:  task.Sequence(),
+			CreatedAt: task.CreatedAt(),
+		}, nil
+	case domain.DrawText:
+		return TaskResponse{
+			TaskRequest: TaskRequest{
+				Type: DrawTextRequestType,
+				Text: &DrawTextRequest{
+					ID:        task.ID(),
+					Point:     pointFromDomain(task.Point()),
+					Text:      task.Text(),
+					WrapWidth: task.WrapWidth(),
+					Alignment: string(task.Alignment()),
+				},
+			},
+			ID:        task.ID(),
+			Sequence:  task.Sequence(),
+			CreatedAt: task.CreatedAt(),
+		}, nil
+	case domain.DrawPolyline:
+		return TaskResponse{
+			TaskRequest: TaskRequest{
+				Type: DrawPolylineRequestType,
+				Polyline: &DrawPolylineRequest{
+					ID:      task.ID(),
+					Points:  pointsFromDomain(task.Points()),
+					Outline: string(task.Outline()),
+				},
+			},
+			ID:        task.ID(),
+			Sequence:  task.Sequence(),
+			CreatedAt: task.CreatedAt(),
+		}, nil
+	case domain.DrawPolygon:
+		filler, outline := string(task.Filler()), string(task.Outline())
+		return TaskResponse{
+			TaskRequest: TaskRequest{
+				Type: DrawPolygonRequestType,
+				Polygon: &DrawPolygonRequest{
+					ID:      task.ID(),
+					Points:  pointsFromDomain(task.Points()),
+					Filler:  &filler,
+					Outline: &outline,
+				},
+			},
+			ID:        task.ID(),
+			Sequence:  task.Sequence(),
+			CreatedAt: task.CreatedAt(),
+		}, nil
+	}
+
+	return TaskResponse{}, fmt.Errorf("failed to convert domain task to response: %#v", task)
+}
+
+func patternFromDomain(pattern domain.Pattern) *PatternRequest {
+	if pattern.IsEmpty() {
+		return nil
+	}
+	return &PatternRequest{Tile: pattern.Tile(), Anchor: string(pattern.Anchor())}
+}
+
+func styleFromDomain(style domain.Style) *StyleRequest {
+	if style.IsDefault() {
+		return nil
+	}
+	return &StyleRequest{Foreground: string(style.Foreground()), Background: string(style.Background())}
+}
+
+func pointFromDomain(point domain.Point) Point {
+	return Point{X: point.X(), Y: point.Y()}
+}
+
+func pointsFromDomain(points []domain.Point) []Point {
+	converted := make([]Point, len(points))
+	for i := range points {
+		converted[i] = pointFromDomain(points[i])
+	}
+	return converted
+}
@@ -10,18 +10,37 @@ import (
 	log "github.com/sirupsen/logrus" //nolint: depguard
 )
 
-func DefaultRouter(ctx context.Context, cfg app.Config, repository app.CanvasRepository, renderer Renderer) httpx.Handler {
+func DefaultRouter(ctx context.Context, cfg app.Config, repository app.CanvasRepository, renderers Renderers, cache app.RenderCache, snapshotter app.Snapshotter) httpx.Handler {
 	logger := LoggerFromContext(ctx)
 
+	invalidate := func(handler app.CommandHandler) app.CommandHandler {
+		return app.NewInvalidateRenderCacheHandler(handler, cache)
+	}
+	refresh := func(handler app.CommandHandler) app.CommandHandler {
+		return NewRefreshSnapshotHandler(handler, repository, snapshotter)
+	}
+
 	router := chi.NewRouter()
 	router.Use(middleware.Logger("router", logger))
 
-	router.Post("/canvas", loggerMiddleware(logger, CreateCanvasHandler(app.NewCreateCanvasHandler(repository, cfg.Height, cfg.Width))))
-	router.Get("/canvas/{canvasID}", loggerMiddleware(logger, RenderCanvasHandler(app.NewRetrieveCanvasHandler(repository), renderer)))
-	router.Post("/canvas/{canvasID}", loggerMiddleware(logger, AddTaskHandler(
-		app.NewDrawRectangleHandler(repository),
-		app.NewAddFillHandler(repository),
-	)))
+	router.Post("/canvas", loggerMiddleware(logger, CreateCanvasHandler(invalidate(app.NewCreateCanvasHandler(repository, cfg)))))
+	router.Get("/canvas/{canvasID}", loggerMiddleware(logger, RenderCanvasHandler(app.NewRetrieveCanvasHandler(repository), renderers, cache, snapshotter)))
+	router.Post("/canvas/{canvasID}", loggerMiddleware(logger, AddTaskHandler(map[RequestType]app.CommandHandler{
+		DrawRectangleRequestType: invalidate(refresh(app.NewDrawRectangleHandler(repository))),
+		AddFillRequestType:       invalidate(refresh(app.NewAddFillHandler(repository))),
+		DrawLineRequestType:      invalidate(refresh(app.NewDrawLineHandler(repository))),
+		DrawEllipseRequestType:   invalidate(refresh(app.NewDrawEllipseHandler(repository))),
+		DrawTextRequestType:      invalidate(refresh(app.NewDrawTextHandler(repository))),
+		DrawPolylineRequestType:  invalidate(refresh(app.NewDrawPolylineHandler(repository))),
+		DrawPolygonRequestType:   invalidate(refresh(app.NewDrawPolygonHandler(repository))),
+	})))
+	router.Patch("/canvas/{canvasID}", loggerMiddleware(logger, ResizeCanvasHandler(invalidate(refresh(app.NewResizeCanvasHandler(repository, cfg))))))
+	router.Post("/canvas/{canvasID}/undo", loggerMiddleware(logger, UndoHandler(invalidate(refresh(app.NewUndoHandler(repository))))))
+	router.Post("/canvas/{canvasID}/redo", loggerMiddleware(logger, RedoHandler(invalidate(refresh(app.NewRedoHandler(repository))))))
+	router.Get("/canvas/{canvasID}/tasks", loggerMiddleware(logger, ListTasksHandler(app.NewListTasksHandler(repository))))
+	router.Put("/canvas/{canvasID}/tasks/{taskID}", loggerMiddleware(logger, UpdateTaskHandler(invalidate(refresh(app.NewUpdateTaskHandler(repository))))))
+	router.Delete("/canvas/{canvasID}/tasks/{taskID}", loggerMiddleware(logger, RemoveTaskHandler(invalidate(refresh(app.NewRemoveTaskHandler(repository))))))
+	router.Get("/render-cache/stats", loggerMiddleware(logger, RenderCacheStatsHandler(cache)))
 
 	return router
 }
@@ -15,7 +15,9 @@ import (
 	"github.com/maitesin/sketch/config"
 	"github.com/maitesin/sketch/internal/app"
 	"github.com/maitesin/sketch/internal/domain"
+	"github.com/maitesin/sketch/internal/infra/cache"
 	httpx "github.com/maitesin/sketch/internal/infra/http"
+	"github.com/maitesin/sketch/internal/infra/raster"
 	log "github.com/sirupsen/logrus" //nolint: depguard
 	"github.com/stretchr/testify/require"
 )
@@ -40,7 +42,7 @@ var noopRepositoryMutator = func(repository app.CanvasRepository) app.CanvasRepo
 
 func validRenderer() httpx.Renderer {
 	return &RendererMock{
-		RenderFunc: func(io.Writer, domain.Canvas) error {
+		RenderFunc: func(io.Writer, domain.Canvas, raster.Viewport) error {
 			return nil
 		},
 	}
@@ -73,6 +75,22 @@ func TestDefaultRouter_CreateCanvas(t *testing.T) {
 			bodyReader:         strings.NewReader(""),
 			expectedStatusCode: http.StatusBadRequest,
 		},
+		{
+			name: `Given a working canvas repository and a valid body request with a custom size,
+                   when the endpoint to create a new canvas is called,
+                   then a canvas is successfully created and a status code created (201) is returned`,
+			repositoryMutator:  noopRepositoryMutator,
+			bodyReader:         strings.NewReader(fmt.Sprintf(`{"id":%q,"height":5,"width":80}`, uuid.New().String())),
+			expectedStatusCode: http.StatusCreated,
+		},
+		{
+			name: `Given a working canvas repository and a valid body request with a size out of the allowed range,
+                   when the endpoint to create a new canvas is called,
+                   then a status code unprocessable entity (422) is returned`,
+			repositoryMutator:  noopRepositoryMutator,
+			bodyReader:         strings.NewReader(fmt.Sprintf(`{"id":%q,"height":5000}`, uuid.New().String())),
+			expectedStatusCode: http.StatusUnprocessableEntity,
+		},
 		{
 			name: `Given a non-working canvas repository and a valid body request,
                    when the endpoint to create a new canvas is called,
@@ -102,7 +120,7 @@ func TestDefaultRouter_CreateCanvas(t *testing.T) {
 			logger := log.New()
 			ctx := httpx.ContextWithLogger(context.Background(), logger)
 
-			server := httptest.NewServer(httpx.DefaultRouter(ctx, cfg.Canvas, repository, &RendererMock{}))
+			server := httptest.NewServer(httpx.DefaultRouter(ctx, cfg.Canvas, repository, httpx.Renderers{httpx.TextMediaType: &RendererMock{}}, cache.NewLRU(renderCacheCapacity), app.Snapshotter{}))
 			defer server.Close()
 
 			client := server.Client()
@@ -139,6 +157,46 @@ func TestDefaultRouter_AddTaskToCanvas(t *testing.T) {
 			bodyReader:         validAddFillerBodyReader(t),
 			expectedStatusCode: http.StatusOK,
 		},
+		{
+			name: `Given a working canvas repository and a valid body request,
+                   when the endpoint to add a draw line task to a canvas is called,
+                   then a status code OK (200) is returned`,
+			repositoryMutator:  noopRepositoryMutator,
+			bodyReader:         validDrawLineBodyReader(t),
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name: `Given a working canvas repository and a valid body request,
+                   when the endpoint to add a draw ellipse task to a canvas is called,
+                   then a status code OK (200) is returned`,
+			repositoryMutator:  noopRepositoryMutator,
+			bodyReader:         validDrawEllipseBodyReader(t),
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name: `Given a working canvas repository and a valid body request,
+                   when the endpoint to add a draw text task to a canvas is called,
+                   then a status code OK (200) is returned`,
+			repositoryMutator:  noopRepositoryMutator,
+			bodyReader:         validDrawTextBodyReader(t),
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name: `Given a working canvas repository and a valid body request,
+                   when the endpoint to add a draw polyline task to a canvas is called,
+                   then a status code OK (200) is returned`,
+			repositoryMutator:  noopRepositoryMutator,
+			bodyReader:         validDrawPolylineBodyReader(t),
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name: `Given a working canvas repository and a valid body request,
+                   when the endpoint to add a draw polygon task to a canvas is called,
+                   then a status code OK (200) is returned`,
+			repositoryMutator:  noopRepositoryMutator,
+			bodyReader:         validDrawPolygonBodyReader(t),
+			expectedStatusCode: http.StatusOK,
+		},
 		{
 			name: `Given a working canvas repository and an invalid body request,
                    when the endpoint to add a fill task to a canvas is called,
@@ -191,7 +249,7 @@ func TestDefaultRouter_AddTaskToCanvas(t *testing.T) {
 			logger := log.New()
 			ctx := httpx.ContextWithLogger(context.Background(), logger)
 
-			server := httptest.NewServer(httpx.DefaultRouter(ctx, cfg.Canvas, repository, &RendererMock{}))
+			server := httptest.NewServer(httpx.DefaultRouter(ctx, cfg.Canvas, repository, httpx.Renderers{httpx.TextMediaType: &RendererMock{}}, cache.NewLRU(renderCacheCapacity), app.Snapshotter{}))
 			defer server.Close()
 
 			client := server.Client()
@@ -205,6 +263,388 @@ func TestDefaultRouter_AddTaskToCanvas(t *testing.T) {
 	}
 }
 
+func TestDefaultRouter_ResizeCanvas(t *testing.T) {
+	tests := []struct {
+		name               string
+		repositoryMutator  repositoryMutator
+		bodyReader         io.Reader
+		expectedStatusCode int
+	}{
+		{
+			name: `Given a working canvas repository and a valid body request,
+                   when the endpoint to resize a canvas is called,
+                   then a status code OK (200) is returned`,
+			repositoryMutator:  noopRepositoryMutator,
+			bodyReader:         strings.NewReader(`{"height":40,"width":60,"anchor":"bottom_left"}`),
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name: `Given a working canvas repository and an invalid body request,
+                   when the endpoint to resize a canvas is called,
+                   then a status code bad request (400) is returned`,
+			repositoryMutator:  noopRepositoryMutator,
+			bodyReader:         strings.NewReader(`{}`),
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name: `Given a working canvas repository with a canvas containing a rectangle and a body request that crops it,
+                   when the endpoint to resize a canvas is called,
+                   then a status code unprocessable entity (422) is returned`,
+			repositoryMutator: func(app.CanvasRepository) app.CanvasRepository {
+				repository := &CanvasRepositoryMock{
+					FindByIDFunc: func(context.Context, uuid.UUID) (domain.Canvas, error) {
+						rectangle := domain.NewDrawRectangle(uuid.New(), domain.NewPoint(10, 10), 5, 5, 'X', 'O', time.Now().UTC())
+						return domain.NewCanvas(uuid.New(), 30, 30, []domain.Task{rectangle}, time.Now().UTC()), nil
+					},
+				}
+				return repository
+			},
+			bodyReader:         strings.NewReader(`{"height":5,"width":5}`),
+			expectedStatusCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name: `Given a canvas repository that cannot find the canvas and a valid body request,
+                   when the endpoint to resize a canvas is called,
+                   then a status code not found (404) is returned`,
+			repositoryMutator: func(app.CanvasRepository) app.CanvasRepository {
+				repository := &CanvasRepositoryMock{
+					FindByIDFunc: func(context.Context, uuid.UUID) (domain.Canvas, error) {
+						return domain.Canvas{}, app.CanvasNotFound{}
+					},
+				}
+				return repository
+			},
+			bodyReader:         strings.NewReader(`{"height":40}`),
+			expectedStatusCode: http.StatusNotFound,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repository := tt.repositoryMutator(validCanvasRepository())
+
+			cfg, err := config.New()
+			require.NoError(t, err)
+
+			logger := log.New()
+			ctx := httpx.ContextWithLogger(context.Background(), logger)
+
+			server := httptest.NewServer(httpx.DefaultRouter(ctx, cfg.Canvas, repository, httpx.Renderers{httpx.TextMediaType: &RendererMock{}}, cache.NewLRU(renderCacheCapacity), app.Snapshotter{}))
+			defer server.Close()
+
+			req, err := http.NewRequestWithContext(ctx, http.MethodPatch, fmt.Sprintf("%s/canvas/%s", server.URL, uuid.New()), tt.bodyReader)
+			require.NoError(t, err)
+
+			client := server.Client()
+			resp, err := client.Do(req)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+
+			require.Equal(t, tt.expectedStatusCode, resp.StatusCode)
+		})
+	}
+}
+
+func TestDefaultRouter_UpdateTask(t *testing.T) {
+	taskID := uuid.New()
+	canvasWithRectangle := func(app.CanvasRepository) app.CanvasRepository {
+		repository := &CanvasRepositoryMock{
+			FindByIDFunc: func(context.Context, uuid.UUID) (domain.Canvas, error) {
+				rectangle := domain.NewDrawRectangle(taskID, domain.NewPoint(0, 0), 5, 5, 'X', 'O', time.Now().UTC())
+				return domain.NewCanvas(uuid.New(), 30, 30, []domain.Task{rectangle}, time.Now().UTC()), nil
+			},
+			UpdateFunc: func(context.Context, domain.Canvas) error {
+				return nil
+			},
+		}
+		return repository
+	}
+
+	tests := []struct {
+		name               string
+		repositoryMutator  repositoryMutator
+		taskID             uuid.UUID
+		bodyReader         io.Reader
+		expectedStatusCode int
+	}{
+		{
+			name: `Given a working canvas repository with a canvas containing the rectangle and a valid body request,
+                   when the endpoint to update a task is called,
+                   then a status code OK (200) is returned`,
+			repositoryMutator:  canvasWithRectangle,
+			taskID:             taskID,
+			bodyReader:         validDrawRectangleBodyReader(t),
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name: `Given a working canvas repository with a canvas containing the rectangle and a body request with a fill,
+                   when the endpoint to update a task is called,
+                   then a status code unprocessable entity (422) is returned`,
+			repositoryMutator:  canvasWithRectangle,
+			taskID:             taskID,
+			bodyReader:         validAddFillerBodyReader(t),
+			expectedStatusCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name: `Given a working canvas repository with a canvas that does not contain the task and a valid body request,
+                   when the endpoint to update a task is called,
+                   then a status code not found (404) is returned`,
+			repositoryMutator:  canvasWithRectangle,
+			taskID:             uuid.New(),
+			bodyReader:         validDrawRectangleBodyReader(t),
+			expectedStatusCode: http.StatusNotFound,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repository := tt.repositoryMutator(validCanvasRepository())
+
+			cfg, err := config.New()
+			require.NoError(t, err)
+
+			logger := log.New()
+			ctx := httpx.ContextWithLogger(context.Background(), logger)
+
+			server := httptest.NewServer(httpx.DefaultRouter(ctx, cfg.Canvas, repository, httpx.Renderers{httpx.TextMediaType: &RendererMock{}}, cache.NewLRU(renderCacheCapacity), app.Snapshotter{}))
+			defer server.Close()
+
+			req, err := http.NewRequestWithContext(ctx, http.MethodPut, fmt.Sprintf("%s/canvas/%s/tasks/%s", server.URL, uuid.New(), tt.taskID), tt.bodyReader)
+			require.NoError(t, err)
+
+			client := server.Client()
+			resp, err := client.Do(req)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+
+			require.Equal(t, tt.expectedStatusCode, resp.StatusCode)
+		})
+	}
+}
+
+func TestDefaultRouter_RemoveTask(t *testing.T) {
+	taskID := uuid.New()
+
+	tests := []struct {
+		name               string
+		repositoryMutator  repositoryMutator
+		taskID             uuid.UUID
+		expectedStatusCode int
+	}{
+		{
+			name: `Given a working canvas repository with a canvas containing the task,
+                   when the endpoint to remove a task is called,
+                   then a status code OK (200) is returned`,
+			repositoryMutator: func(app.CanvasRepository) app.CanvasRepository {
+				repository := &CanvasRepositoryMock{
+					FindByIDFunc: func(context.Context, uuid.UUID) (domain.Canvas, error) {
+						fill := domain.NewFill(taskID, domain.NewPoint(1, 1), '-', time.Now().UTC())
+						return domain.NewCanvas(uuid.New(), 30, 30, []domain.Task{fill}, time.Now().UTC()), nil
+					},
+					UpdateFunc: func(context.Context, domain.Canvas) error {
+						return nil
+					},
+				}
+				return repository
+			},
+			taskID:             taskID,
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name: `Given a working canvas repository with a canvas that does not contain the task,
+                   when the endpoint to remove a task is called,
+                   then a status code not found (404) is returned`,
+			repositoryMutator:  noopRepositoryMutator,
+			taskID:             uuid.New(),
+			expectedStatusCode: http.StatusNotFound,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repository := tt.repositoryMutator(validCanvasRepository())
+
+			cfg, err := config.New()
+			require.NoError(t, err)
+
+			logger := log.New()
+			ctx := httpx.ContextWithLogger(context.Background(), logger)
+
+			server := httptest.NewServer(httpx.DefaultRouter(ctx, cfg.Canvas, repository, httpx.Renderers{httpx.TextMediaType: &RendererMock{}}, cache.NewLRU(renderCacheCapacity), app.Snapshotter{}))
+			defer server.Close()
+
+			req, err := http.NewRequestWithContext(ctx, http.MethodDelete, fmt.Sprintf("%s/canvas/%s/tasks/%s", server.URL, uuid.New(), tt.taskID), nil)
+			require.NoError(t, err)
+
+			client := server.Client()
+			resp, err := client.Do(req)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+
+			require.Equal(t, tt.expectedStatusCode, resp.StatusCode)
+		})
+	}
+}
+
+func TestDefaultRouter_UndoRedo(t *testing.T) {
+	tests := []struct {
+		name               string
+		repositoryMutator  repositoryMutator
+		action             string
+		expectedStatusCode int
+	}{
+		{
+			name: `Given a working canvas repository with a canvas containing a task,
+                   when the endpoint to undo is called,
+                   then a status code OK (200) is returned`,
+			repositoryMutator: func(app.CanvasRepository) app.CanvasRepository {
+				repository := &CanvasRepositoryMock{
+					FindByIDFunc: func(context.Context, uuid.UUID) (domain.Canvas, error) {
+						fill := domain.NewFill(uuid.New(), domain.NewPoint(1, 1), '-', time.Now().UTC())
+						return domain.NewCanvas(uuid.New(), 30, 30, []domain.Task{fill}, time.Now().UTC()), nil
+					},
+					UpdateFunc: func(context.Context, domain.Canvas) error {
+						return nil
+					},
+				}
+				return repository
+			},
+			action:             "undo",
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name: `Given a working canvas repository with an empty canvas,
+                   when the endpoint to undo is called,
+                   then a status code conflict (409) is returned`,
+			repositoryMutator:  noopRepositoryMutator,
+			action:             "undo",
+			expectedStatusCode: http.StatusConflict,
+		},
+		{
+			name: `Given a working canvas repository with a canvas containing an undone task,
+                   when the endpoint to redo is called,
+                   then a status code OK (200) is returned`,
+			repositoryMutator: func(app.CanvasRepository) app.CanvasRepository {
+				repository := &CanvasRepositoryMock{
+					FindByIDFunc: func(context.Context, uuid.UUID) (domain.Canvas, error) {
+						fill := domain.NewFill(uuid.New(), domain.NewPoint(1, 1), '-', time.Now().UTC())
+						return domain.NewCanvasWithHistory(uuid.New(), 30, 30, []domain.Task{fill}, 0, time.Now().UTC()), nil
+					},
+					UpdateFunc: func(context.Context, domain.Canvas) error {
+						return nil
+					},
+				}
+				return repository
+			},
+			action:             "redo",
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name: `Given a working canvas repository with a canvas without undone tasks,
+                   when the endpoint to redo is called,
+                   then a status code conflict (409) is returned`,
+			repositoryMutator:  noopRepositoryMutator,
+			action:             "redo",
+			expectedStatusCode: http.StatusConflict,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repository := tt.repositoryMutator(validCanvasRepository())
+
+			cfg, err := config.New()
+			require.NoError(t, err)
+
+			logger := log.New()
+			ctx := httpx.ContextWithLogger(context.Background(), logger)
+
+			server := httptest.NewServer(httpx.DefaultRouter(ctx, cfg.Canvas, repository, httpx.Renderers{httpx.TextMediaType: &RendererMock{}}, cache.NewLRU(renderCacheCapacity), app.Snapshotter{}))
+			defer server.Close()
+
+			client := server.Client()
+			//nolint: noctx
+			resp, err := client.Post(fmt.Sprintf("%s/canvas/%s/%s", server.URL, uuid.New(), tt.action), "application/json", nil)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+
+			require.Equal(t, tt.expectedStatusCode, resp.StatusCode)
+		})
+	}
+}
+
+func TestDefaultRouter_ListTasks(t *testing.T) {
+	tests := []struct {
+		name               string
+		repositoryMutator  repositoryMutator
+		rawQuery           string
+		expectedStatusCode int
+	}{
+		{
+			name: `Given a working canvas repository,
+                   when the endpoint to list the tasks of a canvas is called,
+                   then a status code OK (200) is returned`,
+			repositoryMutator:  noopRepositoryMutator,
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name: `Given a working canvas repository,
+                   when the endpoint to list the tasks of a canvas is called with an invalid type,
+                   then a status code bad request (400) is returned`,
+			repositoryMutator:  noopRepositoryMutator,
+			rawQuery:           "type=draw_star",
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name: `Given a working canvas repository, but the canvasID does not exists,
+                   when the endpoint to list the tasks of a canvas is called,
+                   then a status code not found (404) is returned`,
+			repositoryMutator: func(app.CanvasRepository) app.CanvasRepository {
+				repository := &CanvasRepositoryMock{
+					FindByIDFunc: func(context.Context, uuid.UUID) (domain.Canvas, error) {
+						return domain.Canvas{}, app.CanvasNotFound{}
+					},
+				}
+				return repository
+			},
+			expectedStatusCode: http.StatusNotFound,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repository := tt.repositoryMutator(validCanvasRepository())
+
+			cfg, err := config.New()
+			require.NoError(t, err)
+
+			logger := log.New()
+			ctx := httpx.ContextWithLogger(context.Background(), logger)
+
+			server := httptest.NewServer(httpx.DefaultRouter(ctx, cfg.Canvas, repository, httpx.Renderers{httpx.TextMediaType: validRenderer()}, cache.NewLRU(renderCacheCapacity), app.Snapshotter{}))
+			defer server.Close()
+
+			client := server.Client()
+			//nolint: noctx
+			resp, err := client.Get(fmt.Sprintf("%s/canvas/%s/tasks?%s", server.URL, uuid.New(), tt.rawQuery))
+			require.NoError(t, err)
+			defer resp.Body.Close()
+
+			require.Equal(t, tt.expectedStatusCode, resp.StatusCode)
+		})
+	}
+}
+
 func TestDefaultRouter_RenderCanvas(t *testing.T) {
 	tests := []struct {
 		name               string
@@ -257,7 +697,7 @@ func TestDefaultRouter_RenderCanvas(t *testing.T) {
 			repositoryMutator: noopRepositoryMutator,
 			rendererMutator: func(httpx.Renderer) httpx.Renderer {
 				renderer := &RendererMock{
-					RenderFunc: func(io.Writer, domain.Canvas) error {
+					RenderFunc: func(io.Writer, domain.Canvas, raster.Viewport) error {
 						return errors.New("unable to render")
 					},
 				}
@@ -280,7 +720,7 @@ func TestDefaultRouter_RenderCanvas(t *testing.T) {
 			logger := log.New()
 			ctx := httpx.ContextWithLogger(context.Background(), logger)
 
-			server := httptest.NewServer(httpx.DefaultRouter(ctx, cfg.Canvas, repository, renderer))
+			server := httptest.NewServer(httpx.DefaultRouter(ctx, cfg.Canvas, repository, httpx.Renderers{httpx.TextMediaType: renderer}, cache.NewLRU(renderCacheCapacity), app.Snapshotter{}))
 			defer server.Close()
 
 			client := server.Client()
@@ -293,3 +733,80 @@ func TestDefaultRouter_RenderCanvas(t *testing.T) {
 		})
 	}
 }
+
+func TestDefaultRouter_RenderCanvas_ContentNegotiation(t *testing.T) {
+	tests := []struct {
+		name                string
+		accept              string
+		query               string
+		expectedContentType string
+		expectedBody        string
+	}{
+		{
+			name: `Given a request with the Accept header sent by browsers,
+                   when the endpoint to render a canvas is called,
+                   then the canvas is rendered as plain text`,
+			accept:              "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8",
+			expectedContentType: "text/plain; charset=utf-8",
+			expectedBody:        "text",
+		},
+		{
+			name: `Given a request with the Accept header sent by browsers and the html format,
+                   when the endpoint to render a canvas is called,
+                   then the canvas is rendered as HTML`,
+			accept:              "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8",
+			query:               "?format=html",
+			expectedContentType: "text/html; charset=utf-8",
+			expectedBody:        "html",
+		},
+		{
+			name: `Given a request that only accepts HTML,
+                   when the endpoint to render a canvas is called,
+                   then the canvas is rendered as HTML`,
+			accept:              "text/html",
+			expectedContentType: "text/html; charset=utf-8",
+			expectedBody:        "html",
+		},
+		{
+			name: `Given a request that accepts any text media type,
+                   when the endpoint to render a canvas is called,
+                   then the canvas is rendered as plain text`,
+			accept:              "text/*",
+			expectedContentType: "text/plain; charset=utf-8",
+			expectedBody:        "text",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg, err := config.New()
+			require.NoError(t, err)
+
+			logger := log.New()
+			ctx := httpx.ContextWithLogger(context.Background(), logger)
+
+			renderers := httpx.Renderers{
+				httpx.TextMediaType: writerRenderer("text"),
+				httpx.HTMLMediaType: writerRenderer("html"),
+			}
+			server := httptest.NewServer(httpx.DefaultRouter(ctx, cfg.Canvas, validCanvasRepository(), renderers, cache.NewLRU(renderCacheCapacity), app.Snapshotter{}))
+			defer server.Close()
+
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/canvas/%s%s", server.URL, uuid.New(), tt.query), nil)
+			require.NoError(t, err)
+			req.Header.Set("Accept", tt.accept)
+
+			resp, err := server.Client().Do(req)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+			require.Equal(t, tt.expectedContentType, resp.Header.Get("Content-Type"))
+			body, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+			require.Equal(t, tt.expectedBody, string(body))
+		})
+	}
+}
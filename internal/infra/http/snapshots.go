@@ -0,0 +1,83 @@
+package http
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/maitesin/sketch/internal/app"
+)
+
+// RefreshSnapshotHandler is a decorator for a command handler that refreshes the snapshot of the canvas once the
+// command has been handled. Snapshots are only taken here, so rendering a canvas never writes them: adding tasks takes
+// a new snapshot every interval tasks, and removing, editing, or undoing tasks replaces the outdated one, so the next
+// rendering does not draw all the tasks again
+type RefreshSnapshotHandler struct {
+	handler     app.CommandHandler
+	repository  app.CanvasRepository
+	snapshotter app.Snapshotter
+}
+
+// NewRefreshSnapshotHandler is a constructor
+func NewRefreshSnapshotHandler(handler app.CommandHandler, repository app.CanvasRepository, snapshotter app.Snapshotter) RefreshSnapshotHandler {
+	return RefreshSnapshotHandler{
+		handler:     handler,
+		repository:  repository,
+		snapshotter: snapshotter,
+	}
+}
+
+// Handle runs the command and refreshes the snapshot of its canvas. The command has been performed by then, so failing
+// to refresh the snapshot is only logged
+func (r RefreshSnapshotHandler) Handle(ctx context.Context, cmd app.Command) error {
+	if err := r.handler.Handle(ctx, cmd); err != nil {
+		return err
+	}
+
+	canvasID, ok := snapshotCanvasIDOf(cmd)
+	if !ok || !r.snapshotter.Enabled() {
+		return nil
+	}
+
+	logger := LoggerFromContext(ctx).WithField("canvas_id", canvasID)
+	canvas, err := r.repository.FindByID(ctx, canvasID)
+	if err != nil {
+		logger.Error(err)
+		return nil
+	}
+	if err := r.snapshotter.Refresh(ctx, canvas); err != nil {
+		logger.Error(err)
+	}
+	return nil
+}
+
+// snapshotCanvasIDOf returns the ID of the canvas targeted by the commands that change its tasks
+func snapshotCanvasIDOf(cmd app.Command) (uuid.UUID, bool) {
+	switch cmd := cmd.(type) {
+	case app.DrawRectangleCmd:
+		return cmd.CanvasID, true
+	case app.AddFillCmd:
+		return cmd.CanvasID, true
+	case app.DrawLineCmd:
+		return cmd.CanvasID, true
+	case app.DrawEllipseCmd:
+		return cmd.CanvasID, true
+	case app.DrawTextCmd:
+		return cmd.CanvasID, true
+	case app.DrawPolylineCmd:
+		return cmd.CanvasID, true
+	case app.DrawPolygonCmd:
+		return cmd.CanvasID, true
+	case app.ResizeCanvasCmd:
+		return cmd.CanvasID, true
+	case app.RemoveTaskCmd:
+		return cmd.CanvasID, true
+	case app.UpdateTaskCmd:
+		return cmd.CanvasID, true
+	case app.UndoCmd:
+		return cmd.CanvasID, true
+	case app.RedoCmd:
+		return cmd.CanvasID, true
+	default:
+		return uuid.UUID{}, false
+	}
+}
@@ -0,0 +1,187 @@
+package http_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/google/uuid"
+	"github.com/maitesin/sketch/internal/app"
+	"github.com/maitesin/sketch/internal/domain"
+	"github.com/maitesin/sketch/internal/infra/cache"
+	httpx "github.com/maitesin/sketch/internal/infra/http"
+	"github.com/maitesin/sketch/internal/infra/raster"
+	log "github.com/sirupsen/logrus" //nolint: depguard
+	"github.com/stretchr/testify/require"
+)
+
+// snapshotCanvas returns a canvas with five lines, so it has two snapshots when they are taken every two tasks
+func snapshotCanvas() domain.Canvas {
+	tasks := make([]domain.Task, 5)
+	for i := range tasks {
+		tasks[i] = domain.NewDrawLine(uuid.New(), domain.NewPoint(i, 0), domain.NewPoint(i, 3), rune('a'+i), time.Now().UTC())
+	}
+	return domain.NewCanvas(uuid.New(), 4, 6, tasks, time.Now().UTC())
+}
+
+func TestRefreshSnapshotHandler(t *testing.T) {
+	canvas := snapshotCanvas()
+
+	tests := []struct {
+		name               string
+		cmd                app.Command
+		interval           int
+		handleErr          error
+		expectedErr        error
+		expectedSavedTasks int
+	}{
+		{
+			name: `Given a refresh snapshot handler,
+                   when an undo command is handled,
+                   then a snapshot of the canvas after undoing the task is stored`,
+			cmd:                app.UndoCmd{CanvasID: canvas.ID()},
+			interval:           2,
+			expectedSavedTasks: 4,
+		},
+		{
+			name: `Given a refresh snapshot handler,
+                   when a command that adds a task is handled,
+                   then a snapshot of the canvas after adding the task is stored`,
+			cmd:                app.DrawLineCmd{CanvasID: canvas.ID()},
+			interval:           2,
+			expectedSavedTasks: 4,
+		},
+		{
+			name: `Given a refresh snapshot handler,
+                   when a command that does not change the tasks of a canvas is handled,
+                   then no snapshot is stored`,
+			cmd:      app.CreateCanvasCmd{ID: canvas.ID()},
+			interval: 2,
+		},
+		{
+			name: `Given a refresh snapshot handler with snapshots disabled,
+                   when an undo command is handled,
+                   then no snapshot is stored`,
+			cmd: app.UndoCmd{CanvasID: canvas.ID()},
+		},
+		{
+			name: `Given a refresh snapshot handler with a non-working command handler,
+                   when an undo command is handled,
+                   then the error is returned and no snapshot is stored`,
+			cmd:         app.UndoCmd{CanvasID: canvas.ID()},
+			interval:    2,
+			handleErr:   errors.New("something went wrong"),
+			expectedErr: errors.New("something went wrong"),
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			commandHandler := &CommandHandlerMock{
+				HandleFunc: func(context.Context, app.Command) error {
+					return tt.handleErr
+				},
+			}
+			canvasRepository := &CanvasRepositoryMock{
+				FindByIDFunc: func(context.Context, uuid.UUID) (domain.Canvas, error) {
+					return canvas, nil
+				},
+			}
+			snapshotRepository := &SnapshotRepositoryMock{
+				FindByCanvasIDFunc: func(context.Context, uuid.UUID) (raster.Snapshot, error) {
+					return raster.Snapshot{}, raster.ErrSnapshotNotFound
+				},
+				SaveFunc: func(context.Context, uuid.UUID, raster.Snapshot) error {
+					return nil
+				},
+			}
+
+			ctx := httpx.ContextWithLogger(context.Background(), log.New())
+			handler := httpx.NewRefreshSnapshotHandler(commandHandler, canvasRepository, app.NewSnapshotter(snapshotRepository, tt.interval))
+			err := handler.Handle(ctx, tt.cmd)
+			if tt.expectedErr != nil {
+				require.EqualError(t, err, tt.expectedErr.Error())
+			} else {
+				require.NoError(t, err)
+			}
+			if tt.expectedSavedTasks != 0 {
+				require.Len(t, snapshotRepository.SaveCalls(), 1)
+				require.Equal(t, tt.expectedSavedTasks, snapshotRepository.SaveCalls()[0].Snapshot.Tasks())
+			} else {
+				require.Empty(t, canvasRepository.FindByIDCalls())
+				require.Empty(t, snapshotRepository.SaveCalls())
+			}
+		})
+	}
+}
+
+func TestRenderCanvasHandler_Snapshots(t *testing.T) {
+	canvas := snapshotCanvas()
+
+	tests := []struct {
+		name    string
+		findErr error
+	}{
+		{
+			name: `Given a canvas without snapshot,
+                   when the canvas is rendered,
+                   then it is rendered from all its tasks without storing a snapshot`,
+			findErr: raster.ErrSnapshotNotFound,
+		},
+		{
+			name: `Given a non-working repository to find snapshots,
+                   when the canvas is rendered,
+                   then it is rendered from all its tasks and the request succeeds`,
+			findErr: errors.New("something went wrong"),
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			queryHandler := &QueryHandlerMock{
+				HandleFunc: func(context.Context, app.Query) (app.QueryResponse, error) {
+					return canvas, nil
+				},
+			}
+			renderer := &RendererMock{
+				RenderFunc: func(io.Writer, domain.Canvas, raster.Viewport) error {
+					return nil
+				},
+			}
+			// rendering never stores nor deletes snapshots, so calling those functions panics
+			repository := &SnapshotRepositoryMock{
+				FindByCanvasIDFunc: func(context.Context, uuid.UUID) (raster.Snapshot, error) {
+					return raster.Snapshot{}, tt.findErr
+				},
+			}
+
+			chiCtx := chi.NewRouteContext()
+			chiCtx.URLParams.Add("canvasID", canvas.ID().String())
+			ctx := context.WithValue(context.Background(), chi.RouteCtxKey, chiCtx)
+			ctx = httpx.ContextWithLogger(ctx, log.New())
+
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("/canvas/%s", canvas.ID()), nil)
+			require.NoError(t, err)
+
+			res := httptest.NewRecorder()
+			httpx.RenderCanvasHandler(queryHandler, httpx.Renderers{httpx.TextMediaType: renderer}, cache.NewLRU(renderCacheCapacity),
+				app.NewSnapshotter(repository, 2))(res, req)
+			result := res.Result()
+			defer result.Body.Close()
+
+			require.Equal(t, http.StatusOK, result.StatusCode)
+			require.Len(t, renderer.RenderCalls(), 1)
+			require.Equal(t, canvas, renderer.RenderCalls()[0].Canvas)
+		})
+	}
+}
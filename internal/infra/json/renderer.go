@@ -0,0 +1,81 @@
+package json
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/google/uuid"
+	"github.com/maitesin/sketch/internal/domain"
+	"github.com/maitesin/sketch/internal/infra/raster"
+)
+
+// unowned is the owner of the cells that have not been drawn by any task
+const unowned = -1
+
+// Grid is the rendering of the viewport of a canvas starting in the cell in X and Y, with a string per row
+type Grid struct {
+	X      int      `json:"x"`
+	Y      int      `json:"y"`
+	Height int      `json:"height"`
+	Width  int      `json:"width"`
+	Rows   []string `json:"rows"`
+}
+
+// OwnedGrid is the rendering of a canvas along with the task that drew each cell last. Each owner is the index of
+// the ID of the task in the tasks, or -1 when no task drew the cell
+type OwnedGrid struct {
+	Grid
+	Tasks  []uuid.UUID `json:"tasks"`
+	Owners [][]int     `json:"owners"`
+}
+
+// Renderer draws the canvas as a JSON object with the rows of the grid of the canvas, so the clients do not need to
+// parse the plain text rendering
+type Renderer struct{}
+
+func (Renderer) Render(writer io.Writer, c domain.Canvas, viewport raster.Viewport) error {
+	grid, err := raster.Rasterize(c, viewport)
+	if err != nil {
+		return err
+	}
+
+	return json.NewEncoder(writer).Encode(gridFromRaster(viewport, grid))
+}
+
+// RenderWithOwners draws the canvas as a JSON object that also contains the task that drew each cell last, so the
+// clients can select the shapes of the canvas
+func (Renderer) RenderWithOwners(writer io.Writer, c domain.Canvas, viewport raster.Viewport) error {
+	grid, owners, err := raster.RasterizeWithOwners(c, viewport)
+	if err != nil {
+		return err
+	}
+
+	response := OwnedGrid{
+		Grid:   gridFromRaster(viewport, grid),
+		Tasks:  []uuid.UUID{},
+		Owners: make([][]int, len(owners)),
+	}
+	indexes := map[uuid.UUID]int{uuid.Nil: unowned}
+	for y, row := range owners {
+		response.Owners[y] = make([]int, len(row))
+		for x, owner := range row {
+			index, ok := indexes[owner]
+			if !ok {
+				index = len(response.Tasks)
+				indexes[owner] = index
+				response.Tasks = append(response.Tasks, owner)
+			}
+			response.Owners[y][x] = index
+		}
+	}
+
+	return json.NewEncoder(writer).Encode(response)
+}
+
+func gridFromRaster(viewport raster.Viewport, grid raster.Grid) Grid {
+	rows := make([]string, len(grid))
+	for i := range grid {
+		rows[i] = string(grid[i])
+	}
+	return Grid{X: viewport.X, Y: viewport.Y, Height: viewport.Height, Width: viewport.Width, Rows: rows}
+}
@@ -0,0 +1,153 @@
+package json_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/maitesin/sketch/internal/domain"
+	jsonx "github.com/maitesin/sketch/internal/infra/json"
+	"github.com/maitesin/sketch/internal/infra/raster"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	rectangleID = uuid.MustParse("2fb51cca-c789-4938-9d66-948c16a4d42f")
+	fillID      = uuid.MustParse("2c2daf0d-97b1-4274-a9ca-06d3c7b167cf")
+)
+
+func canvasFixture1() domain.Canvas {
+	return domain.NewCanvas(
+		uuid.New(),
+		3,
+		5,
+		[]domain.Task{
+			domain.NewDrawRectangle(rectangleID, domain.NewPoint(0, 0), 2, 3, '.', '#', time.Now().UTC()),
+			domain.NewFill(fillID, domain.NewPoint(4, 2), '~', time.Now().UTC()),
+		},
+		time.Now().UTC(),
+	)
+}
+
+func invalidCanvas() domain.Canvas {
+	return domain.NewCanvas(
+		uuid.New(),
+		10,
+		10,
+		[]domain.Task{"this will fail"},
+		time.Now().UTC(),
+	)
+}
+
+func TestRenderer(t *testing.T) {
+	tests := []struct {
+		name           string
+		canvas         domain.Canvas
+		expectedOutput string
+		expectedErr    error
+	}{
+		{
+			name: `Given a canvas with a rectangle and a fill,
+                   when the render method is called from the JSON renderer,
+                   then the size and the rows of the canvas are returned`,
+			canvas:         canvasFixture1(),
+			expectedOutput: `{"x":0,"y":0,"height":3,"width":5,"rows":["###~~","###~~","~~~~~"]}`,
+		},
+		{
+			name: `Given a canvas with an invalid task,
+                   when the render method is called from the JSON renderer,
+                   then an invalid task error is returned`,
+			canvas:      invalidCanvas(),
+			expectedErr: raster.ErrInvalidTask,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var output bytes.Buffer
+			err := jsonx.Renderer{}.Render(&output, tt.canvas, raster.FullViewport(tt.canvas))
+			if tt.expectedErr != nil {
+				require.ErrorAs(t, err, &tt.expectedErr)
+			} else {
+				require.NoError(t, err)
+				require.JSONEq(t, tt.expectedOutput, output.String())
+			}
+		})
+	}
+}
+
+func TestRenderer_RenderWithOwners(t *testing.T) {
+	tests := []struct {
+		name          string
+		canvas        domain.Canvas
+		expectedOwned jsonx.OwnedGrid
+		expectedErr   error
+	}{
+		{
+			name: `Given a canvas with a rectangle and a fill,
+                   when the render with owners method is called from the JSON renderer,
+                   then the rows of the canvas are returned with the task that drew each cell`,
+			canvas: canvasFixture1(),
+			expectedOwned: jsonx.OwnedGrid{
+				Grid: jsonx.Grid{
+					Height: 3,
+					Width:  5,
+					Rows:   []string{"###~~", "###~~", "~~~~~"},
+				},
+				Tasks: []uuid.UUID{rectangleID, fillID},
+				Owners: [][]int{
+					{0, 0, 0, 1, 1},
+					{0, 0, 0, 1, 1},
+					{1, 1, 1, 1, 1},
+				},
+			},
+		},
+		{
+			name: `Given a canvas without tasks,
+                   when the render with owners method is called from the JSON renderer,
+                   then all the cells are unowned`,
+			canvas: domain.NewCanvas(uuid.New(), 1, 2, nil, time.Now().UTC()),
+			expectedOwned: jsonx.OwnedGrid{
+				Grid: jsonx.Grid{
+					Height: 1,
+					Width:  2,
+					Rows:   []string{"  "},
+				},
+				Tasks:  []uuid.UUID{},
+				Owners: [][]int{{-1, -1}},
+			},
+		},
+		{
+			name: `Given a canvas with an invalid task,
+                   when the render with owners method is called from the JSON renderer,
+                   then an invalid task error is returned`,
+			canvas:      invalidCanvas(),
+			expectedErr: raster.ErrInvalidTask,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var output bytes.Buffer
+			err := jsonx.Renderer{}.RenderWithOwners(&output, tt.canvas, raster.FullViewport(tt.canvas))
+			if tt.expectedErr != nil {
+				require.ErrorAs(t, err, &tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+
+			var owned jsonx.OwnedGrid
+			err = json.Unmarshal(output.Bytes(), &owned)
+			require.NoError(t, err)
+			require.Equal(t, tt.expectedOwned, owned)
+		})
+	}
+}
@@ -0,0 +1,191 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/maitesin/sketch/internal/app"
+	"github.com/maitesin/sketch/internal/domain"
+)
+
+// canvas is the stored state of a canvas, like the rows of the SQL repository. The undone tasks are counted from the
+// end of the history, so the cursor follows the tasks when they are reordered
+type canvas struct {
+	id        uuid.UUID
+	height    int
+	width     int
+	undone    int
+	sequence  int
+	tasks     []domain.Task
+	createdAt time.Time
+}
+
+// CanvasRepository stores the canvases in memory, so the service can run without a DB. It is safe to use from several
+// goroutines, and it behaves as the SQL repository: the tasks are sorted by their sequence, inserting a canvas that
+// already exists does nothing, and only updating a canvas stores the tasks added, edited and removed
+type CanvasRepository struct {
+	mutex    sync.RWMutex
+	canvases map[uuid.UUID]canvas
+}
+
+// NewCanvasRepository is a constructor
+func NewCanvasRepository() *CanvasRepository {
+	return &CanvasRepository{canvases: map[uuid.UUID]canvas{}}
+}
+
+func (c *CanvasRepository) Insert(_ context.Context, dc domain.Canvas) error {
+	if err := validateTasks(dc.History()); err != nil {
+		return err
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if _, ok := c.canvases[dc.ID()]; ok {
+		return nil
+	}
+
+	c.canvases[dc.ID()] = canvas{
+		id:        dc.ID(),
+		height:    dc.Height(),
+		width:     dc.Width(),
+		createdAt: dc.CreatedAt(),
+	}
+	return nil
+}
+
+func (c *CanvasRepository) Update(_ context.Context, dc domain.Canvas) error {
+	if err := validateTasks(dc.History()); err != nil {
+		return err
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	stored, ok := c.canvases[dc.ID()]
+	if !ok {
+		return app.CanvasNotFound{ID: dc.ID()}
+	}
+
+	removed := make(map[uuid.UUID]bool, len(dc.RemovedTasks()))
+	for _, id := range dc.RemovedTasks() {
+		removed[id] = true
+	}
+	added := make(map[uuid.UUID]bool, len(dc.AddedTasks()))
+	for _, id := range dc.AddedTasks() {
+		added[id] = true
+	}
+	edited := make(map[uuid.UUID]bool, len(dc.EditedTasks()))
+	for _, id := range dc.EditedTasks() {
+		edited[id] = true
+	}
+
+	// Tasks added concurrently to the same canvas get the same sequence, so the last one to be stored conflicts
+	sequences := make(map[int]uuid.UUID, len(added))
+	for _, task := range dc.History() {
+		if id, _ := domain.TaskID(task); added[id] {
+			sequences[sequence(task)] = id
+		}
+	}
+	storedIDs := make(map[uuid.UUID]bool, len(stored.tasks))
+	for _, task := range stored.tasks {
+		id, _ := domain.TaskID(task)
+		storedIDs[id] = true
+		if addedID, ok := sequences[sequence(task)]; ok && addedID != id && !removed[id] {
+			return app.CanvasConflict{ID: dc.ID()}
+		}
+	}
+	// the sequences given to the tasks removed are not given again either
+	for taskSequence, id := range sequences {
+		if taskSequence <= stored.sequence && !storedIDs[id] {
+			return app.CanvasConflict{ID: dc.ID()}
+		}
+	}
+
+	// Only the changes of the canvas are applied to the stored tasks, so a canvas retrieved before another update does
+	// not bring back the tasks it removed, nor undo the tasks it edited
+	tasks := make([]domain.Task, 0, len(stored.tasks)+len(added))
+	indexes := make(map[uuid.UUID]int, cap(tasks))
+	for _, task := range stored.tasks {
+		id, _ := domain.TaskID(task)
+		if !removed[id] {
+			indexes[id] = len(tasks)
+			tasks = append(tasks, task)
+		}
+	}
+	for _, task := range dc.History() {
+		id, _ := domain.TaskID(task)
+		index, ok := indexes[id]
+		switch {
+		case added[id] && !ok:
+			indexes[id] = len(tasks)
+			tasks = append(tasks, task)
+		case edited[id] && ok:
+			tasks[index] = task
+		case edited[id]:
+			// the task was removed by another update
+			return app.CanvasConflict{ID: dc.ID()}
+		}
+	}
+
+	sort.SliceStable(tasks, func(i, j int) bool {
+		return sequence(tasks[i]) < sequence(tasks[j])
+	})
+
+	stored.height = dc.Height()
+	stored.width = dc.Width()
+	stored.undone = len(dc.History()) - dc.Cursor()
+	if dc.Sequence() > stored.sequence {
+		stored.sequence = dc.Sequence()
+	}
+	stored.tasks = tasks
+	c.canvases[dc.ID()] = stored
+	return nil
+}
+
+func (c *CanvasRepository) FindByID(_ context.Context, id uuid.UUID) (domain.Canvas, error) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	stored, ok := c.canvases[id]
+	if !ok {
+		return domain.Canvas{}, app.CanvasNotFound{ID: id}
+	}
+
+	// The canvas gets its own copy of the tasks, since adding tasks to it could write in the stored ones
+	tasks := make([]domain.Task, len(stored.tasks))
+	copy(tasks, stored.tasks)
+
+	return domain.NewCanvasWithHistory(
+		stored.id,
+		stored.height,
+		stored.width,
+		tasks,
+		len(tasks)-stored.undone,
+		stored.createdAt,
+	).WithSequence(stored.sequence), nil
+}
+
+func validateTasks(tasks []domain.Task) error {
+	for _, task := range tasks {
+		if _, ok := task.(interface{ CreatedAt() time.Time }); !ok {
+			return fmt.Errorf("failed to store task: %#v", task)
+		}
+		if _, ok := domain.TaskID(task); !ok {
+			return fmt.Errorf("failed to store task: %#v", task)
+		}
+		if _, ok := domain.TaskSequence(task); !ok {
+			return fmt.Errorf("failed to store task: %#v", task)
+		}
+	}
+	return nil
+}
+
+func sequence(task domain.Task) int {
+	sequence, _ := domain.TaskSequence(task)
+	return sequence
+}
@@ -0,0 +1,112 @@
+package memory_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/maitesin/sketch/internal/app"
+	"github.com/maitesin/sketch/internal/app/apptest"
+	"github.com/maitesin/sketch/internal/domain"
+	"github.com/maitesin/sketch/internal/infra/memory"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCanvasRepository(t *testing.T) {
+	apptest.TestCanvasRepository(t, func(*testing.T) app.CanvasRepository {
+		return memory.NewCanvasRepository()
+	})
+}
+
+func TestCanvasRepository_UpdateMissingCanvas(t *testing.T) {
+	t.Parallel()
+
+	canvas := domain.NewCanvas(uuid.New(), 10, 10, nil, time.Now().UTC())
+
+	err := memory.NewCanvasRepository().Update(context.Background(), canvas)
+	require.ErrorAs(t, err, &app.CanvasNotFound{})
+}
+
+func TestCanvasRepository_Concurrent(t *testing.T) {
+	t.Parallel()
+
+	const (
+		canvases = 8
+		lines    = 50
+	)
+
+	repository := memory.NewCanvasRepository()
+
+	var wg sync.WaitGroup
+	ids := make([]uuid.UUID, canvases)
+	for i := range ids {
+		ids[i] = uuid.New()
+		wg.Add(1)
+		go func(id uuid.UUID) {
+			defer wg.Done()
+
+			err := repository.Insert(context.Background(), domain.NewCanvas(id, 10, 10, nil, time.Now().UTC()))
+			require.NoError(t, err)
+
+			for j := 0; j < lines; j++ {
+				canvas, err := repository.FindByID(context.Background(), id)
+				require.NoError(t, err)
+
+				err = canvas.AddDrawLine(domain.NewDrawLine(uuid.New(), domain.NewPoint(0, j%10), domain.NewPoint(9, j%10), '-', time.Now().UTC()))
+				require.NoError(t, err)
+
+				err = repository.Update(context.Background(), canvas)
+				require.NoError(t, err)
+			}
+		}(ids[i])
+	}
+	wg.Wait()
+
+	for _, id := range ids {
+		canvas, err := repository.FindByID(context.Background(), id)
+		require.NoError(t, err)
+		require.Len(t, canvas.Tasks(), lines)
+	}
+}
+
+func TestCanvasRepository_StaleCanvas(t *testing.T) {
+	t.Parallel()
+
+	repository := memory.NewCanvasRepository()
+
+	rectangle := domain.NewDrawRectangle(uuid.New(), domain.NewPoint(0, 0), 3, 3, '.', '#', time.Now().UTC())
+	line := domain.NewDrawLine(uuid.New(), domain.NewPoint(0, 0), domain.NewPoint(9, 9), '\\', time.Now().UTC())
+	canvas := domain.NewCanvas(uuid.New(), 10, 10, []domain.Task{rectangle, line}, time.Now().UTC())
+	err := repository.Insert(context.Background(), canvas)
+	require.NoError(t, err)
+	err = repository.Update(context.Background(), canvas)
+	require.NoError(t, err)
+
+	stale, err := repository.FindByID(context.Background(), canvas.ID())
+	require.NoError(t, err)
+
+	removed, err := repository.FindByID(context.Background(), canvas.ID())
+	require.NoError(t, err)
+	err = removed.RemoveTask(line.ID())
+	require.NoError(t, err)
+	err = repository.Update(context.Background(), removed)
+	require.NoError(t, err)
+
+	// the canvas retrieved before the task was removed still has it, but storing it does not bring it back
+	err = stale.Undo()
+	require.NoError(t, err)
+	err = repository.Update(context.Background(), stale)
+	require.NoError(t, err)
+
+	stored, err := repository.FindByID(context.Background(), canvas.ID())
+	require.NoError(t, err)
+	require.Len(t, stored.History(), 1)
+
+	// nor can it edit the task removed
+	err = stale.UpdateTask(domain.NewDrawLine(line.ID(), domain.NewPoint(0, 9), domain.NewPoint(9, 0), '/', time.Now().UTC()))
+	require.NoError(t, err)
+	err = repository.Update(context.Background(), stale)
+	require.ErrorAs(t, err, &app.CanvasConflict{})
+}
@@ -0,0 +1,51 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/maitesin/sketch/internal/infra/raster"
+)
+
+// SnapshotRepository stores the latest snapshot of each canvas in memory. The snapshots are not copied, since they are
+// not changed once they are taken
+type SnapshotRepository struct {
+	mutex     sync.RWMutex
+	snapshots map[uuid.UUID]raster.Snapshot
+}
+
+// NewSnapshotRepository is a constructor
+func NewSnapshotRepository() *SnapshotRepository {
+	return &SnapshotRepository{snapshots: map[uuid.UUID]raster.Snapshot{}}
+}
+
+// Save stores the snapshot as the latest one of the canvas, replacing the previous one
+func (s *SnapshotRepository) Save(_ context.Context, canvasID uuid.UUID, snapshot raster.Snapshot) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.snapshots[canvasID] = snapshot
+	return nil
+}
+
+// FindByCanvasID returns the latest snapshot of the canvas, or raster.ErrSnapshotNotFound when it has none
+func (s *SnapshotRepository) FindByCanvasID(_ context.Context, canvasID uuid.UUID) (raster.Snapshot, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	snapshot, ok := s.snapshots[canvasID]
+	if !ok {
+		return raster.Snapshot{}, raster.ErrSnapshotNotFound
+	}
+	return snapshot, nil
+}
+
+// Delete removes the snapshot of the canvas
+func (s *SnapshotRepository) Delete(_ context.Context, canvasID uuid.UUID) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	delete(s.snapshots, canvasID)
+	return nil
+}
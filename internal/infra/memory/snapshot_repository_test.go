@@ -0,0 +1,50 @@
+package memory_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/maitesin/sketch/internal/domain"
+	"github.com/maitesin/sketch/internal/infra/memory"
+	"github.com/maitesin/sketch/internal/infra/raster"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSnapshotRepository(t *testing.T) {
+	t.Parallel()
+
+	canvas := domain.NewCanvas(uuid.New(), 5, 5, []domain.Task{
+		domain.NewDrawLine(uuid.New(), domain.NewPoint(0, 0), domain.NewPoint(4, 4), '\\', time.Now().UTC()),
+		domain.NewDrawLine(uuid.New(), domain.NewPoint(0, 4), domain.NewPoint(4, 0), '/', time.Now().UTC()),
+	}, time.Now().UTC())
+	repository := memory.NewSnapshotRepository()
+
+	_, err := repository.FindByCanvasID(context.Background(), canvas.ID())
+	require.ErrorIs(t, err, raster.ErrSnapshotNotFound)
+
+	first, err := raster.NewSnapshot(domain.NewCanvas(canvas.ID(), canvas.Height(), canvas.Width(), canvas.Tasks()[:1], canvas.CreatedAt()))
+	require.NoError(t, err)
+	err = repository.Save(context.Background(), canvas.ID(), first)
+	require.NoError(t, err)
+
+	snapshot, err := repository.FindByCanvasID(context.Background(), canvas.ID())
+	require.NoError(t, err)
+	require.Equal(t, first, snapshot)
+
+	second, err := raster.NewSnapshot(canvas)
+	require.NoError(t, err)
+	err = repository.Save(context.Background(), canvas.ID(), second)
+	require.NoError(t, err)
+
+	snapshot, err = repository.FindByCanvasID(context.Background(), canvas.ID())
+	require.NoError(t, err)
+	require.Equal(t, second, snapshot)
+
+	err = repository.Delete(context.Background(), canvas.ID())
+	require.NoError(t, err)
+
+	_, err = repository.FindByCanvasID(context.Background(), canvas.ID())
+	require.ErrorIs(t, err, raster.ErrSnapshotNotFound)
+}
@@ -0,0 +1,268 @@
+// Package migration applies and reverts the schema migrations of a DB, keeping track of the ones applied in the
+// schema_migrations table of the DB
+package migration
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/upper/db/v4"
+)
+
+const (
+	migrationsTable = "schema_migrations"
+	upSuffix        = ".up.sql"
+	downSuffix      = ".down.sql"
+)
+
+// ErrNoMigrationApplied is returned when reverting a migration of a DB without migrations applied
+var ErrNoMigrationApplied = errors.New("no migration applied")
+
+// Migration is a change of the schema of a DB, with the statements to apply it and to revert it
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Status tells whether a migration is applied in the DB, and when it was applied
+type Status struct {
+	Version   int
+	Name      string
+	Applied   bool
+	AppliedAt time.Time
+}
+
+// AppliedMigration is a row of the schema_migrations table
+type AppliedMigration struct {
+	Version   int       `db:"version"`
+	Name      string    `db:"name"`
+	AppliedAt time.Time `db:"applied_at"`
+}
+
+// Runner applies and reverts the migrations of a DB. Each migration is applied or reverted in its own transaction
+type Runner struct {
+	sess       db.Session
+	migrations []Migration
+}
+
+// NewRunner is a constructor. The migrations are read from the files in the root of the file system, which are named
+// after the version and the name of the migration, e.g. 0001_create_canvases_table.up.sql and
+// 0001_create_canvases_table.down.sql
+func NewRunner(sess db.Session, files fs.FS) (*Runner, error) {
+	migrations, err := readMigrations(files)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Runner{
+		sess:       sess,
+		migrations: migrations,
+	}, nil
+}
+
+func readMigrations(files fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(files, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := map[int]*Migration{}
+	for _, entry := range entries {
+		fileName := entry.Name()
+
+		var base string
+		switch {
+		case strings.HasSuffix(fileName, upSuffix):
+			base = strings.TrimSuffix(fileName, upSuffix)
+		case strings.HasSuffix(fileName, downSuffix):
+			base = strings.TrimSuffix(fileName, downSuffix)
+		default:
+			return nil, fmt.Errorf("invalid migration file %q: it must end in %q or %q", fileName, upSuffix, downSuffix)
+		}
+
+		parts := strings.SplitN(base, "_", 2)
+		version, err := strconv.Atoi(parts[0])
+		if err != nil || len(parts) != 2 {
+			return nil, fmt.Errorf("invalid migration file %q: it must start with its version", fileName)
+		}
+
+		content, err := fs.ReadFile(files, fileName)
+		if err != nil {
+			return nil, err
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: parts[1]}
+			byVersion[version] = migration
+		}
+		if migration.Name != parts[1] {
+			return nil, fmt.Errorf("invalid migration file %q: version %d is named %q", fileName, version, migration.Name)
+		}
+
+		if strings.HasSuffix(fileName, upSuffix) {
+			migration.Up = string(content)
+		} else {
+			migration.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up == "" || migration.Down == "" {
+			return nil, fmt.Errorf("migration %d %q must have both an up and a down file", migration.Version, migration.Name)
+		}
+		migrations = append(migrations, *migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+// Up applies all the migrations that are not applied yet, in order, and returns the ones applied
+func (r *Runner) Up(ctx context.Context) ([]Migration, error) {
+	applied, err := r.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var done []Migration
+	for _, migration := range r.migrations {
+		if _, ok := applied[migration.Version]; ok {
+			continue
+		}
+
+		migration := migration
+		err := r.sess.WithContext(ctx).Tx(func(sess db.Session) error {
+			_, err := sess.SQL().Exec(migration.Up)
+			if err != nil {
+				return err
+			}
+
+			_, err = sess.Collection(migrationsTable).Insert(AppliedMigration{
+				Version:   migration.Version,
+				Name:      migration.Name,
+				AppliedAt: time.Now().UTC(),
+			})
+			return err
+		})
+		if err != nil {
+			return done, fmt.Errorf("failed to apply migration %d %q: %w", migration.Version, migration.Name, err)
+		}
+		done = append(done, migration)
+	}
+
+	return done, nil
+}
+
+// Down reverts the latest migration applied, and returns it
+func (r *Runner) Down(ctx context.Context) (Migration, error) {
+	applied, err := r.applied(ctx)
+	if err != nil {
+		return Migration{}, err
+	}
+
+	for i := len(r.migrations) - 1; i >= 0; i-- {
+		migration := r.migrations[i]
+		if _, ok := applied[migration.Version]; !ok {
+			continue
+		}
+
+		err := r.sess.WithContext(ctx).Tx(func(sess db.Session) error {
+			_, err := sess.SQL().Exec(migration.Down)
+			if err != nil {
+				return err
+			}
+
+			return sess.Collection(migrationsTable).Find(db.Cond{"version": migration.Version}).Delete()
+		})
+		if err != nil {
+			return Migration{}, fmt.Errorf("failed to revert migration %d %q: %w", migration.Version, migration.Name, err)
+		}
+		return migration, nil
+	}
+
+	return Migration{}, ErrNoMigrationApplied
+}
+
+// Status returns whether each migration is applied in the DB
+func (r *Runner) Status(ctx context.Context) ([]Status, error) {
+	applied, err := r.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]Status, len(r.migrations))
+	for i, migration := range r.migrations {
+		appliedMigration, ok := applied[migration.Version]
+		statuses[i] = Status{
+			Version:   migration.Version,
+			Name:      migration.Name,
+			Applied:   ok,
+			AppliedAt: appliedMigration.AppliedAt,
+		}
+	}
+
+	return statuses, nil
+}
+
+// Baseline marks the migrations up to the version as applied without applying them, for DBs whose schema was created
+// before tracking the migrations applied. It does nothing when any migration is already marked as applied
+func (r *Runner) Baseline(ctx context.Context, version int) error {
+	applied, err := r.applied(ctx)
+	if err != nil || len(applied) > 0 {
+		return err
+	}
+
+	return r.sess.WithContext(ctx).Tx(func(sess db.Session) error {
+		for _, migration := range r.migrations {
+			if migration.Version > version {
+				break
+			}
+
+			_, err := sess.Collection(migrationsTable).Insert(AppliedMigration{
+				Version:   migration.Version,
+				Name:      migration.Name,
+				AppliedAt: time.Now().UTC(),
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// applied creates the schema_migrations table when it does not exist, and returns the migrations applied by version
+func (r *Runner) applied(ctx context.Context) (map[int]AppliedMigration, error) {
+	_, err := r.sess.WithContext(ctx).SQL().Exec(`CREATE TABLE IF NOT EXISTS ` + migrationsTable + ` (
+    version INT PRIMARY KEY,
+    name TEXT NOT NULL,
+    applied_at TIMESTAMP NOT NULL
+)`)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []AppliedMigration
+	err = r.sess.WithContext(ctx).Collection(migrationsTable).Find().All(&rows)
+	if err != nil && err != db.ErrNoMoreRows {
+		return nil, err
+	}
+
+	applied := make(map[int]AppliedMigration, len(rows))
+	for _, row := range rows {
+		applied[row.Version] = row
+	}
+	return applied, nil
+}
@@ -0,0 +1,213 @@
+package migration_test
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/maitesin/sketch/internal/infra/migration"
+	"github.com/maitesin/sketch/internal/infra/sqlite"
+	"github.com/stretchr/testify/require"
+	"github.com/upper/db/v4"
+)
+
+func validMigrations() fstest.MapFS {
+	return fstest.MapFS{
+		"0001_create_canvases_table.up.sql":    {Data: []byte(`CREATE TABLE canvases (id TEXT PRIMARY KEY);`)},
+		"0001_create_canvases_table.down.sql":  {Data: []byte(`DROP TABLE canvases;`)},
+		"0002_add_height_to_canvases.up.sql":   {Data: []byte(`ALTER TABLE canvases ADD COLUMN height INT NOT NULL DEFAULT 0;`)},
+		"0002_add_height_to_canvases.down.sql": {Data: []byte(`ALTER TABLE canvases DROP COLUMN height;`)},
+		"0010_create_snapshots_table.up.sql":   {Data: []byte(`CREATE TABLE snapshots (canvas_id TEXT PRIMARY KEY);`)},
+		"0010_create_snapshots_table.down.sql": {Data: []byte(`DROP TABLE snapshots;`)},
+	}
+}
+
+func session(t *testing.T) db.Session {
+	t.Helper()
+
+	sess, err := sqlite.Open(sqlite.Config{Path: filepath.Join(t.TempDir(), "migrations.db")})
+	require.NoError(t, err)
+	t.Cleanup(func() { sess.Close() })
+
+	return sess
+}
+
+func TestNewRunner(t *testing.T) {
+	tests := []struct {
+		name        string
+		files       func() fstest.MapFS
+		expectedErr error
+	}{
+		{
+			name: `Given migrations with up and down files,
+                   when a runner is created,
+                   then no error is returned`,
+			files: validMigrations,
+		},
+		{
+			name: `Given a migration without down file,
+                   when a runner is created,
+                   then an error is returned`,
+			files: func() fstest.MapFS {
+				files := validMigrations()
+				delete(files, "0002_add_height_to_canvases.down.sql")
+				return files
+			},
+			expectedErr: errors.New(""),
+		},
+		{
+			name: `Given a file that is not a migration,
+                   when a runner is created,
+                   then an error is returned`,
+			files: func() fstest.MapFS {
+				files := validMigrations()
+				files["README.md"] = &fstest.MapFile{}
+				return files
+			},
+			expectedErr: errors.New(""),
+		},
+		{
+			name: `Given a migration without version,
+                   when a runner is created,
+                   then an error is returned`,
+			files: func() fstest.MapFS {
+				files := validMigrations()
+				files["create_lines_table.up.sql"] = &fstest.MapFile{}
+				return files
+			},
+			expectedErr: errors.New(""),
+		},
+		{
+			name: `Given up and down files of the same version with different names,
+                   when a runner is created,
+                   then an error is returned`,
+			files: func() fstest.MapFS {
+				files := validMigrations()
+				files["0002_add_width_to_canvases.down.sql"] = files["0002_add_height_to_canvases.down.sql"]
+				delete(files, "0002_add_height_to_canvases.down.sql")
+				return files
+			},
+			expectedErr: errors.New(""),
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := migration.NewRunner(nil, tt.files())
+			if tt.expectedErr != nil {
+				require.ErrorAs(t, err, &tt.expectedErr)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestRunner(t *testing.T) {
+	t.Parallel()
+
+	sess := session(t)
+	runner, err := migration.NewRunner(sess, validMigrations())
+	require.NoError(t, err)
+
+	statuses, err := runner.Status(context.Background())
+	require.NoError(t, err)
+	require.Len(t, statuses, 3)
+	for _, status := range statuses {
+		require.False(t, status.Applied)
+	}
+
+	applied, err := runner.Up(context.Background())
+	require.NoError(t, err)
+	require.Len(t, applied, 3)
+	require.Equal(t, []int{1, 2, 10}, []int{applied[0].Version, applied[1].Version, applied[2].Version})
+
+	applied, err = runner.Up(context.Background())
+	require.NoError(t, err)
+	require.Empty(t, applied)
+
+	reverted, err := runner.Down(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 10, reverted.Version)
+	require.Equal(t, "create_snapshots_table", reverted.Name)
+
+	statuses, err = runner.Status(context.Background())
+	require.NoError(t, err)
+	require.True(t, statuses[0].Applied)
+	require.False(t, statuses[0].AppliedAt.IsZero())
+	require.True(t, statuses[1].Applied)
+	require.False(t, statuses[2].Applied)
+
+	_, err = sess.SQL().Exec(`INSERT INTO canvases (id, height) VALUES ('a', 1)`)
+	require.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		_, err = runner.Down(context.Background())
+		require.NoError(t, err)
+	}
+
+	_, err = runner.Down(context.Background())
+	require.ErrorIs(t, err, migration.ErrNoMigrationApplied)
+}
+
+func TestRunner_FailedMigration(t *testing.T) {
+	t.Parallel()
+
+	files := validMigrations()
+	files["0002_add_height_to_canvases.up.sql"] = &fstest.MapFile{
+		Data: []byte(`ALTER TABLE canvases ADD COLUMN height INT NOT NULL DEFAULT 0; ALTER TABLE lines ADD COLUMN height INT;`),
+	}
+
+	sess := session(t)
+	runner, err := migration.NewRunner(sess, files)
+	require.NoError(t, err)
+
+	applied, err := runner.Up(context.Background())
+	require.Error(t, err)
+	require.Len(t, applied, 1)
+
+	statuses, err := runner.Status(context.Background())
+	require.NoError(t, err)
+	require.True(t, statuses[0].Applied)
+	require.False(t, statuses[1].Applied)
+	require.False(t, statuses[2].Applied)
+
+	// the statements of the failed migration are reverted along with it
+	_, err = sess.SQL().Exec(`INSERT INTO canvases (id) VALUES ('a')`)
+	require.NoError(t, err)
+	_, err = sess.SQL().Exec(`INSERT INTO canvases (id, height) VALUES ('b', 1)`)
+	require.Error(t, err)
+}
+
+func TestRunner_Baseline(t *testing.T) {
+	t.Parallel()
+
+	sess := session(t)
+	runner, err := migration.NewRunner(sess, validMigrations())
+	require.NoError(t, err)
+
+	_, err = sess.SQL().Exec(`CREATE TABLE canvases (id TEXT PRIMARY KEY, height INT NOT NULL DEFAULT 0)`)
+	require.NoError(t, err)
+
+	err = runner.Baseline(context.Background(), 2)
+	require.NoError(t, err)
+
+	applied, err := runner.Up(context.Background())
+	require.NoError(t, err)
+	require.Len(t, applied, 1)
+	require.Equal(t, 10, applied[0].Version)
+
+	// a DB with migrations applied is not baselined again
+	err = runner.Baseline(context.Background(), 1)
+	require.NoError(t, err)
+
+	statuses, err := runner.Status(context.Background())
+	require.NoError(t, err)
+	for _, status := range statuses {
+		require.True(t, status.Applied)
+	}
+}
@@ -0,0 +1,31 @@
+package png
+
+import (
+	"fmt"
+	"image/color"
+	"strconv"
+	"strings"
+)
+
+// Config defines the size in pixels of each cell of the canvas and the colours used to draw the image
+type Config struct {
+	CellWidth  int
+	CellHeight int
+	Foreground color.RGBA
+	Background color.RGBA
+}
+
+// ParseColour parses a colour in the #rrggbb hexadecimal notation
+func ParseColour(value string) (color.RGBA, error) {
+	hex := strings.TrimPrefix(value, "#")
+	if len(hex) != 6 || len(hex) == len(value) {
+		return color.RGBA{}, fmt.Errorf("colour %q is not in the #rrggbb format", value)
+	}
+
+	rgb, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return color.RGBA{}, fmt.Errorf("colour %q is not in the #rrggbb format", value)
+	}
+
+	return color.RGBA{R: uint8(rgb >> 16), G: uint8(rgb >> 8), B: uint8(rgb), A: 0xff}, nil
+}
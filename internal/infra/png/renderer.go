@@ -0,0 +1,82 @@
+package png
+
+import (
+	"errors"
+	"image"
+	"image/color"
+	imagepng "image/png"
+	"io"
+
+	"github.com/maitesin/sketch/internal/domain"
+	"github.com/maitesin/sketch/internal/infra/raster"
+)
+
+const (
+	// glyphWidth and glyphHeight are the size in pixels of each glyph of the font
+	glyphWidth  = 6
+	glyphHeight = 13
+	// glyphAdvance is the width of the box where each glyph is drawn, that leaves a blank column between glyphs
+	glyphAdvance = glyphWidth + 1
+
+	firstGlyph = ' '
+)
+
+// ErrInvalidCellSize is returned when the renderer is configured with a cell without pixels
+var ErrInvalidCellSize = errors.New("invalid cell size")
+
+// Renderer draws the canvas as a PNG image, where each cell contains the glyph of its rune in an embedded bitmap
+// font scaled to the size of the cell
+type Renderer struct {
+	cfg Config
+}
+
+func NewRenderer(cfg Config) Renderer {
+	return Renderer{cfg: cfg}
+}
+
+func (r Renderer) Render(writer io.Writer, c domain.Canvas, viewport raster.Viewport) error {
+	if r.cfg.CellWidth <= 0 || r.cfg.CellHeight <= 0 {
+		return ErrInvalidCellSize
+	}
+
+	grid, err := raster.Rasterize(c, viewport)
+	if err != nil {
+		return err
+	}
+
+	palette := color.Palette{r.cfg.Background, r.cfg.Foreground}
+	img := image.NewPaletted(image.Rect(0, 0, viewport.Width*r.cfg.CellWidth, viewport.Height*r.cfg.CellHeight), palette)
+	for y, row := range grid {
+		for x, value := range row {
+			r.drawGlyph(img, x*r.cfg.CellWidth, y*r.cfg.CellHeight, glyph(value))
+		}
+	}
+
+	return imagepng.Encode(writer, img)
+}
+
+// drawGlyph draws the glyph in the cell with the given top left corner, scaling the glyph box to the cell size
+func (r Renderer) drawGlyph(img *image.Paletted, left, top int, bitmap [glyphHeight]uint8) {
+	for py := 0; py < r.cfg.CellHeight; py++ {
+		row := bitmap[py*glyphHeight/r.cfg.CellHeight]
+		if row == 0 {
+			continue
+		}
+
+		for px := 0; px < r.cfg.CellWidth; px++ {
+			gx := px * glyphAdvance / r.cfg.CellWidth
+			if gx < glyphWidth && row&(1<<(glyphWidth-1-gx)) != 0 {
+				img.SetColorIndex(left+px, top+py, 1)
+			}
+		}
+	}
+}
+
+// glyph returns the bitmap of the rune, or the replacement glyph when the rune is not in the font
+func glyph(value rune) [glyphHeight]uint8 {
+	index := int(value - firstGlyph)
+	if index < 0 || index >= len(glyphs) {
+		return replacement
+	}
+	return glyphs[index]
+}
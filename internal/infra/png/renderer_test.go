@@ -0,0 +1,236 @@
+package png_test
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	imagepng "image/png"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/maitesin/sketch/internal/domain"
+	"github.com/maitesin/sketch/internal/infra/png"
+	"github.com/maitesin/sketch/internal/infra/raster"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	black = color.RGBA{A: 0xff}
+	white = color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff}
+	red   = color.RGBA{R: 0xff, A: 0xff}
+)
+
+func configFixture(cellWidth, cellHeight int) png.Config {
+	return png.Config{
+		CellWidth:  cellWidth,
+		CellHeight: cellHeight,
+		Foreground: black,
+		Background: white,
+	}
+}
+
+func canvasFixture1() domain.Canvas {
+	return domain.NewCanvas(
+		uuid.New(),
+		3,
+		4,
+		[]domain.Task{
+			domain.NewDrawRectangle(uuid.New(), domain.NewPoint(0, 0), 2, 2, '@', '@', time.Now().UTC()),
+			domain.NewDrawText(uuid.New(), domain.NewPoint(0, 2), "Hi", 2, domain.AlignLeft, time.Now().UTC()),
+			domain.NewDrawLine(uuid.New(), domain.NewPoint(3, 0), domain.NewPoint(3, 2), 'é', time.Now().UTC()),
+		},
+		time.Now().UTC(),
+	)
+}
+
+func invalidCanvas() domain.Canvas {
+	return domain.NewCanvas(
+		uuid.New(),
+		10,
+		10,
+		[]domain.Task{"this will fail"},
+		time.Now().UTC(),
+	)
+}
+
+func render(t *testing.T, cfg png.Config, canvas domain.Canvas) image.Image {
+	t.Helper()
+
+	var output bytes.Buffer
+	err := png.NewRenderer(cfg).Render(&output, canvas, raster.FullViewport(canvas))
+	require.NoError(t, err)
+
+	img, err := imagepng.Decode(&output)
+	require.NoError(t, err)
+
+	return img
+}
+
+// colours returns the set of colours used in the rectangle of the image
+func colours(img image.Image, rect image.Rectangle) map[color.RGBA]bool {
+	used := map[color.RGBA]bool{}
+	for y := rect.Min.Y; y < rect.Max.Y; y++ {
+		for x := rect.Min.X; x < rect.Max.X; x++ {
+			used[color.RGBAModel.Convert(img.At(x, y)).(color.RGBA)] = true
+		}
+	}
+	return used
+}
+
+// cell returns the pixels of the cell of the canvas drawn with cells of 7x13 pixels
+func cell(img image.Image, x, y int) []color.Color {
+	var pixels []color.Color
+	for py := y * 13; py < (y+1)*13; py++ {
+		for px := x * 7; px < (x+1)*7; px++ {
+			pixels = append(pixels, img.At(px, py))
+		}
+	}
+	return pixels
+}
+
+func TestRenderer(t *testing.T) {
+	t.Parallel()
+
+	img := render(t, configFixture(7, 13), canvasFixture1())
+	require.Equal(t, image.Rect(0, 0, 28, 39), img.Bounds())
+
+	// the cells with a rune contain both colours and the empty cells only the background
+	require.Equal(t, map[color.RGBA]bool{black: true, white: true}, colours(img, image.Rect(0, 0, 7, 13)))
+	require.Equal(t, map[color.RGBA]bool{white: true}, colours(img, image.Rect(14, 0, 21, 13)))
+	// runes outside of the font are drawn with the replacement glyph
+	require.Equal(t, map[color.RGBA]bool{black: true, white: true}, colours(img, image.Rect(21, 0, 28, 13)))
+	// the last column of each cell separates the glyphs
+	require.Equal(t, map[color.RGBA]bool{white: true}, colours(img, image.Rect(6, 0, 7, 39)))
+	// different runes are drawn with different glyphs
+	require.NotEqual(t, cell(img, 0, 2), cell(img, 1, 2))
+	// the same rune is always drawn with the same glyph
+	require.Equal(t, cell(img, 0, 0), cell(img, 1, 1))
+}
+
+func TestRenderer_Scaled(t *testing.T) {
+	t.Parallel()
+
+	original := render(t, configFixture(7, 13), canvasFixture1())
+	scaled := render(t, configFixture(14, 26), canvasFixture1())
+	require.Equal(t, image.Rect(0, 0, 56, 78), scaled.Bounds())
+
+	for y := 0; y < 78; y++ {
+		for x := 0; x < 56; x++ {
+			require.Equal(t, original.At(x/2, y/2), scaled.At(x, y), "pixel (%d, %d)", x, y)
+		}
+	}
+}
+
+func TestRenderer_Colours(t *testing.T) {
+	t.Parallel()
+
+	cfg := configFixture(7, 13)
+	cfg.Foreground, cfg.Background = red, black
+
+	img := render(t, cfg, canvasFixture1())
+	require.Equal(t, map[color.RGBA]bool{red: true, black: true}, colours(img, img.Bounds()))
+}
+
+func TestRenderer_Errors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		cfg         png.Config
+		canvas      domain.Canvas
+		expectedErr error
+	}{
+		{
+			name: `Given a canvas with an invalid task,
+                   when the render method is called from the PNG renderer,
+                   then an invalid task error is returned`,
+			cfg:         configFixture(7, 13),
+			canvas:      invalidCanvas(),
+			expectedErr: raster.ErrInvalidTask,
+		},
+		{
+			name: `Given a PNG renderer configured with an empty cell,
+                   when the render method is called,
+                   then an invalid cell size error is returned`,
+			cfg:         configFixture(0, 13),
+			canvas:      canvasFixture1(),
+			expectedErr: png.ErrInvalidCellSize,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var output bytes.Buffer
+			err := png.NewRenderer(tt.cfg).Render(&output, tt.canvas, raster.FullViewport(tt.canvas))
+			require.True(t, errors.Is(err, tt.expectedErr))
+			require.Zero(t, output.Len())
+		})
+	}
+}
+
+func TestParseColour(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		value          string
+		expectedColour color.RGBA
+		expectedErr    bool
+	}{
+		{
+			name: `Given a colour in the #rrggbb format,
+                   when it is parsed,
+                   then the colour is returned`,
+			value:          "#ff8000",
+			expectedColour: color.RGBA{R: 0xff, G: 0x80, A: 0xff},
+		},
+		{
+			name: `Given a colour in upper case,
+                   when it is parsed,
+                   then the colour is returned`,
+			value:          "#00FF7F",
+			expectedColour: color.RGBA{G: 0xff, B: 0x7f, A: 0xff},
+		},
+		{
+			name: `Given a colour without the leading hash,
+                   when it is parsed,
+                   then an error is returned`,
+			value:       "ff8000",
+			expectedErr: true,
+		},
+		{
+			name: `Given a colour with the wrong length,
+                   when it is parsed,
+                   then an error is returned`,
+			value:       "#fff",
+			expectedErr: true,
+		},
+		{
+			name: `Given a colour with non hexadecimal digits,
+                   when it is parsed,
+                   then an error is returned`,
+			value:       "#gg0000",
+			expectedErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			colour, err := png.ParseColour(tt.value)
+			if tt.expectedErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expectedColour, colour)
+		})
+	}
+}
@@ -0,0 +1,9 @@
+package raster
+
+import "errors"
+
+var (
+	ErrInvalidTask      = errors.New("invalid task")
+	ErrInvalidViewport  = errors.New("invalid viewport")
+	ErrSnapshotNotFound = errors.New("snapshot not found")
+)
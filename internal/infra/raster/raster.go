@@ -0,0 +1,554 @@
+package raster
+
+import (
+	"math"
+	"sort"
+
+	"github.com/google/uuid"
+	"github.com/maitesin/sketch/internal/domain"
+)
+
+// Grid is the raster where the tasks are drawn, with a rune per cell. Writes outside of it are ignored, so tasks
+// cropped by a resize of the canvas are only drawn partially
+type Grid [][]rune
+
+// NewGrid returns a grid with all its cells blank
+func NewGrid(height, width int) Grid {
+	grid := make(Grid, height)
+	for i := range grid {
+		grid[i] = make([]rune, width)
+		for j := range grid[i] {
+			grid[i][j] = ' '
+		}
+	}
+	return grid
+}
+
+// Styles contains the style of each cell of a grid
+type Styles [][]domain.Style
+
+// NewStyles returns the styles of a grid with all its cells using the default colours
+func NewStyles(height, width int) Styles {
+	styles := make(Styles, height)
+	for i := range styles {
+		styles[i] = make([]domain.Style, width)
+	}
+	return styles
+}
+
+// Owners contains the ID of the task that drew each cell of a grid last, or the nil UUID for the blank cells
+type Owners [][]uuid.UUID
+
+// NewOwners returns the owners of a grid with all its cells blank
+func NewOwners(height, width int) Owners {
+	owners := make(Owners, height)
+	for i := range owners {
+		owners[i] = make([]uuid.UUID, width)
+	}
+	return owners
+}
+
+// Rasterize draws all the tasks of the canvas in a new grid with the cells of the viewport, in the order they are
+// rendered
+func Rasterize(canvas domain.Canvas, viewport Viewport) (Grid, error) {
+	p, err := rasterize(canvas, viewport, false, false)
+	return p.grid, err
+}
+
+// RasterizeWithStyles draws all the tasks of the canvas in a new grid with the cells of the viewport, keeping track of
+// the style of each cell. The cells drawn by tasks without colours go back to the default colours
+func RasterizeWithStyles(canvas domain.Canvas, viewport Viewport) (Grid, Styles, error) {
+	p, err := rasterize(canvas, viewport, true, false)
+	return p.grid, p.styles, err
+}
+
+// RasterizeWithOwners draws all the tasks of the canvas in a new grid with the cells of the viewport, keeping track of
+// the task that drew each cell
+func RasterizeWithOwners(canvas domain.Canvas, viewport Viewport) (Grid, Owners, error) {
+	p, err := rasterize(canvas, viewport, false, true)
+	return p.grid, p.owners, err
+}
+
+// rasterize only draws the cells of the viewport, so the work depends on its size instead of the size of the canvas.
+// The cells covered by a fill depend on the cells drawn outside of the viewport too, and its region can only be known
+// by flooding it, so the tasks up to the last fill are drawn in the whole canvas. The rest of the tasks are drawn in
+// the viewport only, so a canvas with an early fill costs little more than one without fills, but one whose last task
+// is a fill costs the same as drawing the whole canvas
+func rasterize(canvas domain.Canvas, viewport Viewport, withStyles, withOwners bool) (pen, error) {
+	if err := viewport.Validate(canvas); err != nil {
+		return pen{}, err
+	}
+
+	tasks := canvas.Tasks()
+	filled := 0
+	for i, task := range tasks {
+		if _, ok := task.(domain.Fill); ok {
+			filled = i + 1
+		}
+	}
+
+	window := viewport
+	if filled > 0 {
+		window = FullViewport(canvas)
+	}
+
+	p := pen{
+		grid: NewGrid(window.Height, window.Width),
+		left: window.X,
+		top:  window.Y,
+	}
+	if withStyles {
+		p.styles = NewStyles(window.Height, window.Width)
+	}
+	if withOwners {
+		p.owners = NewOwners(window.Height, window.Width)
+	}
+	for _, task := range tasks[:filled] {
+		if err := draw(p, task); err != nil {
+			return pen{}, err
+		}
+	}
+
+	p = p.crop(viewport)
+	for _, task := range tasks[filled:] {
+		if err := draw(p, task); err != nil {
+			return pen{}, err
+		}
+	}
+
+	return p, nil
+}
+
+// Draw draws the task on top of what is already drawn in the grid
+func (g Grid) Draw(task domain.Task) error {
+	return draw(pen{grid: g}, task)
+}
+
+func draw(canvas pen, task domain.Task) error {
+	canvas.owner, _ = domain.TaskID(task)
+	switch task := task.(type) {
+	case domain.DrawRectangle:
+		drawRectangle(canvas, task)
+	case domain.Fill:
+		addFill(canvas, task)
+	case domain.DrawLine:
+		drawLine(canvas, task)
+	case domain.DrawEllipse:
+		drawEllipse(canvas, task)
+	case domain.DrawText:
+		drawText(canvas, task)
+	case domain.DrawPolyline:
+		drawPolyline(canvas, task)
+	case domain.DrawPolygon:
+		drawPolygon(canvas, task)
+	case Snapshot:
+		drawSnapshot(canvas, task)
+	default:
+		return ErrInvalidTask
+	}
+	return nil
+}
+
+// Contains returns whether the cell in x and y is inside of the grid
+func (g Grid) Contains(x, y int) bool {
+	return y >= 0 && y < len(g) && x >= 0 && x < len(g[y])
+}
+
+// pen writes the runes of the tasks in a grid, whose top left cell is the cell of the canvas in left and top. When the
+// styles or the owners are tracked, it also writes the style and the owner of the pen
+type pen struct {
+	grid   Grid
+	left   int
+	top    int
+	styles Styles
+	style  domain.Style
+	owners Owners
+	owner  uuid.UUID
+}
+
+// with returns a copy of the pen that writes the style
+func (p pen) with(style domain.Style) pen {
+	p.style = style
+	return p
+}
+
+// crop returns a copy of the pen that only contains the cells of the viewport
+func (p pen) crop(viewport Viewport) pen {
+	if p.left == viewport.X && p.top == viewport.Y && len(p.grid) == viewport.Height {
+		return p
+	}
+
+	x, y := viewport.X-p.left, viewport.Y-p.top
+	cropped := pen{left: viewport.X, top: viewport.Y, grid: make(Grid, viewport.Height)}
+	for i := range cropped.grid {
+		cropped.grid[i] = p.grid[y+i][x : x+viewport.Width]
+	}
+	if p.styles != nil {
+		cropped.styles = make(Styles, viewport.Height)
+		for i := range cropped.styles {
+			cropped.styles[i] = p.styles[y+i][x : x+viewport.Width]
+		}
+	}
+	if p.owners != nil {
+		cropped.owners = make(Owners, viewport.Height)
+		for i := range cropped.owners {
+			cropped.owners[i] = p.owners[y+i][x : x+viewport.Width]
+		}
+	}
+	return cropped
+}
+
+// clip returns the part of the rectangle from the top left to the bottom right cells, both included, that is inside of
+// the grid
+func (p pen) clip(left, top, right, bottom int) (int, int, int, int) {
+	width := 0
+	if len(p.grid) > 0 {
+		width = len(p.grid[0])
+	}
+	return max(left, p.left), max(top, p.top), min(right, p.left+width-1), min(bottom, p.top+len(p.grid)-1)
+}
+
+func (p pen) set(x, y int, r rune) {
+	x, y = x-p.left, y-p.top
+	if p.grid.Contains(x, y) {
+		p.grid[y][x] = r
+		if p.styles != nil {
+			p.styles[y][x] = p.style
+		}
+		if p.owners != nil {
+			p.owners[y][x] = p.owner
+		}
+	}
+}
+
+func drawRectangle(canvas pen, rectangle domain.DrawRectangle) {
+	y := rectangle.Point().Y()
+	x := rectangle.Point().X()
+
+	outline := canvas.with(rectangle.OutlineStyle())
+	for i := x; i < x+rectangle.Width(); i++ {
+		outline.set(i, y, rectangle.Outline())
+		outline.set(i, y+rectangle.Height()-1, rectangle.Outline())
+	}
+	for i := y; i < y+rectangle.Height(); i++ {
+		outline.set(x, i, rectangle.Outline())
+		outline.set(x+rectangle.Width()-1, i, rectangle.Outline())
+	}
+
+	filler := canvas.with(rectangle.FillerStyle())
+	paint := paintWith(rectangle.Filler(), rectangle.Pattern(), rectangle.Point())
+	left, top, right, bottom := canvas.clip(x+1, y+1, x+rectangle.Width()-2, y+rectangle.Height()-2)
+	for i := left; i <= right; i++ {
+		for j := top; j <= bottom; j++ {
+			filler.set(i, j, paint(i, j))
+		}
+	}
+}
+
+func drawLine(canvas pen, line domain.DrawLine) {
+	rasterLine(canvas, line.From(), line.To(), line.Outline())
+}
+
+// rasterLine uses Bresenham's line algorithm, so it works for lines in any direction
+func rasterLine(canvas pen, from, to domain.Point, r rune) {
+	x, y := from.X(), from.Y()
+	toX, toY := to.X(), to.Y()
+
+	dx, stepX := abs(toX-x), 1
+	if x > toX {
+		stepX = -1
+	}
+	dy, stepY := -abs(toY-y), 1
+	if y > toY {
+		stepY = -1
+	}
+
+	diff := dx + dy
+	for {
+		canvas.set(x, y, r)
+		if x == toX && y == toY {
+			return
+		}
+
+		double := 2 * diff
+		if double >= dy {
+			diff += dy
+			x += stepX
+		}
+		if double <= dx {
+			diff += dx
+			y += stepY
+		}
+	}
+}
+
+// drawEllipse fills the inside of the ellipse and then draws its outline on top of it. Ellipses with an even
+// height or width do not have a center cell, so the bottom or right half is shifted by one cell
+func drawEllipse(canvas pen, ellipse domain.DrawEllipse) {
+	if ellipse.Height() <= 0 || ellipse.Width() <= 0 {
+		return
+	}
+
+	radiusX, radiusY := (ellipse.Width()-1)/2, (ellipse.Height()-1)/2
+	centerX, centerY := ellipse.Point().X()+radiusX, ellipse.Point().Y()+radiusY
+	evenX, evenY := 1-ellipse.Width()%2, 1-ellipse.Height()%2
+
+	quadrant := ellipseQuadrant(radiusX, radiusY)
+
+	spans := make([]int, radiusY+1)
+	for _, point := range quadrant {
+		if spans[point.Y()] < point.X() {
+			spans[point.Y()] = point.X()
+		}
+	}
+	for dy, dx := range spans {
+		for x := centerX - dx + 1; x < centerX+dx+evenX; x++ {
+			canvas.set(x, centerY-dy, ellipse.Filler())
+			canvas.set(x, centerY+dy+evenY, ellipse.Filler())
+		}
+	}
+
+	for _, point := range quadrant {
+		left, right := centerX-point.X(), centerX+point.X()+evenX
+		top, bottom := centerY-point.Y(), centerY+point.Y()+evenY
+		canvas.set(left, top, ellipse.Outline())
+		canvas.set(right, top, ellipse.Outline())
+		canvas.set(left, bottom, ellipse.Outline())
+		canvas.set(right, bottom, ellipse.Outline())
+	}
+}
+
+// ellipseQuadrant uses the midpoint ellipse algorithm to calculate the outline of the top right quadrant
+// of an ellipse centered in the origin
+func ellipseQuadrant(radiusX, radiusY int) []domain.Point {
+	if radiusY == 0 {
+		points := make([]domain.Point, radiusX+1)
+		for x := range points {
+			points[x] = domain.NewPoint(x, 0)
+		}
+		return points
+	}
+
+	var points []domain.Point
+	rx2, ry2 := float64(radiusX*radiusX), float64(radiusY*radiusY)
+	x, y := 0, radiusY
+	dx, dy := 0.0, 2*rx2*float64(y)
+
+	decision := ry2 - rx2*float64(radiusY) + rx2/4
+	for dx < dy {
+		points = append(points, domain.NewPoint(x, y))
+		x++
+		dx += 2 * ry2
+		if decision < 0 {
+			decision += dx + ry2
+		} else {
+			y--
+			dy -= 2 * rx2
+			decision += dx - dy + ry2
+		}
+	}
+
+	decision = ry2*(float64(x)+0.5)*(float64(x)+0.5) + rx2*float64((y-1)*(y-1)) - rx2*ry2
+	for y >= 0 {
+		points = append(points, domain.NewPoint(x, y))
+		y--
+		dy -= 2 * rx2
+		if decision > 0 {
+			decision += rx2 - dy
+		} else {
+			x++
+			dx += 2 * ry2
+			decision += dx - dy + rx2
+		}
+	}
+
+	return points
+}
+
+func drawText(canvas pen, text domain.DrawText) {
+	for _, line := range text.Lines() {
+		x, y := line.Point().X(), line.Point().Y()
+		for i, r := range []rune(line.Text()) {
+			canvas.set(x+i, y, r)
+		}
+	}
+}
+
+func drawPolyline(canvas pen, polyline domain.DrawPolyline) {
+	points := polyline.Points()
+	for i := 1; i < len(points); i++ {
+		rasterLine(canvas, points[i-1], points[i], polyline.Outline())
+	}
+}
+
+// drawPolygon fills the inside of the polygon using a scanline with the even-odd rule, and then draws its edges
+// on top of it
+func drawPolygon(canvas pen, polygon domain.DrawPolygon) {
+	points := polygon.Points()
+	if len(points) == 0 {
+		return
+	}
+
+	top, bottom := points[0].Y(), points[0].Y()
+	for _, point := range points {
+		if point.Y() < top {
+			top = point.Y()
+		}
+		if point.Y() > bottom {
+			bottom = point.Y()
+		}
+	}
+
+	for y := top; y <= bottom; y++ {
+		var crossings []float64
+		for i := range points {
+			from, to := points[i], points[(i+1)%len(points)]
+			if (from.Y() <= y && y < to.Y()) || (to.Y() <= y && y < from.Y()) {
+				crossings = append(crossings,
+					float64(from.X())+float64((y-from.Y())*(to.X()-from.X()))/float64(to.Y()-from.Y()))
+			}
+		}
+		sort.Float64s(crossings)
+
+		for i := 0; i+1 < len(crossings); i += 2 {
+			for x := int(math.Ceil(crossings[i])); x <= int(math.Floor(crossings[i+1])); x++ {
+				canvas.set(x, y, polygon.Filler())
+			}
+		}
+	}
+
+	for i := range points {
+		rasterLine(canvas, points[i], points[(i+1)%len(points)], polygon.Outline())
+	}
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func abs(value int) int {
+	if value < 0 {
+		return -value
+	}
+	return value
+}
+
+func addFill(canvas pen, fill domain.Fill) {
+	filler := canvas.with(fill.Style())
+	paint := paintWith(fill.Filler(), fill.Pattern(), fill.Point())
+	floodFill(canvas.grid, fill, func(span Span) {
+		for x := span.Left; x <= span.Right; x++ {
+			filler.set(x, span.Y, paint(x, span.Y))
+		}
+	})
+}
+
+// Span is a horizontal run of cells of a row, including both ends
+type Span struct {
+	Y     int
+	Left  int
+	Right int
+}
+
+// FillSpans returns the runs of cells that the fill would paint on top of what is already drawn in the grid, without
+// painting them
+func (g Grid) FillSpans(fill domain.Fill) []Span {
+	var spans []Span
+	floodFill(g, fill, func(span Span) {
+		spans = append(spans, span)
+	})
+	return spans
+}
+
+// floodFill visits the runs of cells covered by the fill. The visit can paint the cells, because the cells already
+// visited are tracked
+func floodFill(canvas Grid, fill domain.Fill, visit func(Span)) {
+	x, y := fill.Point().X(), fill.Point().Y()
+	if !canvas.Contains(x, y) {
+		return
+	}
+
+	old := canvas[y][x]
+	fillable := func(r rune) bool { return r == old }
+	if fill.Mode() == domain.BoundaryFillMode {
+		fillable = func(r rune) bool { return r != fill.Boundary() }
+	}
+
+	flood(canvas, fill.Point(), fillable, visit, fill.Connectivity() == domain.EightConnected)
+}
+
+// paintWith returns the rune to paint each cell of a shape that starts in the origin with, which is the filler unless
+// the shape has a pattern
+func paintWith(filler rune, pattern domain.Pattern, origin domain.Point) func(x, y int) rune {
+	if pattern.IsEmpty() {
+		return func(int, int) rune { return filler }
+	}
+	return func(x, y int) rune { return pattern.At(x, y, origin) }
+}
+
+// flood uses a scanline fill with an explicit stack of seeds. Each seed fills the whole horizontal run of cells it
+// belongs to and pushes a single seed per run found above and below it, so the memory used does not depend on the
+// call stack and the number of pending seeds stays small even for canvases with millions of cells. The cells already
+// filled are tracked, so the fill finishes even when the painted runes are fillable
+func flood(canvas Grid, point domain.Point, fillable func(rune) bool, visit func(Span), diagonals bool) {
+	filled := make([][]bool, len(canvas))
+	for i := range filled {
+		filled[i] = make([]bool, len(canvas[i]))
+	}
+	canFill := func(x, y int) bool {
+		return canvas.Contains(x, y) && !filled[y][x] && fillable(canvas[y][x])
+	}
+
+	seeds := []domain.Point{point}
+	for len(seeds) > 0 {
+		seed := seeds[len(seeds)-1]
+		seeds = seeds[:len(seeds)-1]
+
+		x, y := seed.X(), seed.Y()
+		if !canFill(x, y) {
+			continue
+		}
+
+		left, right := x, x
+		for canFill(left-1, y) {
+			left--
+		}
+		for canFill(right+1, y) {
+			right++
+		}
+		for i := left; i <= right; i++ {
+			filled[y][i] = true
+		}
+		visit(Span{Y: y, Left: left, Right: right})
+
+		// Diagonal neighbours of the run are the cells just outside of it in the rows above and below
+		if diagonals {
+			left, right = left-1, right+1
+		}
+		seeds = pushRuns(seeds, canFill, left, right, y-1)
+		seeds = pushRuns(seeds, canFill, left, right, y+1)
+	}
+}
+
+// pushRuns adds a seed for each run of fillable cells in the row y between left and right
+func pushRuns(seeds []domain.Point, canFill func(x, y int) bool, left, right, y int) []domain.Point {
+	inRun := false
+	for x := left; x <= right; x++ {
+		fillable := canFill(x, y)
+		if fillable && !inRun {
+			seeds = append(seeds, domain.NewPoint(x, y))
+		}
+		inRun = fillable
+	}
+	return seeds
+}
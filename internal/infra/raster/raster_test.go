@@ -0,0 +1,333 @@
+package raster_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/maitesin/sketch/internal/domain"
+	"github.com/maitesin/sketch/internal/infra/raster"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRasterize(t *testing.T) {
+	tests := []struct {
+		name         string
+		tasks        []domain.Task
+		expectedGrid raster.Grid
+		expectedErr  error
+	}{
+		{
+			name: `Given a canvas without tasks,
+                   when it is rasterized,
+                   then a blank grid with the size of the canvas is returned`,
+			expectedGrid: raster.Grid{[]rune("    "), []rune("    "), []rune("    ")},
+		},
+		{
+			name: `Given a canvas with a rectangle and a fill,
+                   when it is rasterized,
+                   then a grid with both tasks drawn in order is returned`,
+			tasks: []domain.Task{
+				domain.NewDrawRectangle(uuid.New(), domain.NewPoint(0, 0), 3, 3, ' ', '#', time.Now().UTC()),
+				domain.NewFill(uuid.New(), domain.NewPoint(1, 1), '.', time.Now().UTC()),
+			},
+			expectedGrid: raster.Grid{[]rune("### "), []rune("#.# "), []rune("### ")},
+		},
+		{
+			name: `Given a canvas with an ellipse with a negative height and an ellipse with a zero width,
+                   when it is rasterized,
+                   then a blank grid is returned`,
+			tasks: []domain.Task{
+				domain.NewDrawEllipse(uuid.New(), domain.NewPoint(0, 0), -5, 3, '.', 'O', time.Now().UTC()),
+				domain.NewDrawEllipse(uuid.New(), domain.NewPoint(1, 1), 2, 0, '.', 'O', time.Now().UTC()),
+			},
+			expectedGrid: raster.Grid{[]rune("    "), []rune("    "), []rune("    ")},
+		},
+		{
+			name: `Given a canvas with an invalid task,
+                   when it is rasterized,
+                   then an invalid task error is returned`,
+			tasks:       []domain.Task{"this will fail"},
+			expectedErr: raster.ErrInvalidTask,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			canvas := domain.NewCanvas(uuid.New(), 3, 4, tt.tasks, time.Now().UTC())
+
+			grid, err := raster.Rasterize(canvas, raster.FullViewport(canvas))
+			if tt.expectedErr != nil {
+				require.ErrorAs(t, err, &tt.expectedErr)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tt.expectedGrid, grid)
+			}
+		})
+	}
+}
+
+func TestGrid_FillSpans(t *testing.T) {
+	t.Parallel()
+
+	grid := raster.NewGrid(3, 4)
+	err := grid.Draw(domain.NewDrawLine(uuid.New(), domain.NewPoint(2, 0), domain.NewPoint(2, 2), '|', time.Now().UTC()))
+	require.NoError(t, err)
+
+	spans := grid.FillSpans(domain.NewFill(uuid.New(), domain.NewPoint(0, 1), '-', time.Now().UTC()))
+
+	require.ElementsMatch(t, []raster.Span{
+		{Y: 0, Left: 0, Right: 1},
+		{Y: 1, Left: 0, Right: 1},
+		{Y: 2, Left: 0, Right: 1},
+	}, spans)
+	require.Equal(t, raster.Grid{[]rune("  | "), []rune("  | "), []rune("  | ")}, grid)
+}
+
+func TestRasterizeWithStyles(t *testing.T) {
+	red := domain.NewStyle(domain.ColourRed, domain.DefaultColour)
+	blue := domain.NewStyle(domain.ColourWhite, domain.ColourBlue)
+	none := domain.Style{}
+
+	tests := []struct {
+		name           string
+		tasks          []domain.Task
+		expectedGrid   raster.Grid
+		expectedStyles raster.Styles
+		expectedErr    error
+	}{
+		{
+			name: `Given a canvas with a rectangle with different styles for the outline and the filler,
+                   when it is rasterized with styles,
+                   then each cell of the rectangle has the style of the part it belongs to`,
+			tasks: []domain.Task{
+				domain.NewDrawRectangle(uuid.New(), domain.NewPoint(0, 0), 3, 3, '.', '#', time.Now().UTC()).
+					WithStyles(blue, red),
+			},
+			expectedGrid: raster.Grid{[]rune("### "), []rune("#.# "), []rune("### ")},
+			expectedStyles: raster.Styles{
+				{red, red, red, none},
+				{red, blue, red, none},
+				{red, red, red, none},
+			},
+		},
+		{
+			name: `Given a canvas with a styled fill and a line without colours on top of it,
+                   when it is rasterized with styles,
+                   then the cells of the line go back to the default style`,
+			tasks: []domain.Task{
+				domain.NewFill(uuid.New(), domain.NewPoint(0, 0), '~', time.Now().UTC()).WithStyle(blue),
+				domain.NewDrawLine(uuid.New(), domain.NewPoint(0, 1), domain.NewPoint(3, 1), '-', time.Now().UTC()),
+			},
+			expectedGrid: raster.Grid{[]rune("~~~~"), []rune("----"), []rune("~~~~")},
+			expectedStyles: raster.Styles{
+				{blue, blue, blue, blue},
+				{none, none, none, none},
+				{blue, blue, blue, blue},
+			},
+		},
+		{
+			name: `Given a canvas with an invalid task,
+                   when it is rasterized with styles,
+                   then an invalid task error is returned`,
+			tasks:       []domain.Task{"this will fail"},
+			expectedErr: raster.ErrInvalidTask,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			canvas := domain.NewCanvas(uuid.New(), 3, 4, tt.tasks, time.Now().UTC())
+
+			grid, styles, err := raster.RasterizeWithStyles(canvas, raster.FullViewport(canvas))
+			if tt.expectedErr != nil {
+				require.ErrorAs(t, err, &tt.expectedErr)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tt.expectedGrid, grid)
+				require.Equal(t, tt.expectedStyles, styles)
+
+				plain, err := raster.Rasterize(canvas, raster.FullViewport(canvas))
+				require.NoError(t, err)
+				require.Equal(t, plain, grid)
+			}
+		})
+	}
+}
+
+func TestRasterizeWithOwners(t *testing.T) {
+	t.Parallel()
+
+	rectangle := domain.NewDrawRectangle(uuid.New(), domain.NewPoint(0, 0), 3, 3, ' ', '#', time.Now().UTC())
+	line := domain.NewDrawLine(uuid.New(), domain.NewPoint(1, 1), domain.NewPoint(3, 1), '-', time.Now().UTC())
+	canvas := domain.NewCanvas(uuid.New(), 3, 4, []domain.Task{rectangle, line}, time.Now().UTC())
+
+	grid, owners, err := raster.RasterizeWithOwners(canvas, raster.FullViewport(canvas))
+	require.NoError(t, err)
+	require.Equal(t, raster.Grid{[]rune("### "), []rune("#---"), []rune("### ")}, grid)
+
+	r, l, n := rectangle.ID(), line.ID(), uuid.Nil
+	require.Equal(t, raster.Owners{
+		{r, r, r, n},
+		{r, l, l, l},
+		{r, r, r, n},
+	}, owners)
+
+	invalid := domain.NewCanvas(uuid.New(), 1, 1, []domain.Task{"this will fail"}, time.Now().UTC())
+	_, _, err = raster.RasterizeWithOwners(invalid, raster.FullViewport(invalid))
+	require.ErrorIs(t, err, raster.ErrInvalidTask)
+}
+
+func TestRasterize_ViewportWithFill(t *testing.T) {
+	t.Parallel()
+
+	// the fill floods cells outside of the viewport, and the tasks after it cross the edges of the viewport
+	wall := domain.NewDrawLine(uuid.New(), domain.NewPoint(3, 0), domain.NewPoint(3, 5), '|', time.Now().UTC())
+	fill := domain.NewFill(uuid.New(), domain.NewPoint(0, 0), '~', time.Now().UTC())
+	rectangle := domain.NewDrawRectangle(uuid.New(), domain.NewPoint(1, 1), 4, 6, '.', '#', time.Now().UTC())
+	line := domain.NewDrawLine(uuid.New(), domain.NewPoint(0, 4), domain.NewPoint(7, 4), '-', time.Now().UTC())
+	canvas := domain.NewCanvas(uuid.New(), 6, 8, []domain.Task{wall, fill, rectangle, line}, time.Now().UTC())
+	viewport := raster.Viewport{X: 0, Y: 3, Width: 5, Height: 3}
+
+	full, styles, err := raster.RasterizeWithStyles(canvas, raster.FullViewport(canvas))
+	require.NoError(t, err)
+	_, owners, err := raster.RasterizeWithOwners(canvas, raster.FullViewport(canvas))
+	require.NoError(t, err)
+
+	grid, err := raster.Rasterize(canvas, viewport)
+	require.NoError(t, err)
+	require.Equal(t, raster.Grid{[]rune("~#..."), []rune("-----"), []rune("~~~| ")}, grid)
+
+	_, viewportStyles, err := raster.RasterizeWithStyles(canvas, viewport)
+	require.NoError(t, err)
+	_, viewportOwners, err := raster.RasterizeWithOwners(canvas, viewport)
+	require.NoError(t, err)
+	for y := 0; y < viewport.Height; y++ {
+		require.Equal(t, full[viewport.Y+y][viewport.X:viewport.X+viewport.Width], grid[y])
+		require.Equal(t, styles[viewport.Y+y][viewport.X:viewport.X+viewport.Width], viewportStyles[y])
+		require.Equal(t, owners[viewport.Y+y][viewport.X:viewport.X+viewport.Width], viewportOwners[y])
+	}
+}
+
+func TestViewport_Validate(t *testing.T) {
+	canvas := domain.NewCanvas(uuid.New(), 3, 4, nil, time.Now().UTC())
+
+	tests := []struct {
+		name        string
+		viewport    raster.Viewport
+		expectedErr error
+	}{
+		{
+			name: `Given a viewport with the whole canvas,
+                   when it is validated,
+                   then no error is returned`,
+			viewport: raster.FullViewport(canvas),
+		},
+		{
+			name: `Given a viewport with a single cell in the bottom right corner of the canvas,
+                   when it is validated,
+                   then no error is returned`,
+			viewport: raster.Viewport{X: 3, Y: 2, Width: 1, Height: 1},
+		},
+		{
+			name: `Given a viewport that starts at a negative column,
+                   when it is validated,
+                   then an invalid viewport error is returned`,
+			viewport:    raster.Viewport{X: -1, Y: 0, Width: 2, Height: 2},
+			expectedErr: raster.ErrInvalidViewport,
+		},
+		{
+			name: `Given a viewport without width,
+                   when it is validated,
+                   then an invalid viewport error is returned`,
+			viewport:    raster.Viewport{X: 0, Y: 0, Width: 0, Height: 2},
+			expectedErr: raster.ErrInvalidViewport,
+		},
+		{
+			name: `Given a viewport that goes past the bottom of the canvas,
+                   when it is validated,
+                   then an invalid viewport error is returned`,
+			viewport:    raster.Viewport{X: 0, Y: 1, Width: 4, Height: 3},
+			expectedErr: raster.ErrInvalidViewport,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tt.viewport.Validate(canvas)
+			if tt.expectedErr != nil {
+				require.ErrorIs(t, err, tt.expectedErr)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestRasterize_Viewport(t *testing.T) {
+	rectangle := domain.NewDrawRectangle(uuid.New(), domain.NewPoint(1, 1), 4, 6, '.', '#', time.Now().UTC()).
+		WithStyles(domain.NewStyle(domain.ColourBlue, domain.DefaultColour), domain.Style{})
+	line := domain.NewDrawLine(uuid.New(), domain.NewPoint(0, 5), domain.NewPoint(7, 0), '*', time.Now().UTC())
+	fill := domain.NewFill(uuid.New(), domain.NewPoint(7, 5), '~', time.Now().UTC())
+
+	tests := []struct {
+		name  string
+		tasks []domain.Task
+	}{
+		{
+			name: `Given a canvas without fills,
+                   when a window of it is rasterized,
+                   then only the cells of the window are drawn, and they are the same ones as in the whole canvas`,
+			tasks: []domain.Task{rectangle, line},
+		},
+		{
+			name: `Given a canvas with a fill that spreads outside of the window,
+                   when a window of it is rasterized,
+                   then the cells of the window are the same ones as in the whole canvas`,
+			tasks: []domain.Task{rectangle, line, fill},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			canvas := domain.NewCanvas(uuid.New(), 6, 8, tt.tasks, time.Now().UTC())
+			full, fullStyles, err := raster.RasterizeWithStyles(canvas, raster.FullViewport(canvas))
+			require.NoError(t, err)
+			_, fullOwners, err := raster.RasterizeWithOwners(canvas, raster.FullViewport(canvas))
+			require.NoError(t, err)
+
+			for _, viewport := range []raster.Viewport{
+				{X: 0, Y: 0, Width: 8, Height: 6},
+				{X: 2, Y: 1, Width: 3, Height: 4},
+				{X: 7, Y: 5, Width: 1, Height: 1},
+				{X: 0, Y: 3, Width: 8, Height: 2},
+			} {
+				grid, err := raster.Rasterize(canvas, viewport)
+				require.NoError(t, err)
+				_, styles, err := raster.RasterizeWithStyles(canvas, viewport)
+				require.NoError(t, err)
+				_, owners, err := raster.RasterizeWithOwners(canvas, viewport)
+				require.NoError(t, err)
+
+				require.Len(t, grid, viewport.Height)
+				for y := range grid {
+					fromY, fromX, toX := viewport.Y+y, viewport.X, viewport.X+viewport.Width
+					require.Equal(t, full[fromY][fromX:toX], grid[y])
+					require.Equal(t, fullStyles[fromY][fromX:toX], styles[y])
+					require.Equal(t, fullOwners[fromY][fromX:toX], owners[y])
+				}
+			}
+
+			_, err = raster.Rasterize(canvas, raster.Viewport{X: 7, Y: 5, Width: 2, Height: 1})
+			require.ErrorIs(t, err, raster.ErrInvalidViewport)
+		})
+	}
+}
@@ -0,0 +1,246 @@
+package raster
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+
+	"github.com/google/uuid"
+	"github.com/maitesin/sketch/internal/domain"
+)
+
+// Snapshot is the raster of a canvas after drawing its first tasks. It is a task itself, so a canvas where those tasks
+// are replaced by the snapshot is drawn in the same way without drawing them again. The digest identifies the tasks
+// drawn, so the snapshot is not used once any of them is removed or edited
+type Snapshot struct {
+	tasks  int
+	digest string
+	grid   Grid
+	styles Styles
+	owners Owners
+}
+
+// Tasks returns the number of tasks drawn in the snapshot
+func (s Snapshot) Tasks() int {
+	return s.tasks
+}
+
+// Digest returns the digest of the tasks drawn in the snapshot
+func (s Snapshot) Digest() string {
+	return s.digest
+}
+
+// Grid returns the runes of the cells of the snapshot
+func (s Snapshot) Grid() Grid {
+	return s.grid
+}
+
+// Styles returns the styles of the cells of the snapshot
+func (s Snapshot) Styles() Styles {
+	return s.styles
+}
+
+// Owners returns the tasks that drew each cell of the snapshot last
+func (s Snapshot) Owners() Owners {
+	return s.owners
+}
+
+// NewSnapshot draws all the tasks performed in the canvas, which can start with another snapshot
+func NewSnapshot(canvas domain.Canvas) (Snapshot, error) {
+	p, err := rasterize(canvas, FullViewport(canvas), true, true)
+	if err != nil {
+		return Snapshot{}, err
+	}
+
+	tasks, digest := digestOf(canvas.Tasks())
+	return Snapshot{
+		tasks:  tasks,
+		digest: digest,
+		grid:   p.grid,
+		styles: p.styles,
+		owners: p.owners,
+	}, nil
+}
+
+// RestoreSnapshot is a constructor for a snapshot that has been stored
+func RestoreSnapshot(tasks int, digest string, grid Grid, styles Styles, owners Owners) Snapshot {
+	return Snapshot{
+		tasks:  tasks,
+		digest: digest,
+		grid:   grid,
+		styles: styles,
+		owners: owners,
+	}
+}
+
+// Resume returns a canvas with the tasks performed in the canvas, where the ones drawn in the snapshot are replaced by
+// the snapshot. It returns false when the snapshot does not match the canvas anymore, e.g. because the canvas has been
+// resized, or any of the tasks drawn in the snapshot has been removed, edited, or undone
+func (s Snapshot) Resume(canvas domain.Canvas) (domain.Canvas, bool) {
+	tasks := canvas.Tasks()
+	if s.tasks > len(tasks) || len(s.grid) != canvas.Height() ||
+		(len(s.grid) > 0 && len(s.grid[0]) != canvas.Width()) {
+		return domain.Canvas{}, false
+	}
+
+	if _, digest := digestOf(tasks[:s.tasks]); digest != s.digest {
+		return domain.Canvas{}, false
+	}
+
+	return s.Replace(canvas, s.tasks), true
+}
+
+// Replace returns a canvas where the first drawn tasks performed in the canvas are replaced by the snapshot, without
+// checking that they are the ones drawn in it
+func (s Snapshot) Replace(canvas domain.Canvas, drawn int) domain.Canvas {
+	tasks := canvas.Tasks()
+	resumed := make([]domain.Task, 0, len(tasks)-drawn+1)
+	resumed = append(resumed, s)
+	resumed = append(resumed, tasks[drawn:]...)
+	return domain.NewCanvas(canvas.ID(), canvas.Height(), canvas.Width(), resumed, canvas.CreatedAt())
+}
+
+func drawSnapshot(canvas pen, snapshot Snapshot) {
+	height := len(snapshot.grid)
+	if height == 0 {
+		return
+	}
+
+	left, top, right, bottom := canvas.clip(0, 0, len(snapshot.grid[0])-1, height-1)
+	for y := top; y <= bottom; y++ {
+		for x := left; x <= right; x++ {
+			cell := canvas
+			if snapshot.styles != nil {
+				cell.style = snapshot.styles[y][x]
+			}
+			if snapshot.owners != nil {
+				cell.owner = snapshot.owners[y][x]
+			}
+			cell.set(x, y, snapshot.grid[y][x])
+		}
+	}
+}
+
+// digestOf returns the number of tasks and a digest of them, computed as a chain where each task is hashed along with
+// the digest of the tasks before it. A snapshot continues the chain from its own digest, so the digest of a canvas
+// where some tasks have been replaced by a snapshot is the same one as the digest of the original canvas
+func digestOf(tasks []domain.Task) (int, string) {
+	var count int
+	var digest []byte
+	w := taskWriter{hash: sha256.New()}
+	for _, task := range tasks {
+		if snapshot, ok := task.(Snapshot); ok {
+			count = snapshot.tasks
+			digest, _ = hex.DecodeString(snapshot.digest)
+			continue
+		}
+
+		w.hash.Reset()
+		w.hash.Write(digest) //nolint: errcheck
+		w.task(task)
+		count, digest = count+1, w.hash.Sum(digest[:0])
+	}
+	return count, hex.EncodeToString(digest)
+}
+
+// taskWriter writes the fields of the tasks that change how they are drawn in a hash. The creation time is left out,
+// since the tasks keep it when they are edited
+type taskWriter struct {
+	hash    hash.Hash
+	scratch [binary.MaxVarintLen64]byte
+}
+
+func (w *taskWriter) task(task domain.Task) {
+	switch task := task.(type) {
+	case domain.DrawRectangle:
+		w.string("rectangle")
+		w.id(task.ID())
+		w.point(task.Point())
+		w.ints(task.Height(), task.Width(), int(task.Filler()), int(task.Outline()))
+		w.pattern(task.Pattern())
+		w.style(task.FillerStyle())
+		w.style(task.OutlineStyle())
+	case domain.Fill:
+		w.string("fill")
+		w.id(task.ID())
+		w.point(task.Point())
+		w.ints(int(task.Filler()), int(task.Connectivity()), int(task.Boundary()))
+		w.string(string(task.Mode()))
+		w.pattern(task.Pattern())
+		w.style(task.Style())
+	case domain.DrawLine:
+		w.string("line")
+		w.id(task.ID())
+		w.point(task.From())
+		w.point(task.To())
+		w.ints(int(task.Outline()))
+	case domain.DrawEllipse:
+		w.string("ellipse")
+		w.id(task.ID())
+		w.point(task.Point())
+		w.ints(task.Height(), task.Width(), int(task.Filler()), int(task.Outline()))
+	case domain.DrawText:
+		w.string("text")
+		w.id(task.ID())
+		w.point(task.Point())
+		w.string(task.Text())
+		w.ints(task.WrapWidth())
+		w.string(string(task.Alignment()))
+	case domain.DrawPolyline:
+		w.string("polyline")
+		w.id(task.ID())
+		w.points(task.Points())
+		w.ints(int(task.Outline()))
+	case domain.DrawPolygon:
+		w.string("polygon")
+		w.id(task.ID())
+		w.points(task.Points())
+		w.ints(int(task.Filler()), int(task.Outline()))
+	default:
+		w.string(fmt.Sprintf("%T %v", task, task))
+	}
+}
+
+func (w *taskWriter) ints(values ...int) {
+	for _, value := range values {
+		n := binary.PutVarint(w.scratch[:], int64(value))
+		w.hash.Write(w.scratch[:n]) //nolint: errcheck
+	}
+}
+
+// string writes the length of the string before it, so consecutive strings cannot be confused
+func (w *taskWriter) string(value string) {
+	w.ints(len(value))
+	io.WriteString(w.hash, value) //nolint: errcheck
+}
+
+func (w *taskWriter) id(id uuid.UUID) {
+	w.hash.Write(id[:]) //nolint: errcheck
+}
+
+func (w *taskWriter) point(point domain.Point) {
+	w.ints(point.X(), point.Y())
+}
+
+func (w *taskWriter) points(points []domain.Point) {
+	w.ints(len(points))
+	for _, point := range points {
+		w.point(point)
+	}
+}
+
+func (w *taskWriter) pattern(pattern domain.Pattern) {
+	w.ints(len(pattern.Tile()))
+	for _, row := range pattern.Tile() {
+		w.string(row)
+	}
+	w.string(string(pattern.Anchor()))
+}
+
+func (w *taskWriter) style(style domain.Style) {
+	w.string(string(style.Foreground()))
+	w.string(string(style.Background()))
+}
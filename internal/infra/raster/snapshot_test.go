@@ -0,0 +1,160 @@
+package raster_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/maitesin/sketch/internal/domain"
+	"github.com/maitesin/sketch/internal/infra/raster"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	snapshotRectangleID = uuid.New()
+	snapshotLineID      = uuid.New()
+	snapshotFillID      = uuid.New()
+	snapshotTextID      = uuid.New()
+)
+
+// snapshotCanvas returns a canvas with four tasks, where the first two ones are the ones drawn in the snapshots
+func snapshotCanvas() domain.Canvas {
+	red := domain.NewStyle(domain.ColourRed, domain.DefaultColour)
+	return domain.NewCanvas(uuid.New(), 5, 8, []domain.Task{
+		domain.NewDrawRectangle(snapshotRectangleID, domain.NewPoint(0, 0), 4, 5, '.', '#', time.Now().UTC()).
+			WithStyles(red, red),
+		domain.NewDrawLine(snapshotLineID, domain.NewPoint(0, 4), domain.NewPoint(7, 0), '/', time.Now().UTC()),
+		domain.NewFill(snapshotFillID, domain.NewPoint(7, 4), '~', time.Now().UTC()),
+		domain.NewDrawText(snapshotTextID, domain.NewPoint(1, 2), "hi", 0, domain.AlignLeft, time.Now().UTC()),
+	}, time.Now().UTC())
+}
+
+func prefix(canvas domain.Canvas, tasks int) domain.Canvas {
+	return domain.NewCanvas(canvas.ID(), canvas.Height(), canvas.Width(), canvas.Tasks()[:tasks], canvas.CreatedAt())
+}
+
+func TestSnapshot_Resume(t *testing.T) {
+	tests := []struct {
+		name          string
+		mutator       func(t *testing.T, canvas *domain.Canvas)
+		expectedFound bool
+	}{
+		{
+			name: `Given a snapshot of the first tasks of a canvas that has not changed,
+                   when the canvas is resumed from the snapshot,
+                   then the canvas is drawn in the same way`,
+			mutator:       func(*testing.T, *domain.Canvas) {},
+			expectedFound: true,
+		},
+		{
+			name: `Given a snapshot of the first tasks of a canvas where a task has been added,
+                   when the canvas is resumed from the snapshot,
+                   then the canvas is drawn in the same way`,
+			mutator: func(t *testing.T, canvas *domain.Canvas) {
+				err := canvas.AddDrawLine(domain.NewDrawLine(uuid.New(), domain.NewPoint(0, 0), domain.NewPoint(7, 4), '\\', time.Now().UTC()))
+				require.NoError(t, err)
+			},
+			expectedFound: true,
+		},
+		{
+			name: `Given a snapshot of the first tasks of a canvas where a task that is not in the snapshot has been removed,
+                   when the canvas is resumed from the snapshot,
+                   then the canvas is drawn in the same way`,
+			mutator: func(t *testing.T, canvas *domain.Canvas) {
+				require.NoError(t, canvas.RemoveTask(snapshotFillID))
+			},
+			expectedFound: true,
+		},
+		{
+			name: `Given a snapshot of the first tasks of a canvas where a task in the snapshot has been removed,
+                   when the canvas is resumed from the snapshot,
+                   then the snapshot is not used`,
+			mutator: func(t *testing.T, canvas *domain.Canvas) {
+				require.NoError(t, canvas.RemoveTask(snapshotLineID))
+			},
+		},
+		{
+			name: `Given a snapshot of the first tasks of a canvas where a task in the snapshot has been edited,
+                   when the canvas is resumed from the snapshot,
+                   then the snapshot is not used`,
+			mutator: func(t *testing.T, canvas *domain.Canvas) {
+				err := canvas.UpdateTask(domain.NewDrawRectangle(snapshotRectangleID, domain.NewPoint(0, 0), 4, 5, '.', '@', time.Now().UTC()))
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: `Given a snapshot of the first tasks of a canvas where a task in the snapshot has been undone,
+                   when the canvas is resumed from the snapshot,
+                   then the snapshot is not used`,
+			mutator: func(t *testing.T, canvas *domain.Canvas) {
+				for i := 0; i < 3; i++ {
+					require.NoError(t, canvas.Undo())
+				}
+			},
+		},
+		{
+			name: `Given a snapshot of the first tasks of a canvas that has been resized,
+                   when the canvas is resumed from the snapshot,
+                   then the snapshot is not used`,
+			mutator: func(t *testing.T, canvas *domain.Canvas) {
+				require.NoError(t, canvas.Resize(6, 8, domain.AnchorTopLeft, false))
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			canvas := snapshotCanvas()
+			snapshot, err := raster.NewSnapshot(prefix(canvas, 2))
+			require.NoError(t, err)
+			require.Equal(t, 2, snapshot.Tasks())
+
+			tt.mutator(t, &canvas)
+
+			resumed, found := snapshot.Resume(canvas)
+			require.Equal(t, tt.expectedFound, found)
+			if !found {
+				return
+			}
+			require.Len(t, resumed.Tasks(), len(canvas.Tasks())-1)
+
+			for _, viewport := range []raster.Viewport{raster.FullViewport(canvas), {X: 2, Y: 1, Width: 4, Height: 2}} {
+				expectedGrid, expectedStyles, err := raster.RasterizeWithStyles(canvas, viewport)
+				require.NoError(t, err)
+				grid, styles, err := raster.RasterizeWithStyles(resumed, viewport)
+				require.NoError(t, err)
+				require.Equal(t, expectedGrid, grid)
+				require.Equal(t, expectedStyles, styles)
+
+				_, expectedOwners, err := raster.RasterizeWithOwners(canvas, viewport)
+				require.NoError(t, err)
+				_, owners, err := raster.RasterizeWithOwners(resumed, viewport)
+				require.NoError(t, err)
+				require.Equal(t, expectedOwners, owners)
+			}
+		})
+	}
+}
+
+func TestNewSnapshot_FromSnapshot(t *testing.T) {
+	t.Parallel()
+
+	canvas := snapshotCanvas()
+	snapshot, err := raster.NewSnapshot(prefix(canvas, 2))
+	require.NoError(t, err)
+
+	resumed, found := snapshot.Resume(canvas)
+	require.True(t, found)
+
+	fromSnapshot, err := raster.NewSnapshot(prefix(resumed, 2))
+	require.NoError(t, err)
+	fromTasks, err := raster.NewSnapshot(prefix(canvas, 3))
+	require.NoError(t, err)
+	require.Equal(t, fromTasks, fromSnapshot)
+
+	restored := raster.RestoreSnapshot(fromSnapshot.Tasks(), fromSnapshot.Digest(), fromSnapshot.Grid(), fromSnapshot.Styles(), fromSnapshot.Owners())
+	_, found = restored.Resume(canvas)
+	require.True(t, found)
+}
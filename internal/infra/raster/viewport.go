@@ -0,0 +1,30 @@
+package raster
+
+import (
+	"fmt"
+
+	"github.com/maitesin/sketch/internal/domain"
+)
+
+// Viewport is the window of a canvas that is rendered, starting in the cell in X and Y
+type Viewport struct {
+	X      int
+	Y      int
+	Width  int
+	Height int
+}
+
+// FullViewport returns the viewport that contains the whole canvas
+func FullViewport(canvas domain.Canvas) Viewport {
+	return Viewport{Width: canvas.Width(), Height: canvas.Height()}
+}
+
+// Validate returns an error when the viewport is empty or it is not inside of the canvas
+func (v Viewport) Validate(canvas domain.Canvas) error {
+	if v.X < 0 || v.Y < 0 || v.Width <= 0 || v.Height <= 0 ||
+		v.X+v.Width > canvas.Width() || v.Y+v.Height > canvas.Height() {
+		return fmt.Errorf("%w: %dx%d from %dx%d does not fit in a canvas of %dx%d",
+			ErrInvalidViewport, v.Width, v.Height, v.X, v.Y, canvas.Width(), canvas.Height())
+	}
+	return nil
+}
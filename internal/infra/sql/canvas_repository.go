@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -16,38 +17,165 @@ const (
 	canvasTable     = "canvases"
 	rectanglesTable = "rectangles"
 	fillsTable      = "fills"
+	linesTable      = "lines"
+	ellipsesTable   = "ellipses"
+	textsTable      = "texts"
+	polylinesTable  = "polylines"
+	polygonsTable   = "polygons"
 )
 
+// maxRowsPerStatement is the number of rows inserted, or IDs deleted, by a single statement. Tables have up to 15 columns
+const maxRowsPerStatement = 1000
+
+var tasksTables = []string{
+	rectanglesTable,
+	fillsTable,
+	linesTable,
+	ellipsesTable,
+	textsTable,
+	polylinesTable,
+	polygonsTable,
+}
+
 func onConflictDoNothing(queryIn string) string {
 	return queryIn + `ON CONFLICT DO NOTHING`
 }
 
+// onConflictUpdate returns an amendment that turns an insert into an upsert, updating the given columns of the rows
+// that already exist
+func onConflictUpdate(columns []string) func(string) string {
+	return func(queryIn string) string {
+		set := make([]string, len(columns))
+		for i, column := range columns {
+			set[i] = fmt.Sprintf("%s = EXCLUDED.%s", column, column)
+		}
+		return queryIn + ` ON CONFLICT (id) DO UPDATE SET ` + strings.Join(set, ", ")
+	}
+}
+
+// updatableColumns contains the columns of each task table that can change once the task has been created
+var updatableColumns = map[string][]string{
+	rectanglesTable: {"x", "y", "height", "width", "filler", "outline", "pattern", "filler_foreground", "filler_background", "outline_foreground", "outline_background"},
+	fillsTable:      {"x", "y", "filler", "connectivity", "mode", "boundary", "pattern", "foreground", "background"},
+	linesTable:      {"from_x", "from_y", "to_x", "to_y", "outline"},
+	ellipsesTable:   {"x", "y", "height", "width", "filler", "outline"},
+	textsTable:      {"x", "y", "text", "wrap_width", "alignment"},
+	polylinesTable:  {"points", "outline"},
+	polygonsTable:   {"points", "filler", "outline"},
+}
+
 type Canvas struct {
 	ID        uuid.UUID `db:"id"`
 	Height    int       `db:"height"`
 	Width     int       `db:"width"`
+	Undone    int       `db:"undone"`
+	Sequence  int       `db:"sequence"`
 	CreatedAt time.Time `db:"created_at"`
 }
 
 type Rectangle struct {
-	ID        uuid.UUID `db:"id"`
-	CanvasID  uuid.UUID `db:"canvas_id"`
-	X         int       `db:"x"`
-	Y         int       `db:"y"`
-	Height    int       `db:"height"`
-	Width     int       `db:"width"`
-	Filler    rune      `db:"filler"`
-	Outline   rune      `db:"outline"`
-	CreatedAt time.Time `db:"created_at"`
+	ID                uuid.UUID `db:"id" json:"id"`
+	CanvasID          uuid.UUID `db:"canvas_id" json:"canvas_id"`
+	X                 int       `db:"x" json:"x"`
+	Y                 int       `db:"y" json:"y"`
+	Height            int       `db:"height" json:"height"`
+	Width             int       `db:"width" json:"width"`
+	Filler            rune      `db:"filler" json:"filler"`
+	Outline           rune      `db:"outline" json:"outline"`
+	Pattern           Pattern   `db:"pattern" json:"pattern"`
+	FillerForeground  string    `db:"filler_foreground" json:"filler_foreground"`
+	FillerBackground  string    `db:"filler_background" json:"filler_background"`
+	OutlineForeground string    `db:"outline_foreground" json:"outline_foreground"`
+	OutlineBackground string    `db:"outline_background" json:"outline_background"`
+	Sequence          int       `db:"sequence" json:"sequence"`
+	CreatedAt         time.Time `db:"created_at" json:"created_at"`
 }
 
 type Fill struct {
-	ID        uuid.UUID `db:"id"`
-	CanvasID  uuid.UUID `db:"canvas_id"`
-	X         int       `db:"x"`
-	Y         int       `db:"y"`
-	Filler    rune      `db:"filler"`
-	CreatedAt time.Time `db:"created_at"`
+	ID           uuid.UUID `db:"id" json:"id"`
+	CanvasID     uuid.UUID `db:"canvas_id" json:"canvas_id"`
+	X            int       `db:"x" json:"x"`
+	Y            int       `db:"y" json:"y"`
+	Filler       rune      `db:"filler" json:"filler"`
+	Connectivity int       `db:"connectivity" json:"connectivity"`
+	Mode         string    `db:"mode" json:"mode"`
+	Boundary     rune      `db:"boundary" json:"boundary"`
+	Pattern      Pattern   `db:"pattern" json:"pattern"`
+	Foreground   string    `db:"foreground" json:"foreground"`
+	Background   string    `db:"background" json:"background"`
+	Sequence     int       `db:"sequence" json:"sequence"`
+	CreatedAt    time.Time `db:"created_at" json:"created_at"`
+}
+
+type Line struct {
+	ID        uuid.UUID `db:"id" json:"id"`
+	CanvasID  uuid.UUID `db:"canvas_id" json:"canvas_id"`
+	FromX     int       `db:"from_x" json:"from_x"`
+	FromY     int       `db:"from_y" json:"from_y"`
+	ToX       int       `db:"to_x" json:"to_x"`
+	ToY       int       `db:"to_y" json:"to_y"`
+	Outline   rune      `db:"outline" json:"outline"`
+	Sequence  int       `db:"sequence" json:"sequence"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+}
+
+type Ellipse struct {
+	ID        uuid.UUID `db:"id" json:"id"`
+	CanvasID  uuid.UUID `db:"canvas_id" json:"canvas_id"`
+	X         int       `db:"x" json:"x"`
+	Y         int       `db:"y" json:"y"`
+	Height    int       `db:"height" json:"height"`
+	Width     int       `db:"width" json:"width"`
+	Filler    rune      `db:"filler" json:"filler"`
+	Outline   rune      `db:"outline" json:"outline"`
+	Sequence  int       `db:"sequence" json:"sequence"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+}
+
+type Text struct {
+	ID        uuid.UUID `db:"id" json:"id"`
+	CanvasID  uuid.UUID `db:"canvas_id" json:"canvas_id"`
+	X         int       `db:"x" json:"x"`
+	Y         int       `db:"y" json:"y"`
+	Text      string    `db:"text" json:"text"`
+	WrapWidth int       `db:"wrap_width" json:"wrap_width"`
+	Alignment string    `db:"alignment" json:"alignment"`
+	Sequence  int       `db:"sequence" json:"sequence"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+}
+
+type Polyline struct {
+	ID        uuid.UUID `db:"id" json:"id"`
+	CanvasID  uuid.UUID `db:"canvas_id" json:"canvas_id"`
+	Points    Points    `db:"points" json:"points"`
+	Outline   rune      `db:"outline" json:"outline"`
+	Sequence  int       `db:"sequence" json:"sequence"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+}
+
+type Polygon struct {
+	ID        uuid.UUID `db:"id" json:"id"`
+	CanvasID  uuid.UUID `db:"canvas_id" json:"canvas_id"`
+	Points    Points    `db:"points" json:"points"`
+	Filler    rune      `db:"filler" json:"filler"`
+	Outline   rune      `db:"outline" json:"outline"`
+	Sequence  int       `db:"sequence" json:"sequence"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+}
+
+type tasks struct {
+	rectangles []Rectangle
+	fills      []Fill
+	lines      []Line
+	ellipses   []Ellipse
+	texts      []Text
+	polylines  []Polyline
+	polygons   []Polygon
+}
+
+func (t tasks) len() int {
+	return len(t.rectangles) + len(t.fills) + len(t.lines) + len(t.ellipses) + len(t.texts) +
+		len(t.polylines) + len(t.polygons)
 }
 
 type CanvasRepository struct {
@@ -59,10 +187,12 @@ func NewCanvasRepository(sess db.Session) *CanvasRepository {
 }
 
 func (c *CanvasRepository) Insert(ctx context.Context, canvas domain.Canvas) error {
-	sqlCanvas, _, _, err := domainToSQL(canvas)
+	sqlCanvas, _, err := domainToSQL(canvas)
 	if err != nil {
 		return err
 	}
+	// the sequence is stored along with the tasks of the canvas, by Update
+	sqlCanvas.Sequence = 0
 
 	_, err = c.sess.WithContext(ctx).
 		SQL().
@@ -73,83 +203,220 @@ func (c *CanvasRepository) Insert(ctx context.Context, canvas domain.Canvas) err
 	return err
 }
 
-func domainToSQL(canvas domain.Canvas) (Canvas, []Rectangle, []Fill, error) {
-	var rectangles []Rectangle
-	var fills []Fill
+func domainToSQL(canvas domain.Canvas) (Canvas, tasks, error) {
+	var sqlTasks tasks
 
-	for _, task := range canvas.Tasks() {
-		rectangle, ok := task.(domain.DrawRectangle)
-		if !ok {
-			fill, ok := task.(domain.Fill)
-			if !ok {
-				return Canvas{}, nil, nil, fmt.Errorf("failed to convert domain to sql task: %#v", task)
-			}
-			fills = append(fills, Fill{
-				ID:        fill.ID(),
-				CanvasID:  canvas.ID(),
-				X:         fill.Point().X(),
-				Y:         fill.Point().Y(),
-				Filler:    fill.Filler(),
-				CreatedAt: fill.CreatedAt(),
-			})
-			continue
+	for _, task := range canvas.History() {
+		sqlTask, err := domainToSQLTask(canvas.ID(), task)
+		if err != nil {
+			return Canvas{}, tasks{}, err
+		}
+
+		switch sqlTask := sqlTask.(type) {
+		case Rectangle:
+			sqlTasks.rectangles = append(sqlTasks.rectangles, sqlTask)
+		case Fill:
+			sqlTasks.fills = append(sqlTasks.fills, sqlTask)
+		case Line:
+			sqlTasks.lines = append(sqlTasks.lines, sqlTask)
+		case Ellipse:
+			sqlTasks.ellipses = append(sqlTasks.ellipses, sqlTask)
+		case Text:
+			sqlTasks.texts = append(sqlTasks.texts, sqlTask)
+		case Polyline:
+			sqlTasks.polylines = append(sqlTasks.polylines, sqlTask)
+		case Polygon:
+			sqlTasks.polygons = append(sqlTasks.polygons, sqlTask)
 		}
-		rectangles = append(rectangles, Rectangle{
-			ID:        rectangle.ID(),
-			CanvasID:  canvas.ID(),
-			X:         rectangle.Point().X(),
-			Y:         rectangle.Point().Y(),
-			Height:    rectangle.Height(),
-			Width:     rectangle.Width(),
-			Filler:    rectangle.Filler(),
-			Outline:   rectangle.Outline(),
-			CreatedAt: rectangle.CreatedAt(),
-		})
 	}
 
 	return Canvas{
 		ID:        canvas.ID(),
 		Height:    canvas.Height(),
 		Width:     canvas.Width(),
+		Undone:    len(canvas.History()) - canvas.Cursor(),
+		Sequence:  canvas.Sequence(),
 		CreatedAt: canvas.CreatedAt(),
-	}, rectangles, fills, nil
+	}, sqlTasks, nil
+}
+
+// domainToSQLTask returns the row of the table of the task type, e.g. a Rectangle for a domain.DrawRectangle
+func domainToSQLTask(canvasID uuid.UUID, task domain.Task) (interface{}, error) {
+	switch task := task.(type) {
+	case domain.DrawRectangle:
+		return Rectangle{
+			ID:                task.ID(),
+			CanvasID:          canvasID,
+			X:                 task.Point().X(),
+			Y:                 task.Point().Y(),
+			Height:            task.Height(),
+			Width:             task.Width(),
+			Filler:            task.Filler(),
+			Outline:           task.Outline(),
+			Pattern:           domainToSQLPattern(task.Pattern()),
+			FillerForeground:  string(task.FillerStyle().Foreground()),
+			FillerBackground:  string(task.FillerStyle().Background()),
+			OutlineForeground: string(task.OutlineStyle().Foreground()),
+			OutlineBackground: string(task.OutlineStyle().Background()),
+			Sequence:          task.Sequence(),
+			CreatedAt:         task.CreatedAt(),
+		}, nil
+	case domain.Fill:
+		return Fill{
+			ID:           task.ID(),
+			CanvasID:     canvasID,
+			X:            task.Point().X(),
+			Y:            task.Point().Y(),
+			Filler:       task.Filler(),
+			Connectivity: int(task.Connectivity()),
+			Mode:         string(task.Mode()),
+			Boundary:     task.Boundary(),
+			Pattern:      domainToSQLPattern(task.Pattern()),
+			Foreground:   string(task.Style().Foreground()),
+			Background:   string(task.Style().Background()),
+			Sequence:     task.Sequence(),
+			CreatedAt:    task.CreatedAt(),
+		}, nil
+	case domain.DrawLine:
+		return Line{
+			ID:        task.ID(),
+			CanvasID:  canvasID,
+			FromX:     task.From().X(),
+			FromY:     task.From().Y(),
+			ToX:       task.To().X(),
+			ToY:       task.To().Y(),
+			Outline:   task.Outline(),
+			Sequence:  task.Sequence(),
+			CreatedAt: task.CreatedAt(),
+		}, nil
+	case domain.DrawEllipse:
+		return Ellipse{
+			ID:        task.ID(),
+			CanvasID:  canvasID,
+			X:         task.Point().X(),
+			Y:         task.Point().Y(),
+			Height:    task.Height(),
+			Width:     task.Width(),
+			Filler:    task.Filler(),
+			Outline:   task.Outline(),
+			Sequence:  task.Sequence(),
+			CreatedAt: task.CreatedAt(),
+		}, nil
+	case domain.DrawText:
+		return Text{
+			ID:        task.ID(),
+			CanvasID:  canvasID,
+			X:         task.Point().X(),
+			Y:         task.Point().Y(),
+			Text:      task.Text(),
+			WrapWidth: task.WrapWidth(),
+			Alignment: string(task.Alignment()),
+			Sequence:  task.Sequence(),
+			CreatedAt: task.CreatedAt(),
+		}, nil
+	case domain.DrawPolyline:
+		return Polyline{
+			ID:        task.ID(),
+			CanvasID:  canvasID,
+			Points:    domainToSQLPoints(task.Points()),
+			Outline:   task.Outline(),
+			Sequence:  task.Sequence(),
+			CreatedAt: task.CreatedAt(),
+		}, nil
+	case domain.DrawPolygon:
+		return Polygon{
+			ID:        task.ID(),
+			CanvasID:  canvasID,
+			Points:    domainToSQLPoints(task.Points()),
+			Filler:    task.Filler(),
+			Outline:   task.Outline(),
+			Sequence:  task.Sequence(),
+			CreatedAt: task.CreatedAt(),
+		}, nil
+	default:
+		return nil, fmt.Errorf("failed to convert domain to sql task: %#v", task)
+	}
 }
 
+// Update stores the size and the history position of the canvas, and the tasks added, edited and removed in it since it
+// was retrieved. The rest of the tasks are left as they are stored, so a canvas retrieved before another update does
+// not undo the changes of it
 func (c *CanvasRepository) Update(ctx context.Context, canvas domain.Canvas) error {
-	_, sqlRectangles, sqlFills, err := domainToSQL(canvas)
+	sqlCanvas, _, err := domainToSQL(canvas)
+	if err != nil {
+		return err
+	}
+
+	added, edited, editedIDs, err := changedRows(canvas)
 	if err != nil {
 		return err
 	}
 
 	return c.sess.Tx(func(sess db.Session) error {
-		if len(sqlRectangles) > 0 {
-			rectanglesInserter := c.sess.WithContext(ctx).
-				SQL().
-				InsertInto(rectanglesTable)
+		_, err := sess.WithContext(ctx).
+			SQL().
+			Update(canvasTable).
+			Set(map[string]interface{}{
+				"height": sqlCanvas.Height,
+				"width":  sqlCanvas.Width,
+				"undone": sqlCanvas.Undone,
+			}).
+			Where(db.Cond{"id": sqlCanvas.ID}).
+			Exec()
+		if err != nil {
+			return err
+		}
+
+		err = deleteAll(ctx, sess, sqlCanvas.ID, canvas.RemovedTasks())
+		if err != nil {
+			return err
+		}
 
-			for i := range sqlRectangles {
-				rectanglesInserter = rectanglesInserter.Values(sqlRectangles[i])
+		var stored Canvas
+		err = sess.WithContext(ctx).
+			Collection(canvasTable).
+			Find(db.Cond{"id": sqlCanvas.ID}).
+			One(&stored)
+		if err != nil {
+			if err == db.ErrNoMoreRows {
+				return app.CanvasNotFound{ID: sqlCanvas.ID}
 			}
-			_, err = rectanglesInserter.
-				Amend(onConflictDoNothing).
+			return err
+		}
+
+		err = checkAddedSequences(ctx, sess, canvas, stored.Sequence)
+		if err != nil {
+			return err
+		}
+
+		if sqlCanvas.Sequence > stored.Sequence {
+			_, err = sess.WithContext(ctx).
+				SQL().
+				Update(canvasTable).
+				Set("sequence", sqlCanvas.Sequence).
+				Where(db.Cond{"id": sqlCanvas.ID}).
 				Exec()
 			if err != nil {
 				return err
 			}
 		}
 
-		if len(sqlFills) > 0 {
-			fillsInserter := c.sess.WithContext(ctx).
-				SQL().
-				InsertInto(fillsTable)
+		// the tasks edited must still be stored, otherwise they were removed by another update
+		found, err := storedIDs(ctx, sess, sqlCanvas.ID, editedIDs)
+		if err != nil {
+			return err
+		}
+		if len(found) != len(editedIDs) {
+			return app.CanvasConflict{ID: canvas.ID()}
+		}
 
-			for i := range sqlFills {
-				fillsInserter = fillsInserter.Values(sqlFills[i])
+		for _, table := range tasksTables {
+			err = insertAll(ctx, sess, table, added[table])
+			if err != nil {
+				return err
 			}
 
-			_, err = fillsInserter.
-				Amend(onConflictDoNothing).
-				Exec()
+			err = upsertAll(ctx, sess, table, edited[table])
 			if err != nil {
 				return err
 			}
@@ -158,13 +425,262 @@ func (c *CanvasRepository) Update(ctx context.Context, canvas domain.Canvas) err
 	})
 }
 
+// changedRows returns the rows of the tasks added and edited in the canvas by their table, along with the IDs of the
+// tasks edited that must be already stored. The tasks added and then edited are upserted, as they may be stored or not
+func changedRows(canvas domain.Canvas) (map[string][]interface{}, map[string][]interface{}, []uuid.UUID, error) {
+	added := make(map[uuid.UUID]bool, len(canvas.AddedTasks()))
+	for _, id := range canvas.AddedTasks() {
+		added[id] = true
+	}
+	edited := make(map[uuid.UUID]bool, len(canvas.EditedTasks()))
+	for _, id := range canvas.EditedTasks() {
+		edited[id] = true
+	}
+
+	addedRows := map[string][]interface{}{}
+	editedRows := map[string][]interface{}{}
+	var editedIDs []uuid.UUID
+	for _, task := range canvas.History() {
+		id, _ := domain.TaskID(task)
+		if !added[id] && !edited[id] {
+			continue
+		}
+
+		sqlTask, err := domainToSQLTask(canvas.ID(), task)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		table := sqlTaskTable(sqlTask)
+
+		switch {
+		case !edited[id]:
+			addedRows[table] = append(addedRows[table], sqlTask)
+		case added[id]:
+			editedRows[table] = append(editedRows[table], sqlTask)
+		default:
+			editedRows[table] = append(editedRows[table], sqlTask)
+			editedIDs = append(editedIDs, id)
+		}
+	}
+
+	return addedRows, editedRows, editedIDs, nil
+}
+
+func sqlTaskTable(sqlTask interface{}) string {
+	switch sqlTask.(type) {
+	case Rectangle:
+		return rectanglesTable
+	case Fill:
+		return fillsTable
+	case Line:
+		return linesTable
+	case Ellipse:
+		return ellipsesTable
+	case Text:
+		return textsTable
+	case Polyline:
+		return polylinesTable
+	default:
+		return polygonsTable
+	}
+}
+
+// storedIDs returns the IDs of the tasks of the canvas that are stored
+func storedIDs(ctx context.Context, sess db.Session, canvasID uuid.UUID, ids []uuid.UUID) (map[uuid.UUID]bool, error) {
+	values := make([]interface{}, len(ids))
+	for i := range ids {
+		values[i] = ids[i]
+	}
+
+	stored := make(map[uuid.UUID]bool, len(ids))
+	for _, table := range tasksTables {
+		err := inBatches(len(values), func(from, to int) error {
+			var rows []struct {
+				ID uuid.UUID `db:"id"`
+			}
+			err := sess.WithContext(ctx).
+				SQL().
+				Select("id").
+				From(table).
+				Where(db.Cond{"canvas_id": canvasID, "id": db.In(values[from:to]...)}).
+				All(&rows)
+			if err != nil && err != db.ErrNoMoreRows {
+				return err
+			}
+
+			for i := range rows {
+				stored[rows[i].ID] = true
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return stored, nil
+}
+
+// checkAddedSequences returns a CanvasConflict when a task added to the canvas got the sequence of another stored task,
+// or a sequence already given to a task that is not stored, which happens when tasks are added to the same canvas
+// concurrently. The row of the canvas is updated before, so it is locked and the tasks stored by the concurrent updates
+// are seen
+func checkAddedSequences(ctx context.Context, sess db.Session, canvas domain.Canvas, storedSequence int) error {
+	sequences := addedSequences(canvas)
+	if len(sequences) == 0 {
+		return nil
+	}
+
+	ids := make([]uuid.UUID, 0, len(sequences))
+	for _, id := range sequences {
+		ids = append(ids, id)
+	}
+	found, err := storedIDs(ctx, sess, canvas.ID(), ids)
+	if err != nil {
+		return err
+	}
+	for sequence, id := range sequences {
+		if sequence <= storedSequence && !found[id] {
+			return app.CanvasConflict{ID: canvas.ID()}
+		}
+	}
+
+	first := 0
+	for sequence := range sequences {
+		if first == 0 || sequence < first {
+			first = sequence
+		}
+	}
+
+	for _, table := range tasksTables {
+		var stored []struct {
+			ID       uuid.UUID `db:"id"`
+			Sequence int       `db:"sequence"`
+		}
+		err := sess.WithContext(ctx).
+			SQL().
+			Select("id", "sequence").
+			From(table).
+			Where(db.Cond{"canvas_id": canvas.ID(), "sequence >=": first}).
+			All(&stored)
+		if err != nil && err != db.ErrNoMoreRows {
+			return err
+		}
+
+		for i := range stored {
+			if id, ok := sequences[stored[i].Sequence]; ok && id != stored[i].ID {
+				return app.CanvasConflict{ID: canvas.ID()}
+			}
+		}
+	}
+
+	return nil
+}
+
+// addedSequences returns the IDs of the tasks added to the canvas since it was retrieved by their sequence
+func addedSequences(canvas domain.Canvas) map[int]uuid.UUID {
+	added := make(map[uuid.UUID]bool, len(canvas.AddedTasks()))
+	for _, id := range canvas.AddedTasks() {
+		added[id] = true
+	}
+
+	sequences := make(map[int]uuid.UUID, len(added))
+	for _, task := range canvas.History() {
+		id, _ := domain.TaskID(task)
+		if added[id] {
+			sequence, _ := domain.TaskSequence(task)
+			sequences[sequence] = id
+		}
+	}
+	return sequences
+}
+
+// insertAll inserts the rows that are not stored yet, so storing the same tasks again does nothing
+func insertAll(ctx context.Context, sess db.Session, table string, values []interface{}) error {
+	return inBatches(len(values), func(from, to int) error {
+		inserter := sess.WithContext(ctx).
+			SQL().
+			InsertInto(table)
+
+		for i := range values[from:to] {
+			inserter = inserter.Values(values[from+i])
+		}
+
+		_, err := inserter.
+			Amend(onConflictDoNothing).
+			Exec()
+		return err
+	})
+}
+
+// upsertAll inserts the rows, updating the ones that are already stored
+func upsertAll(ctx context.Context, sess db.Session, table string, values []interface{}) error {
+	return inBatches(len(values), func(from, to int) error {
+		inserter := sess.WithContext(ctx).
+			SQL().
+			InsertInto(table)
+
+		for i := range values[from:to] {
+			inserter = inserter.Values(values[from+i])
+		}
+
+		_, err := inserter.
+			Amend(onConflictUpdate(updatableColumns[table])).
+			Exec()
+		return err
+	})
+}
+
+// inBatches calls f with consecutive ranges of at most maxRowsPerStatement of the length rows, since PostgreSQL accepts
+// up to 65535 parameters in a statement and SQLite up to 32766
+func inBatches(length int, f func(from, to int) error) error {
+	for from := 0; from < length; from += maxRowsPerStatement {
+		to := from + maxRowsPerStatement
+		if to > length {
+			to = length
+		}
+
+		err := f(from, to)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func deleteAll(ctx context.Context, sess db.Session, canvasID uuid.UUID, ids []uuid.UUID) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	values := make([]interface{}, len(ids))
+	for i := range ids {
+		values[i] = ids[i]
+	}
+
+	for _, table := range tasksTables {
+		err := inBatches(len(values), func(from, to int) error {
+			_, err := sess.WithContext(ctx).
+				SQL().
+				DeleteFrom(table).
+				Where(db.Cond{"canvas_id": canvasID, "id": db.In(values[from:to]...)}).
+				Exec()
+			return err
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (c *CanvasRepository) FindByID(ctx context.Context, id uuid.UUID) (domain.Canvas, error) {
 	var sqlCanvas Canvas
-	var sqlRectangles []Rectangle
-	var sqlFills []Fill
+	var sqlTasks tasks
 
 	err := c.sess.Tx(func(sess db.Session) error {
-		err := c.sess.WithContext(ctx).
+		err := sess.WithContext(ctx).
 			Collection(canvasTable).
 			Find(db.Cond{"id": id}).
 			One(&sqlCanvas)
@@ -175,72 +691,124 @@ func (c *CanvasRepository) FindByID(ctx context.Context, id uuid.UUID) (domain.C
 			return err
 		}
 
-		err = c.sess.WithContext(ctx).
-			Collection(rectanglesTable).
-			Find(db.Cond{"canvas_id": id}).
-			All(&sqlRectangles)
-		if err != nil && err != db.ErrNoMoreRows {
+		err = findAll(ctx, sess, rectanglesTable, id, &sqlTasks.rectangles)
+		if err != nil {
 			return err
 		}
 
-		err = c.sess.WithContext(ctx).
-			Collection(fillsTable).
-			Find(db.Cond{"canvas_id": id}).
-			All(&sqlFills)
-		if err != nil && err != db.ErrNoMoreRows {
+		err = findAll(ctx, sess, fillsTable, id, &sqlTasks.fills)
+		if err != nil {
 			return err
 		}
 
-		return nil
+		err = findAll(ctx, sess, linesTable, id, &sqlTasks.lines)
+		if err != nil {
+			return err
+		}
+
+		err = findAll(ctx, sess, ellipsesTable, id, &sqlTasks.ellipses)
+		if err != nil {
+			return err
+		}
+
+		err = findAll(ctx, sess, textsTable, id, &sqlTasks.texts)
+		if err != nil {
+			return err
+		}
+
+		err = findAll(ctx, sess, polylinesTable, id, &sqlTasks.polylines)
+		if err != nil {
+			return err
+		}
+
+		return findAll(ctx, sess, polygonsTable, id, &sqlTasks.polygons)
 	})
 	if err != nil {
 		return domain.Canvas{}, err
 	}
 
-	return sqlToDomain(sqlCanvas, sqlRectangles, sqlFills), nil
+	return sqlToDomain(sqlCanvas, sqlTasks), nil
 }
 
-func sqlToDomain(canvas Canvas, rectangles []Rectangle, fills []Fill) domain.Canvas {
-	sort.Slice(rectangles, func(i, j int) bool {
-		return rectangles[i].CreatedAt.Before(rectangles[j].CreatedAt)
-	})
-	sort.Slice(fills, func(i, j int) bool {
-		return fills[i].CreatedAt.Before(fills[j].CreatedAt)
-	})
+func findAll(ctx context.Context, sess db.Session, table string, canvasID uuid.UUID, dest interface{}) error {
+	err := sess.WithContext(ctx).
+		Collection(table).
+		Find(db.Cond{"canvas_id": canvasID}).
+		All(dest)
+	if err != nil && err != db.ErrNoMoreRows {
+		return err
+	}
 
-	tasks := make([]domain.Task, len(rectangles)+len(fills))
-	ti := 0
-	ri := 0
-	fi := 0
+	return nil
+}
 
-	for ri < len(rectangles) && fi < len(fills) {
-		if rectangles[ri].CreatedAt.Before(fills[fi].CreatedAt) {
-			tasks[ti] = sqlRectangleToDomain(rectangles[ri])
-			ri++
-		} else {
-			tasks[ti] = sqlFillToDomain(fills[fi])
-			fi++
-		}
-		ti++
+type sequencedTask struct {
+	task     domain.Task
+	sequence int
+}
+
+func sqlToDomain(canvas Canvas, sqlTasks tasks) domain.Canvas {
+	sequencedTasks := make([]sequencedTask, 0, sqlTasks.len())
+	for i := range sqlTasks.rectangles {
+		sequencedTasks = append(sequencedTasks, sequencedTask{
+			task:     sqlRectangleToDomain(sqlTasks.rectangles[i]),
+			sequence: sqlTasks.rectangles[i].Sequence,
+		})
+	}
+	for i := range sqlTasks.fills {
+		sequencedTasks = append(sequencedTasks, sequencedTask{
+			task:     sqlFillToDomain(sqlTasks.fills[i]),
+			sequence: sqlTasks.fills[i].Sequence,
+		})
+	}
+	for i := range sqlTasks.lines {
+		sequencedTasks = append(sequencedTasks, sequencedTask{
+			task:     sqlLineToDomain(sqlTasks.lines[i]),
+			sequence: sqlTasks.lines[i].Sequence,
+		})
 	}
-	for ri < len(rectangles) {
-		tasks[ti] = sqlRectangleToDomain(rectangles[ri])
-		ri++
-		ti++
+	for i := range sqlTasks.ellipses {
+		sequencedTasks = append(sequencedTasks, sequencedTask{
+			task:     sqlEllipseToDomain(sqlTasks.ellipses[i]),
+			sequence: sqlTasks.ellipses[i].Sequence,
+		})
+	}
+	for i := range sqlTasks.texts {
+		sequencedTasks = append(sequencedTasks, sequencedTask{
+			task:     sqlTextToDomain(sqlTasks.texts[i]),
+			sequence: sqlTasks.texts[i].Sequence,
+		})
 	}
-	for fi < len(fills) {
-		tasks[ti] = sqlFillToDomain(fills[fi])
-		fi++
-		ti++
+	for i := range sqlTasks.polylines {
+		sequencedTasks = append(sequencedTasks, sequencedTask{
+			task:     sqlPolylineToDomain(sqlTasks.polylines[i]),
+			sequence: sqlTasks.polylines[i].Sequence,
+		})
+	}
+	for i := range sqlTasks.polygons {
+		sequencedTasks = append(sequencedTasks, sequencedTask{
+			task:     sqlPolygonToDomain(sqlTasks.polygons[i]),
+			sequence: sqlTasks.polygons[i].Sequence,
+		})
 	}
 
-	return domain.NewCanvas(
+	sort.SliceStable(sequencedTasks, func(i, j int) bool {
+		return sequencedTasks[i].sequence < sequencedTasks[j].sequence
+	})
+
+	tasks := make([]domain.Task, len(sequencedTasks))
+	for i := range sequencedTasks {
+		tasks[i] = sequencedTasks[i].task
+	}
+
+	return domain.NewCanvasWithHistory(
 		canvas.ID,
 		canvas.Height,
 		canvas.Width,
 		tasks,
+		len(tasks)-canvas.Undone,
 		canvas.CreatedAt,
-	)
+	).WithSequence(canvas.Sequence)
 }
 
 func sqlRectangleToDomain(rectangle Rectangle) domain.DrawRectangle {
@@ -252,14 +820,74 @@ func sqlRectangleToDomain(rectangle Rectangle) domain.DrawRectangle {
 		rectangle.Filler,
 		rectangle.Outline,
 		rectangle.CreatedAt,
-	)
+	).WithPattern(sqlToDomainPattern(rectangle.Pattern)).WithStyles(
+		domain.NewStyle(domain.Colour(rectangle.FillerForeground), domain.Colour(rectangle.FillerBackground)),
+		domain.NewStyle(domain.Colour(rectangle.OutlineForeground), domain.Colour(rectangle.OutlineBackground)),
+	).WithSequence(rectangle.Sequence)
 }
 
 func sqlFillToDomain(fill Fill) domain.Fill {
-	return domain.NewFill(
+	return domain.NewFillWithMode(
 		fill.ID,
 		domain.NewPoint(fill.X, fill.Y),
 		fill.Filler,
+		domain.Connectivity(fill.Connectivity),
+		domain.FillMode(fill.Mode),
+		fill.Boundary,
 		fill.CreatedAt,
-	)
+	).WithPattern(sqlToDomainPattern(fill.Pattern)).
+		WithStyle(domain.NewStyle(domain.Colour(fill.Foreground), domain.Colour(fill.Background))).
+		WithSequence(fill.Sequence)
+}
+
+func sqlLineToDomain(line Line) domain.DrawLine {
+	return domain.NewDrawLine(
+		line.ID,
+		domain.NewPoint(line.FromX, line.FromY),
+		domain.NewPoint(line.ToX, line.ToY),
+		line.Outline,
+		line.CreatedAt,
+	).WithSequence(line.Sequence)
+}
+
+func sqlEllipseToDomain(ellipse Ellipse) domain.DrawEllipse {
+	return domain.NewDrawEllipse(
+		ellipse.ID,
+		domain.NewPoint(ellipse.X, ellipse.Y),
+		ellipse.Height,
+		ellipse.Width,
+		ellipse.Filler,
+		ellipse.Outline,
+		ellipse.CreatedAt,
+	).WithSequence(ellipse.Sequence)
+}
+
+func sqlTextToDomain(text Text) domain.DrawText {
+	return domain.NewDrawText(
+		text.ID,
+		domain.NewPoint(text.X, text.Y),
+		text.Text,
+		text.WrapWidth,
+		domain.Alignment(text.Alignment),
+		text.CreatedAt,
+	).WithSequence(text.Sequence)
+}
+
+func sqlPolylineToDomain(polyline Polyline) domain.DrawPolyline {
+	return domain.NewDrawPolyline(
+		polyline.ID,
+		sqlToDomainPoints(polyline.Points),
+		polyline.Outline,
+		polyline.CreatedAt,
+	).WithSequence(polyline.Sequence)
+}
+
+func sqlPolygonToDomain(polygon Polygon) domain.DrawPolygon {
+	return domain.NewDrawPolygon(
+		polygon.ID,
+		sqlToDomainPoints(polygon.Points),
+		polygon.Filler,
+		polygon.Outline,
+		polygon.CreatedAt,
+	).WithSequence(polygon.Sequence)
 }
@@ -0,0 +1,158 @@
+package sql
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/maitesin/sketch/internal/domain"
+	"github.com/maitesin/sketch/internal/infra/raster"
+)
+
+// Style is a pair with the foreground and background colours of a cell
+type Style [2]string
+
+// Cells is stored as a JSON object in a single column. Each row of runes is stored as a string. The styles and the
+// owners of the cells are stored as indexes in the lists of the distinct ones, and each row of indexes is run-length
+// encoded as pairs of an index and the number of consecutive cells that have it, since most of the cells share them
+type Cells struct {
+	Rows      []string    `json:"rows"`
+	Styles    []Style     `json:"styles"`
+	StyleRuns [][]int     `json:"style_runs"`
+	Owners    []uuid.UUID `json:"owners"`
+	OwnerRuns [][]int     `json:"owner_runs"`
+}
+
+func (c Cells) Value() (driver.Value, error) {
+	b, err := json.Marshal(c)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+func (c *Cells) Scan(src interface{}) error {
+	switch src := src.(type) {
+	case []byte:
+		return json.Unmarshal(src, c)
+	case string:
+		return json.Unmarshal([]byte(src), c)
+	default:
+		return fmt.Errorf("failed to scan cells from %T", src)
+	}
+}
+
+func rasterToSQLCells(snapshot raster.Snapshot) Cells {
+	cells := Cells{
+		Rows:      make([]string, len(snapshot.Grid())),
+		StyleRuns: make([][]int, len(snapshot.Styles())),
+		OwnerRuns: make([][]int, len(snapshot.Owners())),
+	}
+
+	for y, row := range snapshot.Grid() {
+		cells.Rows[y] = string(row)
+	}
+
+	styles := map[domain.Style]int{}
+	for y, row := range snapshot.Styles() {
+		indexes := make([]int, len(row))
+		for x, style := range row {
+			index, ok := styles[style]
+			if !ok {
+				index = len(cells.Styles)
+				styles[style] = index
+				cells.Styles = append(cells.Styles, Style{string(style.Foreground()), string(style.Background())})
+			}
+			indexes[x] = index
+		}
+		cells.StyleRuns[y] = encodeRuns(indexes)
+	}
+
+	owners := map[uuid.UUID]int{}
+	for y, row := range snapshot.Owners() {
+		indexes := make([]int, len(row))
+		for x, owner := range row {
+			index, ok := owners[owner]
+			if !ok {
+				index = len(cells.Owners)
+				owners[owner] = index
+				cells.Owners = append(cells.Owners, owner)
+			}
+			indexes[x] = index
+		}
+		cells.OwnerRuns[y] = encodeRuns(indexes)
+	}
+
+	return cells
+}
+
+func sqlCellsToRaster(cells Cells) (raster.Grid, raster.Styles, raster.Owners, error) {
+	grid := make(raster.Grid, len(cells.Rows))
+	for y, row := range cells.Rows {
+		grid[y] = []rune(row)
+	}
+
+	styles := make(raster.Styles, len(cells.StyleRuns))
+	for y, runs := range cells.StyleRuns {
+		indexes, err := decodeRuns(runs, len(cells.Styles))
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("invalid styles in row %d: %w", y, err)
+		}
+		styles[y] = make([]domain.Style, len(indexes))
+		for x, index := range indexes {
+			style := cells.Styles[index]
+			styles[y][x] = domain.NewStyle(domain.Colour(style[0]), domain.Colour(style[1]))
+		}
+	}
+
+	owners := make(raster.Owners, len(cells.OwnerRuns))
+	for y, runs := range cells.OwnerRuns {
+		indexes, err := decodeRuns(runs, len(cells.Owners))
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("invalid owners in row %d: %w", y, err)
+		}
+		owners[y] = make([]uuid.UUID, len(indexes))
+		for x, index := range indexes {
+			owners[y][x] = cells.Owners[index]
+		}
+	}
+
+	return grid, styles, owners, nil
+}
+
+// encodeRuns returns the pairs of an index and the number of consecutive cells of the row that have it
+func encodeRuns(indexes []int) []int {
+	var runs []int
+	for x := 0; x < len(indexes); {
+		length := 1
+		for x+length < len(indexes) && indexes[x+length] == indexes[x] {
+			length++
+		}
+		runs = append(runs, indexes[x], length)
+		x += length
+	}
+	return runs
+}
+
+// decodeRuns returns the indexes of the cells of a row from its runs. The indexes must be lower than values
+func decodeRuns(runs []int, values int) ([]int, error) {
+	if len(runs)%2 != 0 {
+		return nil, fmt.Errorf("odd number of values %d in runs", len(runs))
+	}
+
+	var indexes []int
+	for i := 0; i < len(runs); i += 2 {
+		index, length := runs[i], runs[i+1]
+		if index < 0 || index >= values {
+			return nil, fmt.Errorf("invalid index %d", index)
+		}
+		if length <= 0 {
+			return nil, fmt.Errorf("invalid run length %d", length)
+		}
+		for j := 0; j < length; j++ {
+			indexes = append(indexes, index)
+		}
+	}
+	return indexes, nil
+}
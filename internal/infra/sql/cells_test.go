@@ -0,0 +1,38 @@
+package sql_test
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	sqlx "github.com/maitesin/sketch/internal/infra/sql"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCells_ValueAndScan(t *testing.T) {
+	t.Parallel()
+
+	cells := sqlx.Cells{
+		Rows:      []string{"#. ", "##ñ"},
+		Styles:    []sqlx.Style{{"", ""}, {"red", "blue"}},
+		StyleRuns: [][]int{{1, 1, 0, 2}, {1, 2, 0, 1}},
+		Owners:    []uuid.UUID{uuid.New(), uuid.Nil},
+		OwnerRuns: [][]int{{0, 2, 1, 1}, {0, 3}},
+	}
+
+	value, err := cells.Value()
+	require.NoError(t, err)
+
+	var fromString sqlx.Cells
+	err = fromString.Scan(value)
+	require.NoError(t, err)
+	require.Equal(t, cells, fromString)
+
+	var fromBytes sqlx.Cells
+	err = fromBytes.Scan([]byte(value.(string)))
+	require.NoError(t, err)
+	require.Equal(t, cells, fromBytes)
+
+	var fromInt sqlx.Cells
+	err = fromInt.Scan(42)
+	require.Error(t, err)
+}
@@ -13,3 +13,10 @@ type Config struct {
 func (c *Config) DatabaseURL() string {
 	return fmt.Sprintf("%s?sslmode=%s&binary_parameters=%s", c.URL, c.SSLMode, c.BinaryParams)
 }
+
+// EventStoreConfig defines whether the canvases are stored as events, and the number of events between the snapshots
+// of their state
+type EventStoreConfig struct {
+	Enabled          bool
+	SnapshotInterval int
+}
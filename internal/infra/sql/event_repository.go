@@ -0,0 +1,582 @@
+package sql
+
+import (
+	"bytes"
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/maitesin/sketch/internal/app"
+	"github.com/maitesin/sketch/internal/domain"
+	"github.com/upper/db/v4"
+)
+
+const (
+	eventsTable         = "events"
+	eventSnapshotsTable = "event_snapshots"
+)
+
+// EventType is the kind of change of a canvas recorded by an event
+type EventType string
+
+const (
+	// EventCanvasCreated records the size and the creation time of a new canvas
+	EventCanvasCreated EventType = "canvas_created"
+	// EventCanvasResized records the new size of a canvas. The tasks moved by the resize are recorded as edited
+	EventCanvasResized EventType = "canvas_resized"
+	// EventTaskAdded records a task added at the end of the history of a canvas
+	EventTaskAdded EventType = "task_added"
+	// EventTaskEdited records the new values of a task, which keeps its position in the history of the canvas
+	EventTaskEdited EventType = "task_edited"
+	// EventTaskRemoved records the ID of a task removed from the history of a canvas
+	EventTaskRemoved EventType = "task_removed"
+	// EventHistoryMoved records the number of tasks undone in a canvas after undoing or redoing tasks
+	EventHistoryMoved EventType = "history_moved"
+)
+
+// Event is a change of a canvas. The events of a canvas are numbered by their sequence, starting at 1, and they are
+// never changed once they are appended
+type Event struct {
+	CanvasID  uuid.UUID    `db:"canvas_id"`
+	Sequence  int          `db:"sequence"`
+	Type      EventType    `db:"type"`
+	Payload   EventPayload `db:"payload"`
+	CreatedAt time.Time    `db:"created_at"`
+}
+
+// EventPayload is stored as a JSON object in a single column. Each type of event only sets the fields it needs. The
+// task is ignored by the db mapper, otherwise it would look for the columns of the task tables in the payload
+type EventPayload struct {
+	Height    int          `json:"height,omitempty"`
+	Width     int          `json:"width,omitempty"`
+	Undone    int          `json:"undone,omitempty"`
+	CreatedAt *time.Time   `json:"created_at,omitempty"`
+	TaskID    *uuid.UUID   `json:"task_id,omitempty"`
+	Task      *TaskPayload `json:"task,omitempty" db:"-"`
+}
+
+func (p EventPayload) Value() (driver.Value, error) {
+	b, err := json.Marshal(p)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+func (p *EventPayload) Scan(src interface{}) error {
+	switch src := src.(type) {
+	case []byte:
+		return json.Unmarshal(src, p)
+	case string:
+		return json.Unmarshal([]byte(src), p)
+	default:
+		return fmt.Errorf("failed to scan event payload from %T", src)
+	}
+}
+
+// TaskPayload holds a task in the payload of an event. Only the field of the type of the task is set
+type TaskPayload struct {
+	Rectangle *Rectangle `json:"rectangle,omitempty"`
+	Fill      *Fill      `json:"fill,omitempty"`
+	Line      *Line      `json:"line,omitempty"`
+	Ellipse   *Ellipse   `json:"ellipse,omitempty"`
+	Text      *Text      `json:"text,omitempty"`
+	Polyline  *Polyline  `json:"polyline,omitempty"`
+	Polygon   *Polygon   `json:"polygon,omitempty"`
+}
+
+func domainToTaskPayload(canvasID uuid.UUID, task domain.Task) (TaskPayload, error) {
+	sqlTask, err := domainToSQLTask(canvasID, task)
+	if err != nil {
+		return TaskPayload{}, err
+	}
+
+	switch sqlTask := sqlTask.(type) {
+	case Rectangle:
+		return TaskPayload{Rectangle: &sqlTask}, nil
+	case Fill:
+		return TaskPayload{Fill: &sqlTask}, nil
+	case Line:
+		return TaskPayload{Line: &sqlTask}, nil
+	case Ellipse:
+		return TaskPayload{Ellipse: &sqlTask}, nil
+	case Text:
+		return TaskPayload{Text: &sqlTask}, nil
+	case Polyline:
+		return TaskPayload{Polyline: &sqlTask}, nil
+	case Polygon:
+		return TaskPayload{Polygon: &sqlTask}, nil
+	default:
+		return TaskPayload{}, fmt.Errorf("failed to convert sql task to task payload: %#v", sqlTask)
+	}
+}
+
+func (p TaskPayload) id() uuid.UUID {
+	switch {
+	case p.Rectangle != nil:
+		return p.Rectangle.ID
+	case p.Fill != nil:
+		return p.Fill.ID
+	case p.Line != nil:
+		return p.Line.ID
+	case p.Ellipse != nil:
+		return p.Ellipse.ID
+	case p.Text != nil:
+		return p.Text.ID
+	case p.Polyline != nil:
+		return p.Polyline.ID
+	case p.Polygon != nil:
+		return p.Polygon.ID
+	default:
+		return uuid.Nil
+	}
+}
+
+func (p TaskPayload) sequence() int {
+	switch {
+	case p.Rectangle != nil:
+		return p.Rectangle.Sequence
+	case p.Fill != nil:
+		return p.Fill.Sequence
+	case p.Line != nil:
+		return p.Line.Sequence
+	case p.Ellipse != nil:
+		return p.Ellipse.Sequence
+	case p.Text != nil:
+		return p.Text.Sequence
+	case p.Polyline != nil:
+		return p.Polyline.Sequence
+	case p.Polygon != nil:
+		return p.Polygon.Sequence
+	default:
+		return 0
+	}
+}
+
+func (p TaskPayload) toDomain() (domain.Task, error) {
+	switch {
+	case p.Rectangle != nil:
+		return sqlRectangleToDomain(*p.Rectangle), nil
+	case p.Fill != nil:
+		return sqlFillToDomain(*p.Fill), nil
+	case p.Line != nil:
+		return sqlLineToDomain(*p.Line), nil
+	case p.Ellipse != nil:
+		return sqlEllipseToDomain(*p.Ellipse), nil
+	case p.Text != nil:
+		return sqlTextToDomain(*p.Text), nil
+	case p.Polyline != nil:
+		return sqlPolylineToDomain(*p.Polyline), nil
+	case p.Polygon != nil:
+		return sqlPolygonToDomain(*p.Polygon), nil
+	default:
+		return nil, errors.New("failed to convert task payload to domain: it has no task")
+	}
+}
+
+// equal compares the JSON encoding of the tasks, which is the way they are stored in the events
+func (p TaskPayload) equal(other TaskPayload) (bool, error) {
+	b, err := json.Marshal(p)
+	if err != nil {
+		return false, err
+	}
+	otherB, err := json.Marshal(other)
+	if err != nil {
+		return false, err
+	}
+	return bytes.Equal(b, otherB), nil
+}
+
+// EventState is the state of a canvas after folding its events. It is stored as a JSON object in a single column of
+// the snapshots of the events
+type EventState struct {
+	Height    int           `json:"height"`
+	Width     int           `json:"width"`
+	Undone    int           `json:"undone"`
+	Sequence  int           `json:"sequence,omitempty"`
+	CreatedAt time.Time     `json:"created_at"`
+	Tasks     []TaskPayload `json:"tasks"`
+}
+
+func (s EventState) Value() (driver.Value, error) {
+	b, err := json.Marshal(s)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+func (s *EventState) Scan(src interface{}) error {
+	switch src := src.(type) {
+	case []byte:
+		return json.Unmarshal(src, s)
+	case string:
+		return json.Unmarshal([]byte(src), s)
+	default:
+		return fmt.Errorf("failed to scan event state from %T", src)
+	}
+}
+
+func domainToEventState(canvas domain.Canvas) (EventState, error) {
+	tasks := make([]TaskPayload, len(canvas.History()))
+	for i, task := range canvas.History() {
+		payload, err := domainToTaskPayload(canvas.ID(), task)
+		if err != nil {
+			return EventState{}, err
+		}
+		tasks[i] = payload
+	}
+
+	return EventState{
+		Height:    canvas.Height(),
+		Width:     canvas.Width(),
+		Undone:    len(canvas.History()) - canvas.Cursor(),
+		Sequence:  canvas.Sequence(),
+		CreatedAt: canvas.CreatedAt(),
+		Tasks:     tasks,
+	}, nil
+}
+
+func (s EventState) toDomain(id uuid.UUID) (domain.Canvas, error) {
+	tasks := make([]domain.Task, len(s.Tasks))
+	for i := range s.Tasks {
+		task, err := s.Tasks[i].toDomain()
+		if err != nil {
+			return domain.Canvas{}, err
+		}
+		tasks[i] = task
+	}
+
+	return domain.NewCanvasWithHistory(id, s.Height, s.Width, tasks, len(tasks)-s.Undone, s.CreatedAt).WithSequence(s.Sequence), nil
+}
+
+func (s EventState) index(id uuid.UUID) int {
+	for i := range s.Tasks {
+		if s.Tasks[i].id() == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// apply folds the event into the state
+func (s *EventState) apply(event Event) error {
+	switch event.Type {
+	case EventCanvasCreated:
+		s.Height = event.Payload.Height
+		s.Width = event.Payload.Width
+		if event.Payload.CreatedAt != nil {
+			s.CreatedAt = *event.Payload.CreatedAt
+		}
+	case EventCanvasResized:
+		s.Height = event.Payload.Height
+		s.Width = event.Payload.Width
+	case EventTaskAdded:
+		if event.Payload.Task == nil {
+			return fmt.Errorf("event %d of canvas %s has no task", event.Sequence, event.CanvasID)
+		}
+		s.Tasks = append(s.Tasks, *event.Payload.Task)
+		if sequence := event.Payload.Task.sequence(); sequence > s.Sequence {
+			s.Sequence = sequence
+		}
+	case EventTaskEdited:
+		if event.Payload.Task == nil {
+			return fmt.Errorf("event %d of canvas %s has no task", event.Sequence, event.CanvasID)
+		}
+		i := s.index(event.Payload.Task.id())
+		if i < 0 {
+			return fmt.Errorf("event %d of canvas %s edits a task that does not exist", event.Sequence, event.CanvasID)
+		}
+		s.Tasks[i] = *event.Payload.Task
+	case EventTaskRemoved:
+		if event.Payload.TaskID == nil {
+			return fmt.Errorf("event %d of canvas %s has no task ID", event.Sequence, event.CanvasID)
+		}
+		i := s.index(*event.Payload.TaskID)
+		if i < 0 {
+			return fmt.Errorf("event %d of canvas %s removes a task that does not exist", event.Sequence, event.CanvasID)
+		}
+		s.Tasks = append(s.Tasks[:i:i], s.Tasks[i+1:]...)
+	case EventHistoryMoved:
+		s.Undone = event.Payload.Undone
+	default:
+		return fmt.Errorf("event %d of canvas %s has unknown type %q", event.Sequence, event.CanvasID, event.Type)
+	}
+
+	return nil
+}
+
+// changes returns the events of the changes made to the canvas since it was retrieved, which turn the state into the
+// given one. Only the tasks added, edited and removed in the canvas are compared with the state, so the changes stored
+// concurrently are kept. It returns a CanvasConflict when a task edited in the canvas has been removed from the state
+func (s EventState) changes(canvas domain.Canvas, to EventState) ([]Event, error) {
+	var events []Event
+	if s.Height != to.Height || s.Width != to.Width {
+		events = append(events, Event{
+			Type:    EventCanvasResized,
+			Payload: EventPayload{Height: to.Height, Width: to.Width},
+		})
+	}
+
+	stored := make(map[uuid.UUID]TaskPayload, len(s.Tasks))
+	for _, task := range s.Tasks {
+		stored[task.id()] = task
+	}
+
+	for _, id := range canvas.RemovedTasks() {
+		id := id
+		if _, ok := stored[id]; ok {
+			delete(stored, id)
+			events = append(events, Event{Type: EventTaskRemoved, Payload: EventPayload{TaskID: &id}})
+		}
+	}
+
+	added := make(map[uuid.UUID]bool, len(canvas.AddedTasks()))
+	for _, id := range canvas.AddedTasks() {
+		added[id] = true
+	}
+	edited := make(map[uuid.UUID]bool, len(canvas.EditedTasks()))
+	for _, id := range canvas.EditedTasks() {
+		edited[id] = true
+	}
+
+	for i := range to.Tasks {
+		task := to.Tasks[i]
+		previous, ok := stored[task.id()]
+		switch {
+		case added[task.id()] && !ok:
+			stored[task.id()] = task
+			events = append(events, Event{Type: EventTaskAdded, Payload: EventPayload{Task: &task}})
+		case edited[task.id()] && !ok:
+			return nil, app.CanvasConflict{ID: canvas.ID()}
+		case edited[task.id()]:
+			equal, err := previous.equal(task)
+			if err != nil {
+				return nil, err
+			}
+			if !equal {
+				events = append(events, Event{Type: EventTaskEdited, Payload: EventPayload{Task: &task}})
+			}
+		}
+	}
+
+	if s.Undone != to.Undone {
+		events = append(events, Event{Type: EventHistoryMoved, Payload: EventPayload{Undone: to.Undone}})
+	}
+
+	return events, nil
+}
+
+// EventSnapshot is the state of a canvas after folding its events up to the sequence
+type EventSnapshot struct {
+	CanvasID  uuid.UUID  `db:"canvas_id"`
+	Sequence  int        `db:"sequence"`
+	State     EventState `db:"state"`
+	CreatedAt time.Time  `db:"created_at"`
+}
+
+// EventCanvasRepository stores the canvases as an append-only log of events, and rebuilds them by folding their events.
+// Every snapshotInterval events the state of the canvas is stored as a snapshot, so only the events after it are
+// folded. When snapshotInterval is 0 no snapshot is taken
+type EventCanvasRepository struct {
+	sess             db.Session
+	snapshotInterval int
+}
+
+func NewEventCanvasRepository(sess db.Session, snapshotInterval int) *EventCanvasRepository {
+	return &EventCanvasRepository{sess: sess, snapshotInterval: snapshotInterval}
+}
+
+// Insert appends the creation event of the canvas, unless the canvas already exists. As in CanvasRepository, the tasks
+// of the canvas are stored by Update
+func (e *EventCanvasRepository) Insert(ctx context.Context, canvas domain.Canvas) error {
+	_, err := domainToEventState(canvas)
+	if err != nil {
+		return err
+	}
+
+	createdAt := canvas.CreatedAt()
+	_, err = e.sess.WithContext(ctx).
+		SQL().
+		InsertInto(eventsTable).
+		Values(Event{
+			CanvasID: canvas.ID(),
+			Sequence: 1,
+			Type:     EventCanvasCreated,
+			Payload: EventPayload{
+				Height:    canvas.Height(),
+				Width:     canvas.Width(),
+				CreatedAt: &createdAt,
+			},
+			CreatedAt: time.Now().UTC(),
+		}).
+		Amend(onConflictDoNothing).
+		Exec()
+	return err
+}
+
+// Update appends the events of the changes of the canvas since it was retrieved. Events appended concurrently for the
+// same canvas take the sequence of its events, and tasks added concurrently its sequence, so both return a
+// CanvasConflict
+func (e *EventCanvasRepository) Update(ctx context.Context, canvas domain.Canvas) error {
+	updated, err := domainToEventState(canvas)
+	if err != nil {
+		return err
+	}
+
+	return e.sess.Tx(func(sess db.Session) error {
+		state, sequence, err := e.load(ctx, sess, canvas.ID())
+		if err != nil {
+			return err
+		}
+
+		// tasks added concurrently to the same canvas get the same sequence, so the last one to be stored conflicts
+		added := addedSequences(canvas)
+		for i := range state.Tasks {
+			if id, ok := added[state.Tasks[i].sequence()]; ok && id != state.Tasks[i].id() {
+				return app.CanvasConflict{ID: canvas.ID()}
+			}
+		}
+		// the sequences given to the tasks removed are not given again either
+		for taskSequence, id := range added {
+			if taskSequence <= state.Sequence && state.index(id) < 0 {
+				return app.CanvasConflict{ID: canvas.ID()}
+			}
+		}
+
+		events, err := state.changes(canvas, updated)
+		if err != nil {
+			return err
+		}
+		if len(events) == 0 {
+			return nil
+		}
+
+		now := time.Now().UTC()
+		for i := range events {
+			events[i].CanvasID = canvas.ID()
+			events[i].Sequence = sequence + i + 1
+			events[i].CreatedAt = now
+
+			err = state.apply(events[i])
+			if err != nil {
+				return err
+			}
+		}
+
+		err = inBatches(len(events), func(from, to int) error {
+			inserter := sess.WithContext(ctx).
+				SQL().
+				InsertInto(eventsTable)
+
+			for i := range events[from:to] {
+				inserter = inserter.Values(events[from+i])
+			}
+
+			res, err := inserter.
+				Amend(onConflictDoNothing).
+				Exec()
+			if err != nil {
+				return err
+			}
+
+			// the sequence of the events was taken by the events appended concurrently
+			inserted, err := res.RowsAffected()
+			if err != nil {
+				return err
+			}
+			if inserted != int64(to-from) {
+				return app.CanvasConflict{ID: canvas.ID()}
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		last := sequence + len(events)
+		if e.snapshotInterval == 0 || last/e.snapshotInterval == sequence/e.snapshotInterval {
+			return nil
+		}
+
+		_, err = sess.WithContext(ctx).
+			SQL().
+			InsertInto(eventSnapshotsTable).
+			Values(EventSnapshot{
+				CanvasID:  canvas.ID(),
+				Sequence:  last,
+				State:     state,
+				CreatedAt: now,
+			}).
+			Amend(onConflictUpdateEventSnapshot).
+			Exec()
+		return err
+	})
+}
+
+func onConflictUpdateEventSnapshot(queryIn string) string {
+	return queryIn + ` ON CONFLICT (canvas_id) DO UPDATE SET sequence = EXCLUDED.sequence, state = EXCLUDED.state, ` +
+		`created_at = EXCLUDED.created_at`
+}
+
+func (e *EventCanvasRepository) FindByID(ctx context.Context, id uuid.UUID) (domain.Canvas, error) {
+	var state EventState
+
+	err := e.sess.Tx(func(sess db.Session) error {
+		var err error
+		state, _, err = e.load(ctx, sess, id)
+		return err
+	})
+	if err != nil {
+		return domain.Canvas{}, err
+	}
+
+	return state.toDomain(id)
+}
+
+// load folds the events of the canvas after its latest snapshot, and returns its state along with the sequence of its
+// last event
+func (e *EventCanvasRepository) load(ctx context.Context, sess db.Session, id uuid.UUID) (EventState, int, error) {
+	var state EventState
+	var sequence int
+
+	var snapshot EventSnapshot
+	err := sess.WithContext(ctx).
+		Collection(eventSnapshotsTable).
+		Find(db.Cond{"canvas_id": id}).
+		One(&snapshot)
+	switch {
+	case err == nil:
+		state, sequence = snapshot.State, snapshot.Sequence
+	case err != db.ErrNoMoreRows:
+		return EventState{}, 0, err
+	}
+
+	var events []Event
+	err = sess.WithContext(ctx).
+		Collection(eventsTable).
+		Find(db.Cond{"canvas_id": id, "sequence >": sequence}).
+		OrderBy("sequence").
+		All(&events)
+	if err != nil && err != db.ErrNoMoreRows {
+		return EventState{}, 0, err
+	}
+
+	if sequence == 0 && len(events) == 0 {
+		return EventState{}, 0, app.CanvasNotFound{ID: id}
+	}
+
+	for _, event := range events {
+		err = state.apply(event)
+		if err != nil {
+			return EventState{}, 0, err
+		}
+		sequence = event.Sequence
+	}
+
+	return state, sequence, nil
+}
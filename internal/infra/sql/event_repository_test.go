@@ -0,0 +1,67 @@
+// +build integration
+
+package sql_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/maitesin/sketch/internal/app"
+	"github.com/maitesin/sketch/internal/app/apptest"
+	"github.com/maitesin/sketch/internal/domain"
+	sqlx "github.com/maitesin/sketch/internal/infra/sql"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventCanvasRepository(t *testing.T) {
+	apptest.TestCanvasRepository(t, func(t *testing.T) app.CanvasRepository {
+		sess, dbCloser := setup(t)
+		t.Cleanup(dbCloser)
+
+		return sqlx.NewEventCanvasRepository(sess, 3)
+	})
+}
+
+func TestEventCanvasRepository_ConcurrentUpdates(t *testing.T) {
+	sess, dbCloser := setup(t)
+	t.Cleanup(dbCloser)
+	repository := sqlx.NewEventCanvasRepository(sess, 0)
+
+	canvas := domain.NewCanvas(uuid.New(), 10, 10, nil, time.Now().UTC())
+	err := repository.Insert(context.Background(), canvas)
+	require.NoError(t, err)
+
+	// the updates that lose the race for the sequence of the events conflict
+	const updates = 8
+	var wg sync.WaitGroup
+	errs := make([]error, updates)
+	for i := 0; i < updates; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			found, err := repository.FindByID(context.Background(), canvas.ID())
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			err = found.Resize(11+i, 11+i, domain.AnchorTopLeft, false)
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			errs[i] = repository.Update(context.Background(), found)
+		}(i)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil && !errors.As(err, &app.CanvasConflict{}) {
+			require.NoError(t, err)
+		}
+	}
+}
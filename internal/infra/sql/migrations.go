@@ -0,0 +1,22 @@
+package sql
+
+import (
+	"embed"
+	"io/fs"
+
+	"github.com/maitesin/sketch/internal/infra/migration"
+	"github.com/upper/db/v4"
+)
+
+//go:embed migrations/*.sql
+var migrations embed.FS
+
+// NewMigrator returns the runner of the migrations of the PostgreSQL DB, which are embedded in the binary
+func NewMigrator(sess db.Session) (*migration.Runner, error) {
+	files, err := fs.Sub(migrations, "migrations")
+	if err != nil {
+		return nil, err
+	}
+
+	return migration.NewRunner(sess, files)
+}
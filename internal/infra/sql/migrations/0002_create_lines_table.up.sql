@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS lines (
+    id UUID PRIMARY KEY,
+    canvas_id UUID REFERENCES canvases(id),
+    from_x INT NOT NULL,
+    from_y INT NOT NULL,
+    to_x INT NOT NULL,
+    to_y INT NOT NULL,
+    outline INT NOT NULL,
+    created_at TIMESTAMP NOT NULL
+);
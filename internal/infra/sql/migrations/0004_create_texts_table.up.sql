@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS texts (
+    id UUID PRIMARY KEY,
+    canvas_id UUID REFERENCES canvases(id),
+    x INT NOT NULL,
+    y INT NOT NULL,
+    text TEXT NOT NULL,
+    wrap_width INT NOT NULL,
+    alignment TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL
+);
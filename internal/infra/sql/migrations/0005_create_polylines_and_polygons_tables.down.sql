@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS polygons;
+DROP TABLE IF EXISTS polylines;
@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS polylines (
+    id UUID PRIMARY KEY,
+    canvas_id UUID REFERENCES canvases(id),
+    points JSONB NOT NULL,
+    outline INT NOT NULL,
+    created_at TIMESTAMP NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS polygons (
+    id UUID PRIMARY KEY,
+    canvas_id UUID REFERENCES canvases(id),
+    points JSONB NOT NULL,
+    filler INT NOT NULL,
+    outline INT NOT NULL,
+    created_at TIMESTAMP NOT NULL
+);
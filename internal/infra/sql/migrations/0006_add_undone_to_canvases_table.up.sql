@@ -0,0 +1 @@
+ALTER TABLE canvases ADD COLUMN IF NOT EXISTS undone INT NOT NULL DEFAULT 0;
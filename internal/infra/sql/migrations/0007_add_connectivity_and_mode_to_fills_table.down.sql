@@ -0,0 +1,3 @@
+ALTER TABLE fills DROP COLUMN IF EXISTS boundary;
+ALTER TABLE fills DROP COLUMN IF EXISTS mode;
+ALTER TABLE fills DROP COLUMN IF EXISTS connectivity;
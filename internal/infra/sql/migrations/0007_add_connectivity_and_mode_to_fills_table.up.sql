@@ -0,0 +1,3 @@
+ALTER TABLE fills ADD COLUMN IF NOT EXISTS connectivity INT NOT NULL DEFAULT 4;
+ALTER TABLE fills ADD COLUMN IF NOT EXISTS mode TEXT NOT NULL DEFAULT 'flood';
+ALTER TABLE fills ADD COLUMN IF NOT EXISTS boundary INT NOT NULL DEFAULT 0;
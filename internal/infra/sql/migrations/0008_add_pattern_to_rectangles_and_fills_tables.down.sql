@@ -0,0 +1,2 @@
+ALTER TABLE fills DROP COLUMN IF EXISTS pattern;
+ALTER TABLE rectangles DROP COLUMN IF EXISTS pattern;
@@ -0,0 +1,2 @@
+ALTER TABLE rectangles ADD COLUMN IF NOT EXISTS pattern JSONB NOT NULL DEFAULT '{}';
+ALTER TABLE fills ADD COLUMN IF NOT EXISTS pattern JSONB NOT NULL DEFAULT '{}';
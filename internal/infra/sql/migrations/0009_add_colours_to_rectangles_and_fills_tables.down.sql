@@ -0,0 +1,6 @@
+ALTER TABLE fills DROP COLUMN IF EXISTS background;
+ALTER TABLE fills DROP COLUMN IF EXISTS foreground;
+ALTER TABLE rectangles DROP COLUMN IF EXISTS outline_background;
+ALTER TABLE rectangles DROP COLUMN IF EXISTS outline_foreground;
+ALTER TABLE rectangles DROP COLUMN IF EXISTS filler_background;
+ALTER TABLE rectangles DROP COLUMN IF EXISTS filler_foreground;
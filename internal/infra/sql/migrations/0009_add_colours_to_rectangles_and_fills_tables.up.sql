@@ -0,0 +1,6 @@
+ALTER TABLE rectangles ADD COLUMN IF NOT EXISTS filler_foreground TEXT NOT NULL DEFAULT '';
+ALTER TABLE rectangles ADD COLUMN IF NOT EXISTS filler_background TEXT NOT NULL DEFAULT '';
+ALTER TABLE rectangles ADD COLUMN IF NOT EXISTS outline_foreground TEXT NOT NULL DEFAULT '';
+ALTER TABLE rectangles ADD COLUMN IF NOT EXISTS outline_background TEXT NOT NULL DEFAULT '';
+ALTER TABLE fills ADD COLUMN IF NOT EXISTS foreground TEXT NOT NULL DEFAULT '';
+ALTER TABLE fills ADD COLUMN IF NOT EXISTS background TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS snapshots (
+    canvas_id UUID PRIMARY KEY REFERENCES canvases(id),
+    tasks INT NOT NULL,
+    digest TEXT NOT NULL,
+    cells JSONB NOT NULL,
+    created_at TIMESTAMP NOT NULL
+);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS event_snapshots;
+DROP TABLE IF EXISTS events;
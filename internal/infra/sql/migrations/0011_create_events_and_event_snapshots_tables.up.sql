@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS events (
+    canvas_id UUID NOT NULL,
+    sequence INT NOT NULL,
+    type TEXT NOT NULL,
+    payload JSONB NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (canvas_id, sequence)
+);
+
+CREATE TABLE IF NOT EXISTS event_snapshots (
+    canvas_id UUID PRIMARY KEY,
+    sequence INT NOT NULL,
+    state JSONB NOT NULL,
+    created_at TIMESTAMP NOT NULL
+);
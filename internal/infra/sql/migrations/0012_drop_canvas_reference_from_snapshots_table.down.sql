@@ -0,0 +1,2 @@
+DELETE FROM snapshots WHERE canvas_id NOT IN (SELECT id FROM canvases);
+ALTER TABLE snapshots ADD CONSTRAINT snapshots_canvas_id_fkey FOREIGN KEY (canvas_id) REFERENCES canvases(id);
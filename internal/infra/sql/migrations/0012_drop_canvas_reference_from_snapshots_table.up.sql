@@ -0,0 +1 @@
+ALTER TABLE snapshots DROP CONSTRAINT IF EXISTS snapshots_canvas_id_fkey;
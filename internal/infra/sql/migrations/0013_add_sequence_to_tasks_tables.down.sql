@@ -0,0 +1,7 @@
+ALTER TABLE rectangles DROP COLUMN IF EXISTS sequence;
+ALTER TABLE fills DROP COLUMN IF EXISTS sequence;
+ALTER TABLE lines DROP COLUMN IF EXISTS sequence;
+ALTER TABLE ellipses DROP COLUMN IF EXISTS sequence;
+ALTER TABLE texts DROP COLUMN IF EXISTS sequence;
+ALTER TABLE polylines DROP COLUMN IF EXISTS sequence;
+ALTER TABLE polygons DROP COLUMN IF EXISTS sequence;
@@ -0,0 +1,31 @@
+ALTER TABLE rectangles ADD COLUMN IF NOT EXISTS sequence INT NOT NULL DEFAULT 0;
+ALTER TABLE fills ADD COLUMN IF NOT EXISTS sequence INT NOT NULL DEFAULT 0;
+ALTER TABLE lines ADD COLUMN IF NOT EXISTS sequence INT NOT NULL DEFAULT 0;
+ALTER TABLE ellipses ADD COLUMN IF NOT EXISTS sequence INT NOT NULL DEFAULT 0;
+ALTER TABLE texts ADD COLUMN IF NOT EXISTS sequence INT NOT NULL DEFAULT 0;
+ALTER TABLE polylines ADD COLUMN IF NOT EXISTS sequence INT NOT NULL DEFAULT 0;
+ALTER TABLE polygons ADD COLUMN IF NOT EXISTS sequence INT NOT NULL DEFAULT 0;
+
+-- The tasks of each canvas are numbered by creation time. Tasks created at the same time are numbered as the first
+-- version of the service read them, with the fills before the rectangles, and then by table
+CREATE TEMPORARY TABLE task_sequences AS
+SELECT id, ROW_NUMBER() OVER (PARTITION BY canvas_id ORDER BY created_at, kind, id) AS sequence
+FROM (
+    SELECT id, canvas_id, created_at, 1 AS kind FROM fills
+    UNION ALL SELECT id, canvas_id, created_at, 2 AS kind FROM rectangles
+    UNION ALL SELECT id, canvas_id, created_at, 3 AS kind FROM lines
+    UNION ALL SELECT id, canvas_id, created_at, 4 AS kind FROM ellipses
+    UNION ALL SELECT id, canvas_id, created_at, 5 AS kind FROM texts
+    UNION ALL SELECT id, canvas_id, created_at, 6 AS kind FROM polylines
+    UNION ALL SELECT id, canvas_id, created_at, 7 AS kind FROM polygons
+) AS tasks;
+
+UPDATE rectangles SET sequence = (SELECT sequence FROM task_sequences WHERE task_sequences.id = rectangles.id);
+UPDATE fills SET sequence = (SELECT sequence FROM task_sequences WHERE task_sequences.id = fills.id);
+UPDATE lines SET sequence = (SELECT sequence FROM task_sequences WHERE task_sequences.id = lines.id);
+UPDATE ellipses SET sequence = (SELECT sequence FROM task_sequences WHERE task_sequences.id = ellipses.id);
+UPDATE texts SET sequence = (SELECT sequence FROM task_sequences WHERE task_sequences.id = texts.id);
+UPDATE polylines SET sequence = (SELECT sequence FROM task_sequences WHERE task_sequences.id = polylines.id);
+UPDATE polygons SET sequence = (SELECT sequence FROM task_sequences WHERE task_sequences.id = polygons.id);
+
+DROP TABLE task_sequences;
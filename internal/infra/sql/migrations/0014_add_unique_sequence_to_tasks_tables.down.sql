@@ -0,0 +1,7 @@
+ALTER TABLE rectangles DROP CONSTRAINT IF EXISTS rectangles_canvas_id_sequence_key;
+ALTER TABLE fills DROP CONSTRAINT IF EXISTS fills_canvas_id_sequence_key;
+ALTER TABLE lines DROP CONSTRAINT IF EXISTS lines_canvas_id_sequence_key;
+ALTER TABLE ellipses DROP CONSTRAINT IF EXISTS ellipses_canvas_id_sequence_key;
+ALTER TABLE texts DROP CONSTRAINT IF EXISTS texts_canvas_id_sequence_key;
+ALTER TABLE polylines DROP CONSTRAINT IF EXISTS polylines_canvas_id_sequence_key;
+ALTER TABLE polygons DROP CONSTRAINT IF EXISTS polygons_canvas_id_sequence_key;
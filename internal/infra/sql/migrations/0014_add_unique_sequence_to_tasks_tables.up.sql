@@ -0,0 +1,7 @@
+ALTER TABLE rectangles ADD CONSTRAINT rectangles_canvas_id_sequence_key UNIQUE (canvas_id, sequence);
+ALTER TABLE fills ADD CONSTRAINT fills_canvas_id_sequence_key UNIQUE (canvas_id, sequence);
+ALTER TABLE lines ADD CONSTRAINT lines_canvas_id_sequence_key UNIQUE (canvas_id, sequence);
+ALTER TABLE ellipses ADD CONSTRAINT ellipses_canvas_id_sequence_key UNIQUE (canvas_id, sequence);
+ALTER TABLE texts ADD CONSTRAINT texts_canvas_id_sequence_key UNIQUE (canvas_id, sequence);
+ALTER TABLE polylines ADD CONSTRAINT polylines_canvas_id_sequence_key UNIQUE (canvas_id, sequence);
+ALTER TABLE polygons ADD CONSTRAINT polygons_canvas_id_sequence_key UNIQUE (canvas_id, sequence);
@@ -0,0 +1,38 @@
+// +build integration
+
+package sql_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	sqlx "github.com/maitesin/sketch/internal/infra/sql"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewMigrator(t *testing.T) {
+	session, dbCloser := setup(t)
+	defer dbCloser()
+
+	migrator, err := sqlx.NewMigrator(session)
+	require.NoError(t, err)
+
+	statuses, err := migrator.Status(context.Background())
+	require.NoError(t, err)
+	for _, status := range statuses {
+		require.True(t, status.Applied)
+	}
+
+	// revert all the migrations, and apply them again
+	for range statuses {
+		_, err = migrator.Down(context.Background())
+		require.NoError(t, err)
+	}
+
+	applied, err := migrator.Up(context.Background())
+	require.NoError(t, err)
+	require.Len(t, applied, len(statuses))
+
+	fixtures(t, session, uuid.New(), uuid.New(), uuid.New())
+}
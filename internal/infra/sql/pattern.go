@@ -0,0 +1,42 @@
+package sql
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+
+	"github.com/maitesin/sketch/internal/domain"
+)
+
+// Pattern is stored as a JSON object in a single column. Tasks without pattern store an empty object
+type Pattern struct {
+	Tile   []string `json:"tile,omitempty"`
+	Anchor string   `json:"anchor,omitempty"`
+}
+
+func (p Pattern) Value() (driver.Value, error) {
+	b, err := json.Marshal(p)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+func (p *Pattern) Scan(src interface{}) error {
+	switch src := src.(type) {
+	case []byte:
+		return json.Unmarshal(src, p)
+	case string:
+		return json.Unmarshal([]byte(src), p)
+	default:
+		return fmt.Errorf("failed to scan pattern from %T", src)
+	}
+}
+
+func domainToSQLPattern(pattern domain.Pattern) Pattern {
+	return Pattern{Tile: pattern.Tile(), Anchor: string(pattern.Anchor())}
+}
+
+func sqlToDomainPattern(pattern Pattern) domain.Pattern {
+	return domain.NewPattern(pattern.Tile, domain.PatternAnchor(pattern.Anchor))
+}
@@ -0,0 +1,35 @@
+package sql_test
+
+import (
+	"testing"
+
+	sqlx "github.com/maitesin/sketch/internal/infra/sql"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPattern_ValueAndScan(t *testing.T) {
+	t.Parallel()
+
+	pattern := sqlx.Pattern{Tile: []string{"/\\", "\\/"}, Anchor: "shape"}
+
+	value, err := pattern.Value()
+	require.NoError(t, err)
+
+	var fromString sqlx.Pattern
+	err = fromString.Scan(value)
+	require.NoError(t, err)
+	require.Equal(t, pattern, fromString)
+
+	var fromBytes sqlx.Pattern
+	err = fromBytes.Scan([]byte(value.(string)))
+	require.NoError(t, err)
+	require.Equal(t, pattern, fromBytes)
+
+	empty, err := sqlx.Pattern{}.Value()
+	require.NoError(t, err)
+	require.Equal(t, "{}", empty)
+
+	var fromInt sqlx.Pattern
+	err = fromInt.Scan(42)
+	require.Error(t, err)
+}
@@ -0,0 +1,52 @@
+package sql
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+
+	"github.com/maitesin/sketch/internal/domain"
+)
+
+type Point struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
+// Points is stored as a JSON array in a single column
+type Points []Point
+
+func (p Points) Value() (driver.Value, error) {
+	b, err := json.Marshal(p)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+func (p *Points) Scan(src interface{}) error {
+	switch src := src.(type) {
+	case []byte:
+		return json.Unmarshal(src, p)
+	case string:
+		return json.Unmarshal([]byte(src), p)
+	default:
+		return fmt.Errorf("failed to scan points from %T", src)
+	}
+}
+
+func domainToSQLPoints(points []domain.Point) Points {
+	sqlPoints := make(Points, len(points))
+	for i := range points {
+		sqlPoints[i] = Point{X: points[i].X(), Y: points[i].Y()}
+	}
+	return sqlPoints
+}
+
+func sqlToDomainPoints(sqlPoints Points) []domain.Point {
+	points := make([]domain.Point, len(sqlPoints))
+	for i := range sqlPoints {
+		points[i] = domain.NewPoint(sqlPoints[i].X, sqlPoints[i].Y)
+	}
+	return points
+}
@@ -0,0 +1,31 @@
+package sql_test
+
+import (
+	"testing"
+
+	sqlx "github.com/maitesin/sketch/internal/infra/sql"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPoints_ValueAndScan(t *testing.T) {
+	t.Parallel()
+
+	points := sqlx.Points{{X: 1, Y: 2}, {X: 3, Y: 4}}
+
+	value, err := points.Value()
+	require.NoError(t, err)
+
+	var fromString sqlx.Points
+	err = fromString.Scan(value)
+	require.NoError(t, err)
+	require.Equal(t, points, fromString)
+
+	var fromBytes sqlx.Points
+	err = fromBytes.Scan([]byte(value.(string)))
+	require.NoError(t, err)
+	require.Equal(t, points, fromBytes)
+
+	var fromInt sqlx.Points
+	err = fromInt.Scan(42)
+	require.Error(t, err)
+}
@@ -0,0 +1,83 @@
+package sql
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/maitesin/sketch/internal/infra/raster"
+	"github.com/upper/db/v4"
+)
+
+const snapshotsTable = "snapshots"
+
+type Snapshot struct {
+	CanvasID  uuid.UUID `db:"canvas_id"`
+	Tasks     int       `db:"tasks"`
+	Digest    string    `db:"digest"`
+	Cells     Cells     `db:"cells"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+// SnapshotRepository stores the latest snapshot of each canvas
+type SnapshotRepository struct {
+	sess db.Session
+}
+
+func NewSnapshotRepository(sess db.Session) *SnapshotRepository {
+	return &SnapshotRepository{sess: sess}
+}
+
+// Save stores the snapshot as the latest one of the canvas, replacing the previous one
+func (s *SnapshotRepository) Save(ctx context.Context, canvasID uuid.UUID, snapshot raster.Snapshot) error {
+	_, err := s.sess.WithContext(ctx).
+		SQL().
+		InsertInto(snapshotsTable).
+		Values(Snapshot{
+			CanvasID:  canvasID,
+			Tasks:     snapshot.Tasks(),
+			Digest:    snapshot.Digest(),
+			Cells:     rasterToSQLCells(snapshot),
+			CreatedAt: time.Now().UTC(),
+		}).
+		Amend(onConflictUpdateSnapshot).
+		Exec()
+	return err
+}
+
+func onConflictUpdateSnapshot(queryIn string) string {
+	return queryIn + ` ON CONFLICT (canvas_id) DO UPDATE SET tasks = EXCLUDED.tasks, digest = EXCLUDED.digest, ` +
+		`cells = EXCLUDED.cells, created_at = EXCLUDED.created_at`
+}
+
+// FindByCanvasID returns the latest snapshot of the canvas, or raster.ErrSnapshotNotFound when it has none
+func (s *SnapshotRepository) FindByCanvasID(ctx context.Context, canvasID uuid.UUID) (raster.Snapshot, error) {
+	var sqlSnapshot Snapshot
+	err := s.sess.WithContext(ctx).
+		Collection(snapshotsTable).
+		Find(db.Cond{"canvas_id": canvasID}).
+		One(&sqlSnapshot)
+	if err != nil {
+		if err == db.ErrNoMoreRows {
+			return raster.Snapshot{}, raster.ErrSnapshotNotFound
+		}
+		return raster.Snapshot{}, err
+	}
+
+	grid, styles, owners, err := sqlCellsToRaster(sqlSnapshot.Cells)
+	if err != nil {
+		return raster.Snapshot{}, err
+	}
+
+	return raster.RestoreSnapshot(sqlSnapshot.Tasks, sqlSnapshot.Digest, grid, styles, owners), nil
+}
+
+// Delete removes the snapshot of the canvas
+func (s *SnapshotRepository) Delete(ctx context.Context, canvasID uuid.UUID) error {
+	_, err := s.sess.WithContext(ctx).
+		SQL().
+		DeleteFrom(snapshotsTable).
+		Where(db.Cond{"canvas_id": canvasID}).
+		Exec()
+	return err
+}
@@ -0,0 +1,58 @@
+// +build integration
+
+package sql_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/maitesin/sketch/internal/domain"
+	"github.com/maitesin/sketch/internal/infra/raster"
+	sqlx "github.com/maitesin/sketch/internal/infra/sql"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSnapshotRepository(t *testing.T) {
+	session, dbCloser := setup(t)
+	defer dbCloser()
+
+	canvasID := uuid.New()
+	fixtures(t, session, canvasID, uuid.New(), uuid.New())
+
+	canvases := sqlx.NewCanvasRepository(session)
+	canvas, err := canvases.FindByID(context.Background(), canvasID)
+	require.NoError(t, err)
+
+	repository := sqlx.NewSnapshotRepository(session)
+
+	_, err = repository.FindByCanvasID(context.Background(), canvasID)
+	require.ErrorIs(t, err, raster.ErrSnapshotNotFound)
+
+	first, err := raster.NewSnapshot(domain.NewCanvas(canvasID, canvas.Height(), canvas.Width(), canvas.Tasks()[:1], canvas.CreatedAt()))
+	require.NoError(t, err)
+	err = repository.Save(context.Background(), canvasID, first)
+	require.NoError(t, err)
+
+	snapshot, err := repository.FindByCanvasID(context.Background(), canvasID)
+	require.NoError(t, err)
+	require.Equal(t, first, snapshot)
+
+	second, err := raster.NewSnapshot(canvas)
+	require.NoError(t, err)
+	err = repository.Save(context.Background(), canvasID, second)
+	require.NoError(t, err)
+
+	snapshot, err = repository.FindByCanvasID(context.Background(), canvasID)
+	require.NoError(t, err)
+	require.Equal(t, second, snapshot)
+
+	_, found := snapshot.Resume(canvas)
+	require.True(t, found)
+
+	err = repository.Delete(context.Background(), canvasID)
+	require.NoError(t, err)
+
+	_, err = repository.FindByCanvasID(context.Background(), canvasID)
+	require.ErrorIs(t, err, raster.ErrSnapshotNotFound)
+}
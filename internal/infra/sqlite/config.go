@@ -0,0 +1,6 @@
+package sqlite
+
+// Config defines the SQLite DB configuration
+type Config struct {
+	Path string
+}
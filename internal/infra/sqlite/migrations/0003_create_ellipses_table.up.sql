@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS ellipses (
+    id TEXT PRIMARY KEY,
+    canvas_id TEXT REFERENCES canvases(id),
+    x INT NOT NULL,
+    y INT NOT NULL,
+    height INT NOT NULL,
+    width INT NOT NULL,
+    filler INT NOT NULL,
+    outline INT NOT NULL,
+    created_at TIMESTAMP NOT NULL
+);
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS texts (
+    id TEXT PRIMARY KEY,
+    canvas_id TEXT REFERENCES canvases(id),
+    x INT NOT NULL,
+    y INT NOT NULL,
+    text TEXT NOT NULL,
+    wrap_width INT NOT NULL,
+    alignment TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL
+);
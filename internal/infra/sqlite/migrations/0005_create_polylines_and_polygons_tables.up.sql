@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS polylines (
+    id TEXT PRIMARY KEY,
+    canvas_id TEXT REFERENCES canvases(id),
+    points TEXT NOT NULL,
+    outline INT NOT NULL,
+    created_at TIMESTAMP NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS polygons (
+    id TEXT PRIMARY KEY,
+    canvas_id TEXT REFERENCES canvases(id),
+    points TEXT NOT NULL,
+    filler INT NOT NULL,
+    outline INT NOT NULL,
+    created_at TIMESTAMP NOT NULL
+);
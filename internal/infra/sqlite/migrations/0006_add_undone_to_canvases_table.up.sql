@@ -0,0 +1 @@
+ALTER TABLE canvases ADD COLUMN undone INT NOT NULL DEFAULT 0;
@@ -0,0 +1,3 @@
+ALTER TABLE fills DROP COLUMN boundary;
+ALTER TABLE fills DROP COLUMN mode;
+ALTER TABLE fills DROP COLUMN connectivity;
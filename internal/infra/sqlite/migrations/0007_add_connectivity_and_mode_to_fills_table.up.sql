@@ -0,0 +1,3 @@
+ALTER TABLE fills ADD COLUMN connectivity INT NOT NULL DEFAULT 4;
+ALTER TABLE fills ADD COLUMN mode TEXT NOT NULL DEFAULT 'flood';
+ALTER TABLE fills ADD COLUMN boundary INT NOT NULL DEFAULT 0;
@@ -0,0 +1,2 @@
+ALTER TABLE fills DROP COLUMN pattern;
+ALTER TABLE rectangles DROP COLUMN pattern;
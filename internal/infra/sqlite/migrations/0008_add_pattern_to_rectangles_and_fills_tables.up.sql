@@ -0,0 +1,2 @@
+ALTER TABLE rectangles ADD COLUMN pattern TEXT NOT NULL DEFAULT '{}';
+ALTER TABLE fills ADD COLUMN pattern TEXT NOT NULL DEFAULT '{}';
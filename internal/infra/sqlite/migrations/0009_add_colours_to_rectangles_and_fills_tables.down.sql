@@ -0,0 +1,6 @@
+ALTER TABLE fills DROP COLUMN background;
+ALTER TABLE fills DROP COLUMN foreground;
+ALTER TABLE rectangles DROP COLUMN outline_background;
+ALTER TABLE rectangles DROP COLUMN outline_foreground;
+ALTER TABLE rectangles DROP COLUMN filler_background;
+ALTER TABLE rectangles DROP COLUMN filler_foreground;
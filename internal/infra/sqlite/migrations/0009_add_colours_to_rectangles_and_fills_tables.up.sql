@@ -0,0 +1,6 @@
+ALTER TABLE rectangles ADD COLUMN filler_foreground TEXT NOT NULL DEFAULT '';
+ALTER TABLE rectangles ADD COLUMN filler_background TEXT NOT NULL DEFAULT '';
+ALTER TABLE rectangles ADD COLUMN outline_foreground TEXT NOT NULL DEFAULT '';
+ALTER TABLE rectangles ADD COLUMN outline_background TEXT NOT NULL DEFAULT '';
+ALTER TABLE fills ADD COLUMN foreground TEXT NOT NULL DEFAULT '';
+ALTER TABLE fills ADD COLUMN background TEXT NOT NULL DEFAULT '';
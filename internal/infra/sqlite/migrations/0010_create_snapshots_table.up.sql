@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS snapshots (
+    canvas_id TEXT PRIMARY KEY REFERENCES canvases(id),
+    tasks INT NOT NULL,
+    digest TEXT NOT NULL,
+    cells TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL
+);
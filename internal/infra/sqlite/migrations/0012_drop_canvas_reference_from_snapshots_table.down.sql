@@ -0,0 +1,12 @@
+CREATE TABLE snapshots_with_reference (
+    canvas_id TEXT PRIMARY KEY REFERENCES canvases(id),
+    tasks INT NOT NULL,
+    digest TEXT NOT NULL,
+    cells TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL
+);
+
+INSERT INTO snapshots_with_reference SELECT canvas_id, tasks, digest, cells, created_at FROM snapshots
+WHERE canvas_id IN (SELECT id FROM canvases);
+DROP TABLE snapshots;
+ALTER TABLE snapshots_with_reference RENAME TO snapshots;
@@ -0,0 +1,11 @@
+CREATE TABLE snapshots_without_reference (
+    canvas_id TEXT PRIMARY KEY,
+    tasks INT NOT NULL,
+    digest TEXT NOT NULL,
+    cells TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL
+);
+
+INSERT INTO snapshots_without_reference SELECT canvas_id, tasks, digest, cells, created_at FROM snapshots;
+DROP TABLE snapshots;
+ALTER TABLE snapshots_without_reference RENAME TO snapshots;
@@ -0,0 +1,7 @@
+ALTER TABLE rectangles DROP COLUMN sequence;
+ALTER TABLE fills DROP COLUMN sequence;
+ALTER TABLE lines DROP COLUMN sequence;
+ALTER TABLE ellipses DROP COLUMN sequence;
+ALTER TABLE texts DROP COLUMN sequence;
+ALTER TABLE polylines DROP COLUMN sequence;
+ALTER TABLE polygons DROP COLUMN sequence;
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS rectangles_canvas_id_sequence_key;
+DROP INDEX IF EXISTS fills_canvas_id_sequence_key;
+DROP INDEX IF EXISTS lines_canvas_id_sequence_key;
+DROP INDEX IF EXISTS ellipses_canvas_id_sequence_key;
+DROP INDEX IF EXISTS texts_canvas_id_sequence_key;
+DROP INDEX IF EXISTS polylines_canvas_id_sequence_key;
+DROP INDEX IF EXISTS polygons_canvas_id_sequence_key;
@@ -0,0 +1,7 @@
+CREATE UNIQUE INDEX IF NOT EXISTS rectangles_canvas_id_sequence_key ON rectangles (canvas_id, sequence);
+CREATE UNIQUE INDEX IF NOT EXISTS fills_canvas_id_sequence_key ON fills (canvas_id, sequence);
+CREATE UNIQUE INDEX IF NOT EXISTS lines_canvas_id_sequence_key ON lines (canvas_id, sequence);
+CREATE UNIQUE INDEX IF NOT EXISTS ellipses_canvas_id_sequence_key ON ellipses (canvas_id, sequence);
+CREATE UNIQUE INDEX IF NOT EXISTS texts_canvas_id_sequence_key ON texts (canvas_id, sequence);
+CREATE UNIQUE INDEX IF NOT EXISTS polylines_canvas_id_sequence_key ON polylines (canvas_id, sequence);
+CREATE UNIQUE INDEX IF NOT EXISTS polygons_canvas_id_sequence_key ON polygons (canvas_id, sequence);
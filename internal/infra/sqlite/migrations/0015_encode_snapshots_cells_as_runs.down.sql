@@ -0,0 +1,2 @@
+-- The cells of the snapshots are stored with the previous encoding. Snapshots only save work, so the new ones are dropped
+DELETE FROM snapshots;
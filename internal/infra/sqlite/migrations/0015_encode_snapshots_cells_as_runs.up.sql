@@ -0,0 +1,2 @@
+-- The cells of the snapshots are stored with a new encoding. Snapshots only save work, so the old ones are dropped
+DELETE FROM snapshots;
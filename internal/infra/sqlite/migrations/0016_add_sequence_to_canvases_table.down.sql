@@ -0,0 +1 @@
+ALTER TABLE canvases DROP COLUMN sequence;
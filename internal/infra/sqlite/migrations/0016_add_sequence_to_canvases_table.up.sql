@@ -0,0 +1,14 @@
+ALTER TABLE canvases ADD COLUMN sequence INT NOT NULL DEFAULT 0;
+
+-- The canvases start with the highest sequence of their tasks, since the sequences of the tasks removed are unknown
+UPDATE canvases SET sequence = COALESCE((
+    SELECT MAX(sequence) FROM (
+        SELECT sequence FROM rectangles WHERE rectangles.canvas_id = canvases.id
+        UNION ALL SELECT sequence FROM fills WHERE fills.canvas_id = canvases.id
+        UNION ALL SELECT sequence FROM lines WHERE lines.canvas_id = canvases.id
+        UNION ALL SELECT sequence FROM ellipses WHERE ellipses.canvas_id = canvases.id
+        UNION ALL SELECT sequence FROM texts WHERE texts.canvas_id = canvases.id
+        UNION ALL SELECT sequence FROM polylines WHERE polylines.canvas_id = canvases.id
+        UNION ALL SELECT sequence FROM polygons WHERE polygons.canvas_id = canvases.id
+    ) AS tasks
+), 0);
@@ -0,0 +1,79 @@
+// Package sqlite opens the embedded SQLite DB used to store the canvas information in single node deployments. The DB
+// is used through the same upper/db abstraction as PostgreSQL, so the SQL repositories work with both of them
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"io/fs"
+
+	"github.com/maitesin/sketch/internal/infra/migration"
+	"github.com/upper/db/v4"
+	"github.com/upper/db/v4/adapter/sqlite"
+	_ "modernc.org/sqlite" // Pure Go SQLite driver, so the project builds without cgo
+)
+
+const driverName = "sqlite"
+
+//go:embed migrations/*.sql
+var migrations embed.FS
+
+// Open opens the SQLite DB stored in the path of the configuration, creating it when it does not exist
+func Open(cfg Config) (db.Session, error) {
+	sqlDB, err := sql.Open(driverName, cfg.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	// SQLite only allows a writer at a time, and the foreign keys are enabled for each connection
+	sqlDB.SetMaxOpenConns(1)
+
+	sess, err := sqlite.New(sqlDB)
+	if err != nil {
+		sqlDB.Close()
+		return nil, err
+	}
+
+	_, err = sess.SQL().Exec(`PRAGMA foreign_keys = ON`)
+	if err != nil {
+		sess.Close()
+		return nil, err
+	}
+
+	return sess, nil
+}
+
+// NewMigrator returns the runner of the migrations of the SQLite DB, which are embedded in the binary. DBs created
+// before the migrations applied were tracked kept their version in the user_version pragma, so the migrations up to
+// it are marked as applied
+func NewMigrator(ctx context.Context, sess db.Session) (*migration.Runner, error) {
+	files, err := fs.Sub(migrations, "migrations")
+	if err != nil {
+		return nil, err
+	}
+
+	runner, err := migration.NewRunner(sess, files)
+	if err != nil {
+		return nil, err
+	}
+
+	var version int
+	row, err := sess.WithContext(ctx).SQL().QueryRow(`PRAGMA user_version`)
+	if err != nil {
+		return nil, err
+	}
+	err = row.Scan(&version)
+	if err != nil {
+		return nil, err
+	}
+
+	if version > 0 {
+		err = runner.Baseline(ctx, version)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return runner, nil
+}
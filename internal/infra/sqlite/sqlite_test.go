@@ -0,0 +1,365 @@
+package sqlite_test
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/maitesin/sketch/internal/app"
+	"github.com/maitesin/sketch/internal/app/apptest"
+	"github.com/maitesin/sketch/internal/domain"
+	"github.com/maitesin/sketch/internal/infra/migration"
+	"github.com/maitesin/sketch/internal/infra/raster"
+	sqlx "github.com/maitesin/sketch/internal/infra/sql"
+	"github.com/maitesin/sketch/internal/infra/sqlite"
+	"github.com/stretchr/testify/require"
+	"github.com/upper/db/v4"
+)
+
+func setup(t *testing.T) db.Session {
+	t.Helper()
+
+	sess, err := sqlite.Open(sqlite.Config{Path: filepath.Join(t.TempDir(), "sketch.db")})
+	require.NoError(t, err)
+	t.Cleanup(func() { sess.Close() })
+
+	migrator, err := sqlite.NewMigrator(context.Background(), sess)
+	require.NoError(t, err)
+	_, err = migrator.Up(context.Background())
+	require.NoError(t, err)
+
+	return sess
+}
+
+func TestCanvasRepository(t *testing.T) {
+	apptest.TestCanvasRepository(t, func(t *testing.T) app.CanvasRepository {
+		return sqlx.NewCanvasRepository(setup(t))
+	})
+}
+
+func TestEventCanvasRepository(t *testing.T) {
+	for _, snapshotInterval := range []int{0, 3} {
+		snapshotInterval := snapshotInterval
+		t.Run(fmt.Sprintf("SnapshotInterval%d", snapshotInterval), func(t *testing.T) {
+			apptest.TestCanvasRepository(t, func(t *testing.T) app.CanvasRepository {
+				return sqlx.NewEventCanvasRepository(setup(t), snapshotInterval)
+			})
+		})
+	}
+}
+
+func TestCanvasRepository_ManyTasks(t *testing.T) {
+	repositories := map[string]func(db.Session) app.CanvasRepository{
+		"Tables": func(sess db.Session) app.CanvasRepository { return sqlx.NewCanvasRepository(sess) },
+		"Events": func(sess db.Session) app.CanvasRepository { return sqlx.NewEventCanvasRepository(sess, 0) },
+	}
+	for name, repository := range repositories {
+		repository := repository
+		t.Run(name, func(t *testing.T) {
+			repository := repository(setup(t))
+
+			canvas := domain.NewCanvas(uuid.New(), 100, 100, nil, time.Now().UTC())
+			err := repository.Insert(context.Background(), canvas)
+			require.NoError(t, err)
+
+			// the rows and the IDs of the tasks do not fit in the parameters of a single statement
+			ids := make([]uuid.UUID, 5000)
+			for i := range ids {
+				ids[i] = uuid.New()
+				err = canvas.AddDrawRectangle(domain.NewDrawRectangle(ids[i], domain.NewPoint(i%90, i%90), 10, 10, '.', '#', time.Now().UTC()))
+				require.NoError(t, err)
+			}
+			err = repository.Update(context.Background(), canvas)
+			require.NoError(t, err)
+
+			canvas, err = repository.FindByID(context.Background(), canvas.ID())
+			require.NoError(t, err)
+			require.Len(t, canvas.History(), len(ids))
+
+			for _, id := range ids[:4000] {
+				err = canvas.RemoveTask(id)
+				require.NoError(t, err)
+			}
+			err = repository.Update(context.Background(), canvas)
+			require.NoError(t, err)
+
+			canvas, err = repository.FindByID(context.Background(), canvas.ID())
+			require.NoError(t, err)
+			require.Len(t, canvas.History(), 1000)
+		})
+	}
+}
+
+func TestEventCanvasRepository_Events(t *testing.T) {
+	sess := setup(t)
+	repository := sqlx.NewEventCanvasRepository(sess, 4)
+
+	canvas := domain.NewCanvas(uuid.New(), 10, 10, nil, time.Now().UTC())
+	err := repository.Insert(context.Background(), canvas)
+	require.NoError(t, err)
+
+	line := domain.NewDrawLine(uuid.New(), domain.NewPoint(0, 0), domain.NewPoint(4, 4), '\\', time.Now().UTC())
+	err = canvas.AddDrawLine(line)
+	require.NoError(t, err)
+	err = canvas.AddFill(domain.NewFill(uuid.New(), domain.NewPoint(9, 0), '.', time.Now().UTC()))
+	require.NoError(t, err)
+	err = repository.Update(context.Background(), canvas)
+	require.NoError(t, err)
+
+	err = canvas.Resize(12, 12, domain.AnchorBottomRight, false)
+	require.NoError(t, err)
+	err = canvas.RemoveTask(line.ID())
+	require.NoError(t, err)
+	err = canvas.Undo()
+	require.NoError(t, err)
+	err = repository.Update(context.Background(), canvas)
+	require.NoError(t, err)
+
+	// nothing changed, so no event is appended
+	err = repository.Update(context.Background(), canvas)
+	require.NoError(t, err)
+
+	var events []sqlx.Event
+	err = sess.Collection("events").Find(db.Cond{"canvas_id": canvas.ID()}).OrderBy("sequence").All(&events)
+	require.NoError(t, err)
+
+	types := make([]sqlx.EventType, len(events))
+	for i, event := range events {
+		require.Equal(t, i+1, event.Sequence)
+		types[i] = event.Type
+	}
+	require.Equal(t, []sqlx.EventType{
+		sqlx.EventCanvasCreated,
+		sqlx.EventTaskAdded,
+		sqlx.EventTaskAdded,
+		sqlx.EventCanvasResized,
+		sqlx.EventTaskRemoved,
+		sqlx.EventTaskEdited,
+		sqlx.EventHistoryMoved,
+	}, types)
+
+	var eventSnapshot sqlx.EventSnapshot
+	err = sess.Collection("event_snapshots").Find(db.Cond{"canvas_id": canvas.ID()}).One(&eventSnapshot)
+	require.NoError(t, err)
+	require.Equal(t, 7, eventSnapshot.Sequence)
+
+	stored, err := repository.FindByID(context.Background(), canvas.ID())
+	require.NoError(t, err)
+	require.Equal(t, 12, stored.Height())
+	require.Equal(t, 12, stored.Width())
+	require.Len(t, stored.History(), 1)
+	require.Equal(t, 0, stored.Cursor())
+
+	// the canvases stored as events have no row in the canvases table, and their renderings are snapshotted too
+	snapshot, err := raster.NewSnapshot(stored)
+	require.NoError(t, err)
+	err = sqlx.NewSnapshotRepository(sess).Save(context.Background(), canvas.ID(), snapshot)
+	require.NoError(t, err)
+
+	err = repository.Update(context.Background(), domain.NewCanvas(uuid.New(), 10, 10, nil, time.Now().UTC()))
+	require.ErrorAs(t, err, &app.CanvasNotFound{})
+}
+
+func TestEventCanvasRepository_StaleCanvas(t *testing.T) {
+	repository := sqlx.NewEventCanvasRepository(setup(t), 0)
+
+	rectangle := domain.NewDrawRectangle(uuid.New(), domain.NewPoint(0, 0), 3, 3, '.', '#', time.Now().UTC())
+	line := domain.NewDrawLine(uuid.New(), domain.NewPoint(0, 0), domain.NewPoint(9, 9), '\\', time.Now().UTC())
+	canvas := domain.NewCanvas(uuid.New(), 10, 10, []domain.Task{rectangle, line}, time.Now().UTC())
+	err := repository.Insert(context.Background(), canvas)
+	require.NoError(t, err)
+	err = repository.Update(context.Background(), canvas)
+	require.NoError(t, err)
+
+	stale, err := repository.FindByID(context.Background(), canvas.ID())
+	require.NoError(t, err)
+
+	// the rectangle is edited and the line, the last task, removed after the stale canvas was retrieved
+	found, err := repository.FindByID(context.Background(), canvas.ID())
+	require.NoError(t, err)
+	edited := domain.NewDrawRectangle(rectangle.ID(), domain.NewPoint(5, 5), 3, 3, '.', '#', time.Now().UTC())
+	err = found.UpdateTask(edited)
+	require.NoError(t, err)
+	err = found.RemoveTask(line.ID())
+	require.NoError(t, err)
+	err = repository.Update(context.Background(), found)
+	require.NoError(t, err)
+
+	err = stale.Resize(12, 12, domain.AnchorTopLeft, false)
+	require.NoError(t, err)
+	err = repository.Update(context.Background(), stale)
+	require.NoError(t, err)
+
+	stored, err := repository.FindByID(context.Background(), canvas.ID())
+	require.NoError(t, err)
+	require.Equal(t, 12, stored.Height())
+	require.Len(t, stored.History(), 1)
+	storedRectangle, ok := stored.History()[0].(domain.DrawRectangle)
+	require.True(t, ok)
+	require.Equal(t, edited.Point(), storedRectangle.Point())
+
+	// the task removed cannot be edited either
+	err = stale.UpdateTask(domain.NewDrawLine(line.ID(), domain.NewPoint(0, 9), domain.NewPoint(9, 0), '/', time.Now().UTC()))
+	require.NoError(t, err)
+	err = repository.Update(context.Background(), stale)
+	require.ErrorAs(t, err, &app.CanvasConflict{})
+}
+
+func TestSnapshotRepository(t *testing.T) {
+	sess := setup(t)
+
+	canvas := domain.NewCanvas(uuid.New(), 5, 5, nil, time.Now().UTC())
+	err := canvas.AddDrawLine(domain.NewDrawLine(uuid.New(), domain.NewPoint(0, 0), domain.NewPoint(4, 4), '\\', time.Now().UTC()))
+	require.NoError(t, err)
+
+	canvases := sqlx.NewCanvasRepository(sess)
+	err = canvases.Insert(context.Background(), canvas)
+	require.NoError(t, err)
+
+	repository := sqlx.NewSnapshotRepository(sess)
+
+	_, err = repository.FindByCanvasID(context.Background(), canvas.ID())
+	require.ErrorIs(t, err, raster.ErrSnapshotNotFound)
+
+	snapshot, err := raster.NewSnapshot(canvas)
+	require.NoError(t, err)
+	err = repository.Save(context.Background(), canvas.ID(), snapshot)
+	require.NoError(t, err)
+	err = repository.Save(context.Background(), canvas.ID(), snapshot)
+	require.NoError(t, err)
+
+	found, err := repository.FindByCanvasID(context.Background(), canvas.ID())
+	require.NoError(t, err)
+	require.Equal(t, snapshot, found)
+
+	err = repository.Delete(context.Background(), canvas.ID())
+	require.NoError(t, err)
+
+	_, err = repository.FindByCanvasID(context.Background(), canvas.ID())
+	require.ErrorIs(t, err, raster.ErrSnapshotNotFound)
+}
+
+func TestSnapshotRepository_LargeCanvas(t *testing.T) {
+	sess := setup(t)
+
+	canvas := domain.NewCanvas(uuid.New(), 1000, 1000, nil, time.Now().UTC())
+	err := canvas.AddDrawRectangle(domain.NewDrawRectangle(uuid.New(), domain.NewPoint(10, 10), 500, 500, '.', '#', time.Now().UTC()))
+	require.NoError(t, err)
+	err = sqlx.NewCanvasRepository(sess).Insert(context.Background(), canvas)
+	require.NoError(t, err)
+
+	snapshot, err := raster.NewSnapshot(canvas)
+	require.NoError(t, err)
+	repository := sqlx.NewSnapshotRepository(sess)
+	err = repository.Save(context.Background(), canvas.ID(), snapshot)
+	require.NoError(t, err)
+
+	// the cells take little more than a byte each, since the styles and the owners of consecutive cells are shared
+	var size int
+	row, err := sess.SQL().QueryRow(`SELECT LENGTH(cells) FROM snapshots WHERE canvas_id = ?`, canvas.ID())
+	require.NoError(t, err)
+	err = row.Scan(&size)
+	require.NoError(t, err)
+	require.Less(t, size, 1100*1000)
+
+	found, err := repository.FindByCanvasID(context.Background(), canvas.ID())
+	require.NoError(t, err)
+	require.Equal(t, snapshot, found)
+}
+
+func TestNewMigrator(t *testing.T) {
+	sess := setup(t)
+
+	migrator, err := sqlite.NewMigrator(context.Background(), sess)
+	require.NoError(t, err)
+
+	statuses, err := migrator.Status(context.Background())
+	require.NoError(t, err)
+	require.NotEmpty(t, statuses)
+
+	// revert all the migrations, and apply them again
+	for range statuses {
+		_, err = migrator.Down(context.Background())
+		require.NoError(t, err)
+	}
+	_, err = migrator.Down(context.Background())
+	require.ErrorIs(t, err, migration.ErrNoMigrationApplied)
+
+	applied, err := migrator.Up(context.Background())
+	require.NoError(t, err)
+	require.Len(t, applied, len(statuses))
+
+	canvas := domain.NewCanvas(uuid.New(), 5, 5, nil, time.Now().UTC())
+	err = sqlx.NewCanvasRepository(sess).Insert(context.Background(), canvas)
+	require.NoError(t, err)
+}
+
+func TestNewMigrator_TasksSequence(t *testing.T) {
+	sess := setup(t)
+
+	migrator, err := sqlite.NewMigrator(context.Background(), sess)
+	require.NoError(t, err)
+
+	// revert the migrations up to the one that adds the sequence of the tasks, and store the tasks without it
+	for {
+		reverted, err := migrator.Down(context.Background())
+		require.NoError(t, err)
+		if reverted.Name == "add_sequence_to_tasks_tables" {
+			break
+		}
+	}
+
+	canvasID, rectangleID, fillID, lineID := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	createdAt := time.Now().UTC()
+	queries := []struct {
+		query string
+		args  []interface{}
+	}{
+		{`INSERT INTO canvases (id, height, width, created_at) VALUES (?, 30, 30, ?)`, []interface{}{canvasID, createdAt}},
+		{`INSERT INTO lines (id, canvas_id, from_x, from_y, to_x, to_y, outline, created_at) VALUES (?, ?, 0, 0, 5, 5, 42, ?)`, []interface{}{lineID, canvasID, createdAt.Add(-time.Second)}},
+		{`INSERT INTO rectangles (id, canvas_id, x, y, height, width, filler, outline, created_at) VALUES (?, ?, 0, 0, 3, 3, 46, 35, ?)`, []interface{}{rectangleID, canvasID, createdAt}},
+		{`INSERT INTO fills (id, canvas_id, x, y, filler, created_at) VALUES (?, ?, 1, 1, 45, ?)`, []interface{}{fillID, canvasID, createdAt}},
+	}
+	for _, q := range queries {
+		_, err = sess.SQL().Exec(q.query, q.args...)
+		require.NoError(t, err)
+	}
+
+	_, err = migrator.Up(context.Background())
+	require.NoError(t, err)
+
+	// the tasks are ordered by creation time, and the fill goes before the rectangle created at the same time
+	canvas, err := sqlx.NewCanvasRepository(sess).FindByID(context.Background(), canvasID)
+	require.NoError(t, err)
+	ids := make([]uuid.UUID, len(canvas.Tasks()))
+	sequences := make([]int, len(canvas.Tasks()))
+	for i, task := range canvas.Tasks() {
+		ids[i], _ = domain.TaskID(task)
+		sequences[i], _ = domain.TaskSequence(task)
+	}
+	require.Equal(t, []uuid.UUID{lineID, fillID, rectangleID}, ids)
+	require.Equal(t, []int{1, 2, 3}, sequences)
+}
+
+func TestNewMigrator_UserVersion(t *testing.T) {
+	sess, err := sqlite.Open(sqlite.Config{Path: filepath.Join(t.TempDir(), "sketch.db")})
+	require.NoError(t, err)
+	defer sess.Close()
+
+	// DBs created before the migrations were tracked have the schema of their version applied
+	_, err = sess.SQL().Exec(`CREATE TABLE canvases (id TEXT PRIMARY KEY, height INT NOT NULL, width INT NOT NULL, created_at TIMESTAMP NOT NULL)`)
+	require.NoError(t, err)
+	_, err = sess.SQL().Exec(`PRAGMA user_version = 1`)
+	require.NoError(t, err)
+
+	migrator, err := sqlite.NewMigrator(context.Background(), sess)
+	require.NoError(t, err)
+
+	statuses, err := migrator.Status(context.Background())
+	require.NoError(t, err)
+	require.True(t, statuses[0].Applied)
+	require.False(t, statuses[1].Applied)
+}
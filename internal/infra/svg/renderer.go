@@ -0,0 +1,264 @@
+package svg
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/maitesin/sketch/internal/domain"
+	"github.com/maitesin/sketch/internal/infra/raster"
+)
+
+const (
+	// cellSize is the side in pixels of the square drawn for each cell of the canvas
+	cellSize = 10
+	half     = cellSize / 2
+
+	background = "#ffffff"
+	foreground = "#000000"
+)
+
+// palette contains the colours used to draw the runes, so the same rune is always drawn with the same colour
+var palette = []string{
+	"#1f77b4", "#ff7f0e", "#2ca02c", "#d62728", "#9467bd",
+	"#8c564b", "#e377c2", "#7f7f7f", "#bcbd22", "#17becf",
+}
+
+// colour returns the colour to draw the rune with. Spaces erase what is below them, so they use the background colour
+func colour(r rune) string {
+	if r == ' ' {
+		return background
+	}
+	return palette[int(r)%len(palette)]
+}
+
+// Renderer draws each task of the canvas as native SVG shapes. The tasks are also drawn in a raster, so the regions
+// covered by the fills are the same ones as in the other renderers. The viewport is the view box of the image, so the
+// shapes outside of it are cropped by the SVG viewers
+type Renderer struct{}
+
+func (Renderer) Render(writer io.Writer, c domain.Canvas, viewport raster.Viewport) error {
+	if err := viewport.Validate(c); err != nil {
+		return err
+	}
+	x, y := viewport.X*cellSize, viewport.Y*cellSize
+	width, height := viewport.Width*cellSize, viewport.Height*cellSize
+
+	var buffer bytes.Buffer
+	fmt.Fprintf(&buffer,
+		`<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="%d %d %d %d" shape-rendering="crispEdges">`+"\n",
+		width, height, x, y, width, height)
+	fmt.Fprintf(&buffer, `<rect x="%d" y="%d" width="%d" height="%d" fill="%s"/>`+"\n", x, y, width, height, background)
+
+	grid := raster.NewGrid(c.Height(), c.Width())
+	for _, task := range c.Tasks() {
+		switch task := task.(type) {
+		case domain.DrawRectangle:
+			drawRectangle(&buffer, task)
+		case domain.Fill:
+			addFill(&buffer, task, visibleSpans(grid.FillSpans(task), viewport))
+		case domain.DrawLine:
+			drawLine(&buffer, task)
+		case domain.DrawEllipse:
+			drawEllipse(&buffer, task)
+		case domain.DrawText:
+			drawText(&buffer, task)
+		case domain.DrawPolyline:
+			drawPolyline(&buffer, task)
+		case domain.DrawPolygon:
+			drawPolygon(&buffer, task)
+		case raster.Snapshot:
+			drawSnapshot(&buffer, task, viewport)
+		}
+
+		if err := grid.Draw(task); err != nil {
+			return err
+		}
+	}
+	buffer.WriteString("</svg>\n")
+
+	_, err := buffer.WriteTo(writer)
+	return err
+}
+
+// drawSnapshot draws the cells of the viewport in the snapshot with a rectangle for each run of cells with the same
+// rune, because the shapes of the tasks drawn in it are not known anymore. The blank cells are left as the background
+func drawSnapshot(buffer *bytes.Buffer, snapshot raster.Snapshot, viewport raster.Viewport) {
+	grid := snapshot.Grid()
+	for y := viewport.Y; y < viewport.Y+viewport.Height && y < len(grid); y++ {
+		right := min(viewport.X+viewport.Width, len(grid[y]))
+		for x := viewport.X; x < right; {
+			r, start := grid[y][x], x
+			for x < right && grid[y][x] == r {
+				x++
+			}
+			if r == ' ' {
+				continue
+			}
+			fmt.Fprintf(buffer, `<rect x="%d" y="%d" width="%d" height="%d" fill="%s"/>`+"\n",
+				start*cellSize, y*cellSize, (x-start)*cellSize, cellSize, colour(r))
+		}
+	}
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// center returns the coordinate in pixels of the center of a cell
+func center(cell int) int {
+	return cell*cellSize + half
+}
+
+func drawRectangle(buffer *bytes.Buffer, rectangle domain.DrawRectangle) {
+	x, y := rectangle.Point().X(), rectangle.Point().Y()
+	height, width := rectangle.Height(), rectangle.Width()
+	if height <= 0 || width <= 0 {
+		return
+	}
+
+	// Rectangles one cell thick have no inside, and their outline cannot be drawn as a stroke
+	if height == 1 || width == 1 {
+		fmt.Fprintf(buffer, `<rect x="%d" y="%d" width="%d" height="%d" fill="%s"/>`+"\n",
+			x*cellSize, y*cellSize, width*cellSize, height*cellSize, colour(rectangle.Outline()))
+		return
+	}
+
+	// The stroke is as wide as a cell and centered in the outline cells, so it covers them completely
+	fill := paint(buffer, rectangle.ID(), rectangle.Filler(), rectangle.Pattern(), rectangle.Point())
+	fmt.Fprintf(buffer, `<rect x="%d" y="%d" width="%d" height="%d" fill="%s" stroke="%s" stroke-width="%d"/>`+"\n",
+		center(x), center(y), (width-1)*cellSize, (height-1)*cellSize, fill, colour(rectangle.Outline()), cellSize)
+}
+
+// visibleSpans returns the spans that are in the rows of the viewport
+func visibleSpans(spans []raster.Span, viewport raster.Viewport) []raster.Span {
+	visible := spans[:0]
+	for _, span := range spans {
+		if span.Y >= viewport.Y && span.Y < viewport.Y+viewport.Height &&
+			span.Right >= viewport.X && span.Left < viewport.X+viewport.Width {
+			visible = append(visible, span)
+		}
+	}
+	return visible
+}
+
+// addFill draws the cells covered by the fill as a single path, with a rectangle for each run of cells
+func addFill(buffer *bytes.Buffer, fill domain.Fill, spans []raster.Span) {
+	if len(spans) == 0 {
+		return
+	}
+
+	var path strings.Builder
+	for _, span := range spans {
+		fmt.Fprintf(&path, "M%d %dh%dv%dh%dz",
+			span.Left*cellSize, span.Y*cellSize, (span.Right-span.Left+1)*cellSize, cellSize, -(span.Right-span.Left+1)*cellSize)
+	}
+
+	value := paint(buffer, fill.ID(), fill.Filler(), fill.Pattern(), fill.Point())
+	fmt.Fprintf(buffer, `<path d="%s" fill="%s"/>`+"\n", path.String(), value)
+}
+
+// paint returns the value of the fill attribute for the filler, or for the pattern when it is not empty. Patterns
+// are defined as SVG patterns that are referenced by the ID of the task
+func paint(buffer *bytes.Buffer, id fmt.Stringer, filler rune, pattern domain.Pattern, origin domain.Point) string {
+	if pattern.IsEmpty() {
+		return colour(filler)
+	}
+
+	tile := pattern.Tile()
+	x, y := 0, 0
+	if pattern.Anchor() == domain.PatternAnchorShape {
+		x, y = origin.X()*cellSize, origin.Y()*cellSize
+	}
+
+	fmt.Fprintf(buffer,
+		`<defs><pattern id="pattern-%s" patternUnits="userSpaceOnUse" x="%d" y="%d" width="%d" height="%d">`,
+		id, x, y, len([]rune(tile[0]))*cellSize, len(tile)*cellSize)
+	for i, row := range tile {
+		for j, r := range []rune(row) {
+			fmt.Fprintf(buffer, `<rect x="%d" y="%d" width="%d" height="%d" fill="%s"/>`,
+				j*cellSize, i*cellSize, cellSize, cellSize, colour(r))
+		}
+	}
+	buffer.WriteString("</pattern></defs>\n")
+
+	return fmt.Sprintf("url(#pattern-%s)", id)
+}
+
+func drawLine(buffer *bytes.Buffer, line domain.DrawLine) {
+	fmt.Fprintf(buffer,
+		`<line x1="%d" y1="%d" x2="%d" y2="%d" stroke="%s" stroke-width="%d" stroke-linecap="square"/>`+"\n",
+		center(line.From().X()), center(line.From().Y()), center(line.To().X()), center(line.To().Y()),
+		colour(line.Outline()), cellSize)
+}
+
+func drawEllipse(buffer *bytes.Buffer, ellipse domain.DrawEllipse) {
+	x, y := ellipse.Point().X(), ellipse.Point().Y()
+	height, width := ellipse.Height(), ellipse.Width()
+	if height <= 0 || width <= 0 {
+		return
+	}
+
+	// Ellipses one cell thick are straight lines, and SVG does not draw ellipses without one of its radii
+	if height == 1 || width == 1 {
+		fmt.Fprintf(buffer, `<rect x="%d" y="%d" width="%d" height="%d" fill="%s"/>`+"\n",
+			x*cellSize, y*cellSize, width*cellSize, height*cellSize, colour(ellipse.Outline()))
+		return
+	}
+
+	fmt.Fprintf(buffer,
+		`<ellipse cx="%d" cy="%d" rx="%d" ry="%d" fill="%s" stroke="%s" stroke-width="%d"/>`+"\n",
+		x*cellSize+width*half, y*cellSize+height*half, (width-1)*half, (height-1)*half,
+		colour(ellipse.Filler()), colour(ellipse.Outline()), cellSize)
+}
+
+// drawText draws each line of the text on top of a rectangle with the background colour, because the spaces of
+// the text erase what is below them. Each character is placed in the center of its cell
+func drawText(buffer *bytes.Buffer, text domain.DrawText) {
+	for _, line := range text.Lines() {
+		runes := []rune(line.Text())
+		if len(runes) == 0 {
+			continue
+		}
+
+		x, y := line.Point().X(), line.Point().Y()
+		positions := make([]string, len(runes))
+		for i := range runes {
+			positions[i] = fmt.Sprint(center(x + i))
+		}
+
+		fmt.Fprintf(buffer, `<rect x="%d" y="%d" width="%d" height="%d" fill="%s"/>`+"\n",
+			x*cellSize, y*cellSize, len(runes)*cellSize, cellSize, background)
+		fmt.Fprintf(buffer,
+			`<text x="%s" y="%d" fill="%s" font-family="monospace" font-size="%d" text-anchor="middle" dominant-baseline="central" xml:space="preserve">`,
+			strings.Join(positions, " "), center(y), foreground, cellSize)
+		_ = xml.EscapeText(buffer, []byte(line.Text()))
+		buffer.WriteString("</text>\n")
+	}
+}
+
+func drawPolyline(buffer *bytes.Buffer, polyline domain.DrawPolyline) {
+	fmt.Fprintf(buffer,
+		`<polyline points="%s" fill="none" stroke="%s" stroke-width="%d" stroke-linecap="square" stroke-linejoin="miter"/>`+"\n",
+		points(polyline.Points()), colour(polyline.Outline()), cellSize)
+}
+
+func drawPolygon(buffer *bytes.Buffer, polygon domain.DrawPolygon) {
+	fmt.Fprintf(buffer,
+		`<polygon points="%s" fill="%s" stroke="%s" stroke-width="%d" stroke-linejoin="miter"/>`+"\n",
+		points(polygon.Points()), colour(polygon.Filler()), colour(polygon.Outline()), cellSize)
+}
+
+// points returns the centers of the cells of the points in the format of the points attribute
+func points(points []domain.Point) string {
+	coordinates := make([]string, len(points))
+	for i, point := range points {
+		coordinates[i] = fmt.Sprintf("%d,%d", center(point.X()), center(point.Y()))
+	}
+	return strings.Join(coordinates, " ")
+}
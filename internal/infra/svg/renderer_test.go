@@ -0,0 +1,174 @@
+package svg_test
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/maitesin/sketch/internal/domain"
+	"github.com/maitesin/sketch/internal/infra/raster"
+	"github.com/maitesin/sketch/internal/infra/svg"
+	"github.com/stretchr/testify/require"
+)
+
+func canvasFixture1() domain.Canvas {
+	return domain.NewCanvas(
+		uuid.New(),
+		4,
+		5,
+		[]domain.Task{
+			domain.NewDrawRectangle(uuid.New(), domain.NewPoint(0, 0), 3, 3, '.', '@', time.Now().UTC()),
+			domain.NewFill(uuid.New(), domain.NewPoint(4, 3), '-', time.Now().UTC()),
+		},
+		time.Now().UTC(),
+	)
+}
+
+func outputFixture1() string {
+	return `<svg xmlns="http://www.w3.org/2000/svg" width="50" height="40" viewBox="0 0 50 40" shape-rendering="crispEdges">
+<rect x="0" y="0" width="50" height="40" fill="#ffffff"/>
+<rect x="5" y="5" width="20" height="20" fill="#e377c2" stroke="#9467bd" stroke-width="10"/>
+<path d="M0 30h50v10h-50zM30 20h20v10h-20zM30 10h20v10h-20zM30 0h20v10h-20z" fill="#8c564b"/>
+</svg>
+`
+}
+
+// canvasFixture1FromSnapshot returns the canvas of the fixture 1 where the rectangle is replaced by a snapshot
+func canvasFixture1FromSnapshot(t *testing.T) domain.Canvas {
+	t.Helper()
+
+	canvas := canvasFixture1()
+	snapshot, err := raster.NewSnapshot(domain.NewCanvas(canvas.ID(), canvas.Height(), canvas.Width(), canvas.Tasks()[:1], canvas.CreatedAt()))
+	require.NoError(t, err)
+
+	resumed, ok := snapshot.Resume(canvas)
+	require.True(t, ok)
+	return resumed
+}
+
+func outputFixture1FromSnapshot() string {
+	return `<svg xmlns="http://www.w3.org/2000/svg" width="50" height="40" viewBox="0 0 50 40" shape-rendering="crispEdges">
+<rect x="0" y="0" width="50" height="40" fill="#ffffff"/>
+<rect x="0" y="0" width="30" height="10" fill="#9467bd"/>
+<rect x="0" y="10" width="10" height="10" fill="#9467bd"/>
+<rect x="10" y="10" width="10" height="10" fill="#e377c2"/>
+<rect x="20" y="10" width="10" height="10" fill="#9467bd"/>
+<rect x="0" y="20" width="30" height="10" fill="#9467bd"/>
+<path d="M0 30h50v10h-50zM30 20h20v10h-20zM30 10h20v10h-20zM30 0h20v10h-20z" fill="#8c564b"/>
+</svg>
+`
+}
+
+func canvasWithAllTasks() domain.Canvas {
+	return domain.NewCanvas(
+		uuid.New(),
+		20,
+		30,
+		[]domain.Task{
+			domain.NewDrawRectangle(uuid.New(), domain.NewPoint(0, 0), 5, 8, ' ', '#', time.Now().UTC()).
+				WithPattern(domain.NewPattern([]string{"/\\"}, domain.PatternAnchorShape)),
+			domain.NewDrawRectangle(uuid.New(), domain.NewPoint(10, 0), 1, 5, 'x', 'x', time.Now().UTC()),
+			domain.NewFill(uuid.New(), domain.NewPoint(29, 19), '~', time.Now().UTC()).
+				WithPattern(domain.NewPattern([]string{"xo", "ox"}, domain.PatternAnchorCanvas)),
+			domain.NewDrawLine(uuid.New(), domain.NewPoint(0, 19), domain.NewPoint(19, 0), '*', time.Now().UTC()),
+			domain.NewDrawEllipse(uuid.New(), domain.NewPoint(12, 6), 5, 7, '.', 'O', time.Now().UTC()),
+			domain.NewDrawText(uuid.New(), domain.NewPoint(1, 12), "<b> & \"quotes\"", 8, domain.AlignCenter, time.Now().UTC()),
+			domain.NewDrawPolyline(uuid.New(), []domain.Point{domain.NewPoint(20, 1), domain.NewPoint(25, 6), domain.NewPoint(29, 1)}, '+', time.Now().UTC()),
+			domain.NewDrawPolygon(uuid.New(), []domain.Point{domain.NewPoint(20, 12), domain.NewPoint(28, 12), domain.NewPoint(24, 18)}, ':', '%', time.Now().UTC()),
+		},
+		time.Now().UTC(),
+	)
+}
+
+func invalidCanvas() domain.Canvas {
+	return domain.NewCanvas(
+		uuid.New(),
+		10,
+		10,
+		[]domain.Task{"this will fail"},
+		time.Now().UTC(),
+	)
+}
+
+func TestRenderer(t *testing.T) {
+	tests := []struct {
+		name           string
+		canvas         domain.Canvas
+		expectedOutput string
+		expectedErr    error
+	}{
+		{
+			name: `Given a canvas with a rectangle and a fill around it,
+                   when the render method is called from the SVG renderer,
+                   then it outputs the rectangle with stroke and fill, and the filled cells as a single path`,
+			canvas:         canvasFixture1(),
+			expectedOutput: outputFixture1(),
+		},
+		{
+			name: `Given a canvas with a snapshot of a rectangle and a fill around it,
+                   when the render method is called from the SVG renderer,
+                   then it outputs the cells of the snapshot as runs of squares, and the filled cells as a single path`,
+			canvas:         canvasFixture1FromSnapshot(t),
+			expectedOutput: outputFixture1FromSnapshot(),
+		},
+		{
+			name: `Given a canvas with an invalid task,
+                   when the render method is called from the SVG renderer,
+                   then it will return an error of invalid task`,
+			canvas:      invalidCanvas(),
+			expectedErr: raster.ErrInvalidTask,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			re := svg.Renderer{}
+			writer := &bytes.Buffer{}
+			err := re.Render(writer, tt.canvas, raster.FullViewport(tt.canvas))
+			if tt.expectedErr != nil {
+				require.ErrorAs(t, err, &tt.expectedErr)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tt.expectedOutput, writer.String())
+			}
+		})
+	}
+}
+
+func TestRenderer_WellFormed(t *testing.T) {
+	t.Parallel()
+
+	re := svg.Renderer{}
+	writer := &bytes.Buffer{}
+	canvas := canvasWithAllTasks()
+	err := re.Render(writer, canvas, raster.FullViewport(canvas))
+	require.NoError(t, err)
+
+	elements := map[string]int{}
+	decoder := xml.NewDecoder(writer)
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		require.NoError(t, err)
+		if start, ok := token.(xml.StartElement); ok {
+			elements[start.Name.Local]++
+		}
+	}
+
+	require.Equal(t, 1, elements["svg"])
+	require.Equal(t, 2, elements["pattern"])
+	require.Equal(t, 1, elements["path"])
+	require.Equal(t, 1, elements["line"])
+	require.Equal(t, 1, elements["ellipse"])
+	require.Equal(t, 2, elements["text"])
+	require.Equal(t, 1, elements["polyline"])
+	require.Equal(t, 1, elements["polygon"])
+}